@@ -0,0 +1,125 @@
+package blob
+
+import (
+	apperrors "blog-api/app/errors"
+	"blog-api/app/i18n"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ErrNotFound indica que no hay un archivo guardado con la clave pedida
+var ErrNotFound = apperrors.New(apperrors.ErrNotFound, "blob not found")
+
+// ErrInvalidKey indica que la clave no es un path relativo válido
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// Store guarda archivos identificados por una clave como photos/abc.jpg
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Disk guarda los archivos en un directorio del disco local
+type Disk struct {
+	root string
+}
+
+// NewDisk crea el store en root, creando el directorio si no existe
+func NewDisk(root string) (*Disk, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("can´t create blob directory %s: %w", root, err)
+	}
+	return &Disk{root: root}, nil
+}
+
+// Put guarda el contenido de r en key. El archivo aparece completo o no aparece.
+func (d *Disk) Put(ctx context.Context, key string, r io.Reader) error {
+	name, err := d.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+// Open abre el archivo guardado en key
+func (d *Disk) Open(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	name, err := d.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, i18n.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return file, err
+}
+
+// Delete borra el archivo guardado en key; no es un error que no exista
+func (d *Disk) Delete(ctx context.Context, key string) error {
+	name, err := d.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path devuelve el archivo de key, que no puede salir del directorio del store
+func (d *Disk) path(key string) (string, error) {
+	clean := path.Clean("/" + key)[1:]
+	if key == "" || clean != key || strings.HasPrefix(path.Base(key), ".") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return filepath.Join(d.root, filepath.FromSlash(clean)), nil
+}
+
+// Handler sirve los archivos de store; la clave es el resto del path de la ruta
+// (p. ej. /files/*). Las claves no se reutilizan, así que se cachean sin vencimiento.
+func Handler(store Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := chi.URLParam(r, "*")
+		file, err := store.Open(r.Context(), key)
+		if errors.Is(err, ErrInvalidKey) {
+			err = ErrNotFound
+		}
+		if err != nil {
+			apperrors.Write(w, r, err, apperrors.ErrInternal)
+			return
+		}
+		defer file.Close()
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		http.ServeContent(w, r, path.Base(key), time.Time{}, file)
+	}
+}
@@ -0,0 +1,73 @@
+package blob
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisk(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	store, err := NewDisk(filepath.Join(root, "uploads"))
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put(ctx, "photos/a.jpg", strings.NewReader("image")))
+	file, err := store.Open(ctx, "photos/a.jpg")
+	require.NoError(t, err)
+	content, err := io.ReadAll(file)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	require.Equal(t, "image", string(content))
+
+	// Put reemplaza el archivo y no deja temporales
+	require.NoError(t, store.Put(ctx, "photos/a.jpg", strings.NewReader("other")))
+	entries, err := os.ReadDir(filepath.Join(root, "uploads", "photos"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	require.NoError(t, store.Delete(ctx, "photos/a.jpg"))
+	require.NoError(t, store.Delete(ctx, "photos/a.jpg"))
+	_, err = store.Open(ctx, "photos/a.jpg")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestDisk_InvalidKey(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewDisk(t.TempDir())
+	require.NoError(t, err)
+	for _, key := range []string{"", "../a.jpg", "/a.jpg", "photos/../../a.jpg", "photos//a.jpg", ".hidden", "photos/"} {
+		require.ErrorIs(t, store.Put(ctx, key, strings.NewReader("x")), ErrInvalidKey, key)
+		_, err := store.Open(ctx, key)
+		require.ErrorIs(t, err, ErrInvalidKey, key)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	store, err := NewDisk(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, store.Put(context.Background(), "photos/a.txt", strings.NewReader("hello")))
+	r := chi.NewRouter()
+	r.Get("/files/*", Handler(store))
+
+	mockRecorder := httptest.NewRecorder()
+	r.ServeHTTP(mockRecorder, httptest.NewRequest(http.MethodGet, "/files/photos/a.txt", nil))
+	require.Equal(t, http.StatusOK, mockRecorder.Code)
+	require.Equal(t, "hello", mockRecorder.Body.String())
+	require.Contains(t, mockRecorder.Header().Get("Content-Type"), "text/plain")
+	require.Contains(t, mockRecorder.Header().Get("Cache-Control"), "immutable")
+
+	for _, path := range []string{"/files/photos/b.txt", "/files/photos/.a.txt", "/files/"} {
+		mockRecorder = httptest.NewRecorder()
+		r.ServeHTTP(mockRecorder, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusNotFound, mockRecorder.Code, path)
+	}
+}
@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Entry es una respuesta del upstream guardada en la caché
+type Entry struct {
+	Status   int         `json:"status"`
+	Header   http.Header `json:"header"`
+	Body     []byte      `json:"body"`
+	StoredAt time.Time   `json:"storedAt"`
+}
+
+// Cache es un almacén de respuestas por clave. La implementación en memoria es
+// LRU; un backend tipo Redis solo necesita serializar Entry y expirar por ttl.
+type Cache interface {
+	// Get devuelve la entrada de key si todavía no expiró
+	Get(ctx context.Context, key string) (Entry, bool)
+	// Set guarda la entrada durante ttl
+	Set(ctx context.Context, key string, entry Entry, ttl time.Duration)
+	// DeletePrefix elimina key y todas las claves que la extienden con "/" o "?"
+	DeletePrefix(ctx context.Context, key string)
+}
+
+// Policy define cuánto tiempo es fresca una entrada de un recurso y cuánto más
+// se puede servir vencida mientras se revalida en segundo plano
+type Policy struct {
+	TTL   time.Duration
+	Stale time.Duration
+}
+
+// Fresh indica si la entrada todavía es fresca según la política
+func (p Policy) Fresh(entry Entry, now time.Time) bool {
+	return now.Before(entry.StoredAt.Add(p.TTL))
+}
+
+// Lifetime es el tiempo total que la entrada se guarda en la caché
+func (p Policy) Lifetime() time.Duration {
+	return p.TTL + p.Stale
+}
+
+// Status es el resultado de la caché para una solicitud, informado en X-Cache
+type Status string
+
+const (
+	Hit   Status = "HIT"
+	Miss  Status = "MISS"
+	Stale Status = "STALE"
+)
+
+type statusKey struct{}
+
+type statusHolder struct {
+	mu     sync.Mutex
+	status Status
+}
+
+// SetStatus registra el resultado de la caché en el contexto de la solicitud.
+// Si varias consultas pasan por la caché, gana el peor resultado.
+func SetStatus(ctx context.Context, status Status) {
+	holder, ok := ctx.Value(statusKey{}).(*statusHolder)
+	if !ok {
+		return
+	}
+	holder.mu.Lock()
+	defer holder.mu.Unlock()
+	if holder.status == "" || holder.status == Hit || status == Miss {
+		holder.status = status
+	}
+}
+
+// Report agrega el header X-Cache con el resultado registrado por el servicio
+func Report(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		holder := &statusHolder{}
+		ctx := context.WithValue(r.Context(), statusKey{}, holder)
+		next.ServeHTTP(&reportWriter{ResponseWriter: w, holder: holder}, r.WithContext(ctx))
+	})
+}
+
+type reportWriter struct {
+	http.ResponseWriter
+	holder      *statusHolder
+	wroteHeader bool
+}
+
+func (w *reportWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.holder.mu.Lock()
+		if w.holder.status != "" {
+			w.Header().Set("X-Cache", string(w.holder.status))
+		}
+		w.holder.mu.Unlock()
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *reportWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *reportWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
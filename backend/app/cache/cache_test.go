@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRU_Evicts(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(2)
+	c.Set(ctx, "a", Entry{Body: []byte("a")}, time.Minute)
+	c.Set(ctx, "b", Entry{Body: []byte("b")}, time.Minute)
+	_, ok := c.Get(ctx, "a")
+	require.True(t, ok)
+	c.Set(ctx, "c", Entry{Body: []byte("c")}, time.Minute)
+
+	_, ok = c.Get(ctx, "b")
+	require.False(t, ok, "b was the least recently used")
+	entry, ok := c.Get(ctx, "a")
+	require.True(t, ok)
+	require.Equal(t, []byte("a"), entry.Body)
+	require.Equal(t, 2, c.Len())
+}
+
+func TestLRU_Expires(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := NewLRU(10)
+	c.now = func() time.Time { return now }
+	c.Set(ctx, "a", Entry{}, time.Minute)
+	now = now.Add(time.Minute)
+	_, ok := c.Get(ctx, "a")
+	require.False(t, ok)
+	require.Equal(t, 0, c.Len())
+}
+
+func TestLRU_DeletePrefix(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(10)
+	for _, key := range []string{"https://h/posts", "https://h/posts?userId=1", "https://h/posts/1", "https://h/postsx", "https://h/users/1"} {
+		c.Set(ctx, key, Entry{}, time.Minute)
+	}
+	c.DeletePrefix(ctx, "https://h/posts")
+	require.Equal(t, 2, c.Len())
+	_, ok := c.Get(ctx, "https://h/postsx")
+	require.True(t, ok)
+}
+
+func TestPolicy_Fresh(t *testing.T) {
+	now := time.Now()
+	policy := Policy{TTL: time.Minute, Stale: time.Hour}
+	require.True(t, policy.Fresh(Entry{StoredAt: now}, now.Add(59*time.Second)))
+	require.False(t, policy.Fresh(Entry{StoredAt: now}, now.Add(time.Minute)))
+	require.Equal(t, time.Hour+time.Minute, policy.Lifetime())
+}
+
+func TestReport(t *testing.T) {
+	handler := func(statuses ...Status) http.Handler {
+		return Report(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, status := range statuses {
+				SetStatus(r.Context(), status)
+			}
+			w.Write([]byte("ok"))
+		}))
+	}
+	serve := func(h http.Handler) *httptest.ResponseRecorder {
+		mockRecorder := httptest.NewRecorder()
+		h.ServeHTTP(mockRecorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		return mockRecorder
+	}
+	require.Equal(t, "HIT", serve(handler(Hit)).Header().Get("X-Cache"))
+	require.Equal(t, "MISS", serve(handler(Hit, Miss, Stale)).Header().Get("X-Cache"))
+	require.Equal(t, "STALE", serve(handler(Hit, Stale)).Header().Get("X-Cache"))
+	require.Empty(t, serve(handler()).Header().Get("X-Cache"))
+}
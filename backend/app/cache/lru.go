@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSize es la cantidad de entradas de la caché en memoria por defecto
+const DefaultSize = 1000
+
+// LRU es una caché en memoria con un máximo de entradas; al llenarse descarta
+// la usada hace más tiempo
+type LRU struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+	now     func() time.Time
+}
+
+type lruItem struct {
+	key       string
+	entry     Entry
+	expiresAt time.Time
+}
+
+// NewLRU crea una caché en memoria de hasta size entradas
+func NewLRU(size int) *LRU {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	return &LRU{
+		size:    size,
+		order:   list.New(),
+		entries: map[string]*list.Element{},
+		now:     time.Now,
+	}
+}
+
+// Get devuelve la entrada de key si todavía no expiró
+func (c *LRU) Get(ctx context.Context, key string) (Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return Entry{}, false
+	}
+	item := element.Value.(*lruItem)
+	if !c.now().Before(item.expiresAt) {
+		c.remove(element)
+		return Entry{}, false
+	}
+	c.order.MoveToFront(element)
+	return item.entry, true
+}
+
+// Set guarda la entrada durante ttl
+func (c *LRU) Set(ctx context.Context, key string, entry Entry, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expiresAt := c.now().Add(ttl)
+	if element, ok := c.entries[key]; ok {
+		element.Value = &lruItem{key: key, entry: entry, expiresAt: expiresAt}
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruItem{key: key, entry: entry, expiresAt: expiresAt})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// DeletePrefix elimina key y todas las claves que la extienden con "/" o "?"
+func (c *LRU) DeletePrefix(ctx context.Context, key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, element := range c.entries {
+		if HasPrefix(k, key) {
+			c.remove(element)
+		}
+	}
+}
+
+// Len devuelve la cantidad de entradas guardadas
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *LRU) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*lruItem).key)
+}
+
+// HasPrefix indica si key es prefix o una URL debajo de prefix
+func HasPrefix(key string, prefix string) bool {
+	if !strings.HasPrefix(key, prefix) {
+		return false
+	}
+	rest := key[len(prefix):]
+	return rest == "" || rest[0] == '/' || rest[0] == '?'
+}
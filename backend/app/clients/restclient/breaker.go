@@ -0,0 +1,183 @@
+package restclient
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// BreakerState es el estado de un circuit breaker
+type BreakerState int
+
+const (
+	// StateClosed deja pasar todas las solicitudes
+	StateClosed BreakerState = iota
+	// StateOpen rechaza las solicitudes hasta que termine el cool-down
+	StateOpen
+	// StateHalfOpen deja pasar una sola solicitud de prueba
+	StateHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// MarshalText permite serializar el estado como texto en JSON
+func (s BreakerState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// BreakerOptions configura el circuit breaker de cada host upstream
+type BreakerOptions struct {
+	// FailureThreshold es la cantidad de fallas seguidas que abre el circuito. 0 lo desactiva
+	FailureThreshold int
+	// CoolDown es el tiempo que el circuito queda abierto antes de probar de nuevo
+	CoolDown time.Duration
+}
+
+// DefaultBreakerOptions abre el circuito tras 5 fallas seguidas durante 30 segundos
+func DefaultBreakerOptions() BreakerOptions {
+	return BreakerOptions{
+		FailureThreshold: 5,
+		CoolDown:         30 * time.Second,
+	}
+}
+
+// CircuitOpenError se devuelve sin llamar al upstream cuando su circuito está abierto
+type CircuitOpenError struct {
+	Host       string
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit open for %s, retry in %s", e.Host, e.RetryAfter)
+}
+
+// BreakerStatus es el estado visible de un circuito
+type BreakerStatus struct {
+	Host     string       `json:"host"`
+	State    BreakerState `json:"state"`
+	Failures int          `json:"failures"`
+	OpenedAt *time.Time   `json:"openedAt,omitempty"`
+}
+
+type breaker struct {
+	mu       sync.Mutex
+	options  BreakerOptions
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+	now      func() time.Time
+}
+
+// allow indica si la solicitud puede salir; si no, cuánto falta para volver a probar
+func (b *breaker) allow() (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case StateOpen:
+		remaining := b.openedAt.Add(b.options.CoolDown).Sub(b.now())
+		if remaining > 0 {
+			return false, remaining
+		}
+		b.state = StateHalfOpen
+		b.probing = true
+		return true, 0
+	case StateHalfOpen:
+		if b.probing {
+			return false, time.Second
+		}
+		b.probing = true
+		return true, 0
+	default:
+		return true, 0
+	}
+}
+
+// record registra el resultado de una solicitud que el breaker dejó pasar y
+// devuelve el estado anterior y el nuevo
+func (b *breaker) record(failed bool) (BreakerState, BreakerState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	from := b.state
+	b.probing = false
+	if !failed {
+		b.state = StateClosed
+		b.failures = 0
+		return from, b.state
+	}
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= b.options.FailureThreshold {
+		if b.state != StateOpen {
+			metrics.Add("breaker_opened", 1)
+		}
+		b.state = StateOpen
+		b.openedAt = b.now()
+	}
+	return from, b.state
+}
+
+// release libera la prueba de un circuito medio abierto sin registrar resultado
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+func (b *breaker) status(host string) BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	status := BreakerStatus{Host: host, State: b.state, Failures: b.failures}
+	if b.state != StateClosed {
+		openedAt := b.openedAt
+		status.OpenedAt = &openedAt
+	}
+	return status
+}
+
+// breakers guarda un circuito por host upstream
+type breakers struct {
+	mu      sync.Mutex
+	options BreakerOptions
+	hosts   map[string]*breaker
+	now     func() time.Time
+}
+
+func newBreakers(options BreakerOptions) *breakers {
+	return &breakers{options: options, hosts: map[string]*breaker{}, now: time.Now}
+}
+
+func (bs *breakers) get(host string) *breaker {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	b, ok := bs.hosts[host]
+	if !ok {
+		b = &breaker{options: bs.options, now: bs.now}
+		bs.hosts[host] = b
+	}
+	return b
+}
+
+func (bs *breakers) statuses() []BreakerStatus {
+	bs.mu.Lock()
+	hosts := make([]string, 0, len(bs.hosts))
+	for host := range bs.hosts {
+		hosts = append(hosts, host)
+	}
+	bs.mu.Unlock()
+	sort.Strings(hosts)
+	statuses := make([]BreakerStatus, 0, len(hosts))
+	for _, host := range hosts {
+		statuses = append(statuses, bs.get(host).status(host))
+	}
+	return statuses
+}
@@ -0,0 +1,92 @@
+package restclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreaker_Transitions(t *testing.T) {
+	now := time.Now()
+	b := &breaker{options: BreakerOptions{FailureThreshold: 2, CoolDown: time.Minute}, now: func() time.Time { return now }}
+
+	ok, _ := b.allow()
+	require.True(t, ok)
+	b.record(true)
+	require.Equal(t, StateClosed, b.status("h").State)
+	from, to := b.record(true)
+	require.Equal(t, StateClosed, from)
+	require.Equal(t, StateOpen, to)
+
+	ok, wait := b.allow()
+	require.False(t, ok)
+	require.Equal(t, time.Minute, wait)
+
+	now = now.Add(time.Minute)
+	ok, _ = b.allow()
+	require.True(t, ok)
+	require.Equal(t, StateHalfOpen, b.status("h").State)
+	ok, _ = b.allow()
+	require.False(t, ok, "only one probe while half-open")
+
+	_, to = b.record(true)
+	require.Equal(t, StateOpen, to, "a failed probe reopens the circuit")
+
+	now = now.Add(time.Minute)
+	ok, _ = b.allow()
+	require.True(t, ok)
+	_, to = b.record(false)
+	require.Equal(t, StateClosed, to)
+	require.Equal(t, 0, b.status("h").Failures)
+}
+
+func TestRestClient_Breaker_FastFails(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	options := retryOptions(1)
+	options.Breaker = BreakerOptions{FailureThreshold: 2, CoolDown: time.Minute}
+	rc := NewRestClient(options)
+	for i := 0; i < 2; i++ {
+		resp, err := rc.NewRequest(context.Background(), http.MethodGet, server.URL+"/posts", nil, nil)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	_, err := rc.NewRequest(context.Background(), http.MethodGet, server.URL+"/users", nil, nil)
+	var open *CircuitOpenError
+	require.True(t, errors.As(err, &open))
+	require.Equal(t, server.Listener.Addr().String(), open.Host)
+	require.Greater(t, open.RetryAfter, time.Duration(0))
+	require.Equal(t, int32(2), calls.Load())
+
+	statuses := rc.Breakers()
+	require.Len(t, statuses, 1)
+	require.Equal(t, StateOpen, statuses[0].State)
+}
+
+func TestRestClient_Breaker_Disabled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	options := retryOptions(1)
+	options.Breaker = BreakerOptions{}
+	rc := NewRestClient(options)
+	for i := 0; i < 10; i++ {
+		resp, err := rc.NewRequest(context.Background(), http.MethodGet, server.URL, nil, nil)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	require.Empty(t, rc.Breakers())
+}
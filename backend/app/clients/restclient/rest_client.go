@@ -1,19 +1,179 @@
 package restclient
 
 import (
+	"bytes"
+	"context"
+	"expvar"
 	"io"
+	"log/slog"
+	"net"
 	"net/http"
+	neturl "net/url"
+	"time"
 )
 
+// metrics publica los contadores del cliente en /debug/vars
+var metrics = expvar.NewMap("restclient")
+
 type IRestClient interface {
-	NewRequest(method string, url string, body io.Reader, headers map[string]string) (*http.Response, error)
+	NewRequest(ctx context.Context, method string, url string, body io.Reader, headers map[string]string) (*http.Response, error)
+}
+
+// Options configura los tiempos de espera, el pool de conexiones y los reintentos del cliente
+type Options struct {
+	// ConnectTimeout limita el establecimiento de la conexión TCP
+	ConnectTimeout time.Duration
+	// ReadTimeout limita la espera de los headers de la respuesta una vez enviada la solicitud
+	ReadTimeout time.Duration
+	// Timeout limita la llamada completa, con sus reintentos y la lectura del cuerpo.
+	// ConnectTimeout y ReadTimeout limitan cada intento.
+	Timeout             time.Duration
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+	Retry               RetryPolicy
+	Breaker             BreakerOptions
+	Logger              *slog.Logger
+}
+
+// DefaultOptions devuelve valores razonables para un upstream HTTP público
+func DefaultOptions() Options {
+	return Options{
+		ConnectTimeout:      5 * time.Second,
+		ReadTimeout:         10 * time.Second,
+		Timeout:             30 * time.Second,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 20,
+		IdleConnTimeout:     90 * time.Second,
+		Retry:               DefaultRetryPolicy(),
+		Breaker:             DefaultBreakerOptions(),
+	}
+}
+
+type RestClient struct {
+	client   *http.Client
+	timeout  time.Duration
+	retry    RetryPolicy
+	breakers *breakers
+	logger   *slog.Logger
+}
+
+// NewRequest RestClient realiza una solicitud HTTP con los parámetros dados.
+// La solicitud se cancela cuando se cancela ctx. Los errores de red transitorios y
+// las respuestas 5xx o 429 se reintentan según la política del cliente. Si el
+// circuito del host está abierto devuelve un *CircuitOpenError sin llamar al upstream.
+// Timeout limita la llamada completa hasta que se cierra el cuerpo de la respuesta.
+func (rc *RestClient) NewRequest(ctx context.Context, method string, url string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	if rc.breakers.options.FailureThreshold <= 0 {
+		return rc.deadline(ctx, method, url, body, headers)
+	}
+	host := hostOf(url)
+	cb := rc.breakers.get(host)
+	if ok, wait := cb.allow(); !ok {
+		metrics.Add("breaker_rejected", 1)
+		return nil, &CircuitOpenError{Host: host, RetryAfter: wait}
+	}
+	resp, err := rc.deadline(ctx, method, url, body, headers)
+	if ctx.Err() != nil || (err != nil && !transient(err)) {
+		// La cancelación del cliente y los errores permanentes, como uno de TLS o de
+		// una URL inválida, no dicen nada sobre la salud del upstream
+		cb.release()
+		return resp, err
+	}
+	from, to := cb.record(err != nil || resp.StatusCode >= http.StatusInternalServerError)
+	if from != to {
+		rc.logger.Warn("upstream circuit state changed", "host", host, "from", from, "to", to)
+	}
+	return resp, err
+}
+
+// Breakers devuelve el estado del circuito de cada host upstream llamado
+func (rc *RestClient) Breakers() []BreakerStatus {
+	return rc.breakers.statuses()
+}
+
+// deadline hace la llamada con sus reintentos dentro de Timeout. El plazo se libera al
+// cerrar el cuerpo de la respuesta, así también limita su lectura.
+func (rc *RestClient) deadline(ctx context.Context, method string, url string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	if rc.timeout <= 0 {
+		return rc.retrying(ctx, method, url, body, headers)
+	}
+	ctx, cancel := context.WithTimeout(ctx, rc.timeout)
+	resp, err := rc.retrying(ctx, method, url, body, headers)
+	if err != nil || resp == nil {
+		cancel()
+		return resp, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelBody libera el plazo de la llamada cuando se cierra el cuerpo
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
 }
-type RestClient struct{}
 
-// NewRequest RestClient realiza una solicitud HTTP con los parámetros dados
-func (rc *RestClient) NewRequest(method string, url string, body io.Reader, headers map[string]string) (*http.Response, error) {
-	client := &http.Client{}
-	req, err := http.NewRequest(method, url, body)
+func (b *cancelBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+func (rc *RestClient) retrying(ctx context.Context, method string, url string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	// El cuerpo se lee una sola vez para poder reenviarlo en cada intento
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = io.ReadAll(body); err != nil {
+			return nil, err
+		}
+	}
+	attempts := 1
+	if rc.retry.Allows(method) {
+		attempts = rc.retry.MaxAttempts
+	}
+	for attempt := 1; ; attempt++ {
+		metrics.Add("requests", 1)
+		resp, err := rc.do(ctx, method, url, payload, headers)
+		if ctx.Err() != nil || !rc.shouldRetry(resp, err) {
+			return resp, err
+		}
+		if attempt >= attempts {
+			if attempts > 1 {
+				metrics.Add("retries_exhausted", 1)
+				rc.logger.Warn("upstream retries exhausted", "method", method, "url", url, "attempts", attempt)
+			}
+			return resp, err
+		}
+		delay := rc.retry.Backoff(attempt)
+		if resp != nil {
+			if after, ok := retryAfter(resp); ok {
+				if after > rc.retry.MaxDelay {
+					return resp, nil
+				}
+				delay = after
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		metrics.Add("retries", 1)
+		rc.logger.Warn("retrying upstream request", "method", method, "url", url, "attempt", attempt, "delay", delay, "status", status(resp), "error", err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (rc *RestClient) do(ctx context.Context, method string, url string, payload []byte, headers map[string]string) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, err
 	}
@@ -21,14 +181,57 @@ func (rc *RestClient) NewRequest(method string, url string, body io.Reader, head
 	for key, value := range headers {
 		req.Header.Set(key, value)
 	}
-	resp, err := client.Do(req)
+	return rc.client.Do(req)
+}
+
+func (rc *RestClient) shouldRetry(resp *http.Response, err error) bool {
 	if err != nil {
-		return nil, err
+		return transient(err)
 	}
-	//defer resp.Body.Close()
-	return resp, nil
+	return retryable(resp)
+}
+
+func hostOf(url string) string {
+	u, err := neturl.Parse(url)
+	if err != nil || u.Host == "" {
+		return url
+	}
+	return u.Host
 }
 
-func NewRestClient() *RestClient {
-	return &RestClient{}
+func status(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
+
+// NewRestClient crea un cliente que reutiliza un único transporte con pool de conexiones
+func NewRestClient(options Options) *RestClient {
+	dialer := &net.Dialer{
+		Timeout:   options.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          options.MaxIdleConns,
+		MaxIdleConnsPerHost:   options.MaxIdleConnsPerHost,
+		IdleConnTimeout:       options.IdleConnTimeout,
+		TLSHandshakeTimeout:   options.ConnectTimeout,
+		ResponseHeaderTimeout: options.ReadTimeout,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	logger := options.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &RestClient{
+		client:   &http.Client{Transport: transport},
+		timeout:  options.Timeout,
+		retry:    options.Retry,
+		breakers: newBreakers(options.Breaker),
+		logger:   logger,
+	}
 }
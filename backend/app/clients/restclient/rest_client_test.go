@@ -2,30 +2,123 @@ package restclient
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"github.com/stretchr/testify/require"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestRestClient_NewRequest_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Echo-Header", r.Header.Get("X-Test"))
+		w.Write(body)
+	}))
+	defer server.Close()
 	// Mock body reader
 	mockBody := bytes.NewBufferString("hello world")
 	// Mock headers
-	headers := map[string]string{}
+	headers := map[string]string{"X-Test": "value"}
 	// Create client and request
-	rc := &RestClient{}
-	req, err := rc.NewRequest(http.MethodGet, "https://jsonplaceholder.typicode.com/posts", mockBody, headers)
+	rc := NewRestClient(DefaultOptions())
+	req, err := rc.NewRequest(context.Background(), http.MethodPost, server.URL+"/posts", mockBody, headers)
 	require.NoError(t, err)
 	defer req.Body.Close()
 	// Verify HTTP method and URL
-	require.Equal(t, http.MethodGet, req.Request.Method)
-	require.Equal(t, "https://jsonplaceholder.typicode.com/posts", req.Request.URL.String())
+	require.Equal(t, http.MethodPost, req.Request.Method)
+	require.Equal(t, server.URL+"/posts", req.Request.URL.String())
 
 	// Verify headers
 	for key, value := range headers {
-		require.Equal(t, value, req.Header.Get(key))
+		require.Equal(t, value, req.Request.Header.Get(key))
 	}
+	require.Equal(t, "value", req.Header.Get("X-Echo-Header"))
 
-	// Verify response status code
+	// Verify response status code and body
 	require.Equal(t, http.StatusOK, req.StatusCode)
+	body, _ := io.ReadAll(req.Body)
+	require.Equal(t, "hello world", string(body))
+}
+
+func TestRestClient_NewRequest_ContextCanceled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	rc := NewRestClient(DefaultOptions())
+	_, err := rc.NewRequest(ctx, http.MethodGet, server.URL, nil, nil)
+	require.Error(t, err)
+	require.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestRestClient_NewRequest_ReadTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	options := DefaultOptions()
+	options.ReadTimeout = 50 * time.Millisecond
+	rc := NewRestClient(options)
+	start := time.Now()
+	_, err := rc.NewRequest(context.Background(), http.MethodGet, server.URL, nil, nil)
+	require.Error(t, err)
+	require.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestRestClient_NewRequest_TimeoutCoversRetries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		time.Sleep(40 * time.Millisecond)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	// Cada intento entra en Timeout, pero los cinco juntos no
+	options := DefaultOptions()
+	options.Timeout = 100 * time.Millisecond
+	options.Retry = RetryPolicy{MaxAttempts: 5, BaseDelay: 10 * time.Millisecond, MaxDelay: 10 * time.Millisecond}
+	rc := NewRestClient(options)
+	start := time.Now()
+	_, err := rc.NewRequest(context.Background(), http.MethodGet, server.URL, nil, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), 200*time.Millisecond)
+	require.Less(t, calls.Load(), int32(5))
+}
+
+func TestRestClient_NewRequest_TimeoutCoversBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	options := DefaultOptions()
+	options.Timeout = 50 * time.Millisecond
+	rc := NewRestClient(options)
+	resp, err := rc.NewRequest(context.Background(), http.MethodGet, server.URL, nil, nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	_, err = io.ReadAll(resp.Body)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
 }
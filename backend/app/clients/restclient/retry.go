@@ -0,0 +1,109 @@
+package restclient
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy define cuántas veces y cada cuánto se reintenta una llamada fallida
+type RetryPolicy struct {
+	// MaxAttempts es el total de intentos, incluido el primero. 1 o menos desactiva los reintentos
+	MaxAttempts int
+	// BaseDelay es la espera antes del primer reintento; se duplica en cada intento
+	BaseDelay time.Duration
+	// MaxDelay limita la espera entre intentos. Un Retry-After mayor corta los reintentos
+	MaxDelay time.Duration
+	// RetryNonIdempotent permite reintentar también POST y PATCH
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy reintenta dos veces los métodos idempotentes
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    2 * time.Second,
+	}
+}
+
+// Allows indica si el método se puede reintentar con esta política
+func (p RetryPolicy) Allows(method string) bool {
+	if p.MaxAttempts <= 1 {
+		return false
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete, http.MethodTrace:
+		return true
+	}
+	return p.RetryNonIdempotent
+}
+
+// Backoff devuelve la espera antes del reintento número attempt (1 es el primero),
+// con crecimiento exponencial y jitter: un valor al azar entre la mitad y el total
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// retryable indica si la respuesta es un error transitorio del upstream
+func retryable(resp *http.Response) bool {
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode == http.StatusNotImplemented:
+		return false
+	default:
+		return resp.StatusCode >= 500
+	}
+}
+
+// transient indica si el error de red puede no repetirse en otro intento: plazos
+// vencidos, conexiones rechazadas o cortadas y respuestas truncadas. Los errores de
+// TLS, de certificados o de una URL inválida se repetirían en cada intento.
+func transient(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	for _, target := range []error{syscall.ECONNREFUSED, syscall.ECONNRESET, syscall.ECONNABORTED, syscall.EPIPE, io.ErrUnexpectedEOF, io.EOF} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryAfter interpreta el header Retry-After, en segundos o como fecha HTTP
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
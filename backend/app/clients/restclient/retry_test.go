@@ -0,0 +1,189 @@
+package restclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func retryOptions(attempts int) Options {
+	options := DefaultOptions()
+	options.Retry = RetryPolicy{MaxAttempts: attempts, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+	return options
+}
+
+func TestRestClient_Retry_RecoversFrom5xx(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	rc := NewRestClient(retryOptions(3))
+	resp, err := rc.NewRequest(context.Background(), http.MethodGet, server.URL, nil, nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, int32(3), calls.Load())
+}
+
+func TestRestClient_Retry_Exhausted(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	rc := NewRestClient(retryOptions(2))
+	resp, err := rc.NewRequest(context.Background(), http.MethodDelete, server.URL, nil, nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	require.Equal(t, int32(2), calls.Load())
+}
+
+func TestRestClient_Retry_NonIdempotent(t *testing.T) {
+	var calls atomic.Int32
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	t.Run("not retried by default", func(t *testing.T) {
+		rc := NewRestClient(retryOptions(3))
+		resp, err := rc.NewRequest(context.Background(), http.MethodPost, server.URL, bytes.NewBufferString("{}"), nil)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		require.Equal(t, int32(1), calls.Load())
+	})
+	t.Run("retried with replayed body", func(t *testing.T) {
+		calls.Store(0)
+		bodies = nil
+		options := retryOptions(3)
+		options.Retry.RetryNonIdempotent = true
+		rc := NewRestClient(options)
+		resp, err := rc.NewRequest(context.Background(), http.MethodPost, server.URL, bytes.NewBufferString(`{"id":1}`), nil)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		require.Equal(t, []string{`{"id":1}`, `{"id":1}`}, bodies)
+	})
+}
+
+func TestRestClient_Retry_RetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	rc := NewRestClient(retryOptions(3))
+	resp, err := rc.NewRequest(context.Background(), http.MethodGet, server.URL, nil, nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestRestClient_Retry_NotFoundIsFinal(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	rc := NewRestClient(retryOptions(3))
+	resp, err := rc.NewRequest(context.Background(), http.MethodGet, server.URL, nil, nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestRestClient_Retry_PermanentErrorIsFinal(t *testing.T) {
+	var conns atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	// El cliente no confía en el certificado del servidor de prueba
+	options := retryOptions(3)
+	options.Breaker = BreakerOptions{FailureThreshold: 1, CoolDown: time.Minute}
+	rc := NewRestClient(options)
+	_, err := rc.NewRequest(context.Background(), http.MethodGet, server.URL, nil, nil)
+	require.Error(t, err)
+	require.Equal(t, int32(1), conns.Load())
+	_, err = rc.NewRequest(context.Background(), http.MethodGet, "gopher://"+server.Listener.Addr().String(), nil, nil)
+	require.Error(t, err)
+	require.Equal(t, StateClosed, rc.Breakers()[0].State, "permanent errors don't open the circuit")
+}
+
+func TestRestClient_Retry_RecoversFromClosedConnection(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	rc := NewRestClient(retryOptions(3))
+	resp, err := rc.NewRequest(context.Background(), http.MethodGet, server.URL, nil, nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, int32(2), calls.Load())
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt, max := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 5: time.Second, 9: time.Second} {
+		for i := 0; i < 20; i++ {
+			delay := policy.Backoff(attempt)
+			require.GreaterOrEqual(t, delay, max/2)
+			require.LessOrEqual(t, delay, max)
+		}
+	}
+}
+
+func TestRetryPolicy_Allows(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	require.True(t, policy.Allows(http.MethodGet))
+	require.True(t, policy.Allows(http.MethodPut))
+	require.False(t, policy.Allows(http.MethodPost))
+	require.False(t, policy.Allows(http.MethodPatch))
+	policy.MaxAttempts = 1
+	require.False(t, policy.Allows(http.MethodGet))
+}
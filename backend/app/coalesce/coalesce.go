@@ -0,0 +1,85 @@
+package coalesce
+
+import (
+	"context"
+	"expvar"
+	"sync"
+)
+
+// metrics publica en /debug/vars cuántas llamadas se hicieron y cuántas se
+// resolvieron esperando a otra llamada igual en curso
+var metrics = expvar.NewMap("coalesce")
+
+// Group junta las llamadas concurrentes con la misma clave en una sola. La
+// llamada compartida sigue mientras quede al menos un interesado; si todos
+// cancelan, se cancela también.
+type Group[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*call[T]
+}
+
+type call[T any] struct {
+	done    chan struct{}
+	val     T
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// Do ejecuta fn una sola vez por clave entre las llamadas concurrentes y
+// devuelve su resultado a todas. shared indica si el resultado vino de la
+// llamada de otro. Si ctx se cancela, Do vuelve enseguida con ctx.Err().
+func (g *Group[T]) Do(ctx context.Context, key string, fn func(ctx context.Context) (T, error)) (val T, err error, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*call[T]{}
+	}
+	metrics.Add("calls", 1)
+	c, shared := g.calls[key]
+	if shared {
+		metrics.Add("coalesced", 1)
+		c.waiters++
+	} else {
+		// La llamada compartida no depende del contexto de quien la inició
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		c = &call[T]{done: make(chan struct{}), waiters: 1, cancel: cancel}
+		g.calls[key] = c
+		go g.run(callCtx, key, c, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.val, c.err, shared
+	case <-ctx.Done():
+		g.leave(key, c)
+		var zero T
+		return zero, ctx.Err(), shared
+	}
+}
+
+func (g *Group[T]) run(ctx context.Context, key string, c *call[T], fn func(ctx context.Context) (T, error)) {
+	defer c.cancel()
+	c.val, c.err = fn(ctx)
+	g.mu.Lock()
+	if g.calls[key] == c {
+		delete(g.calls, key)
+	}
+	g.mu.Unlock()
+	close(c.done)
+}
+
+// leave quita un interesado; el último cancela la llamada y la saca del grupo
+// para que las llamadas nuevas no se unan a una llamada cancelada
+func (g *Group[T]) leave(key string, c *call[T]) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	c.waiters--
+	if c.waiters > 0 {
+		return
+	}
+	c.cancel()
+	if g.calls[key] == c {
+		delete(g.calls, key)
+	}
+}
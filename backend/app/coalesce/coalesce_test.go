@@ -0,0 +1,111 @@
+package coalesce
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroup_Do_Coalesces(t *testing.T) {
+	var g Group[int]
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (int, error) {
+		calls.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	var shared atomic.Int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			val, err, s := g.Do(context.Background(), "posts/1", fn)
+			require.NoError(t, err)
+			require.Equal(t, 42, val)
+			if s {
+				shared.Add(1)
+			}
+		}()
+	}
+	require.Eventually(t, func() bool {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		return g.calls["posts/1"] != nil && g.calls["posts/1"].waiters == 10
+	}, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+	require.Equal(t, int32(1), calls.Load())
+	require.Equal(t, int32(9), shared.Load())
+}
+
+func TestGroup_Do_CancelledWaiter(t *testing.T) {
+	var g Group[int]
+	release := make(chan struct{})
+	started := make(chan struct{})
+	fn := func(ctx context.Context) (int, error) {
+		close(started)
+		<-release
+		return 1, ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error)
+	go func() {
+		_, err, _ := g.Do(ctx, "k", fn)
+		result <- err
+	}()
+	<-started
+	// Un segundo interesado mantiene viva la llamada aunque el primero cancele
+	second := make(chan int)
+	go func() {
+		val, _, _ := g.Do(context.Background(), "k", fn)
+		second <- val
+	}()
+	require.Eventually(t, func() bool {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		return g.calls["k"].waiters == 2
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	require.True(t, errors.Is(<-result, context.Canceled))
+	close(release)
+	require.Equal(t, 1, <-second)
+}
+
+func TestGroup_Do_AllWaitersCancel(t *testing.T) {
+	var g Group[int]
+	cancelled := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		g.Do(ctx, "k", func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			close(cancelled)
+			return 0, ctx.Err()
+		})
+	}()
+	require.Eventually(t, func() bool {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		return g.calls["k"] != nil
+	}, time.Second, time.Millisecond)
+	cancel()
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("the shared call was not cancelled")
+	}
+
+	val, err, shared := g.Do(context.Background(), "k", func(ctx context.Context) (int, error) { return 2, nil })
+	require.NoError(t, err)
+	require.Equal(t, 2, val)
+	require.False(t, shared)
+}
@@ -0,0 +1,90 @@
+package conditional
+
+import (
+	apperrors "blog-api/app/errors"
+	"blog-api/app/i18n"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+)
+
+// ErrPreconditionFailed indica que el ETag del elemento no está en If-Match: cambió
+// después de que el cliente lo leyó
+var ErrPreconditionFailed = apperrors.New(apperrors.ErrPreconditionFailed, "the resource has changed")
+
+// Tag calcula el ETag fuerte de una representación a partir de sus bytes
+func Tag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+}
+
+// ETag calcula el ETag de la representación JSON de v, la misma que responden los manejadores
+func ETag(v any) (string, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return Tag(body), nil
+}
+
+// Match informa si etag está en header, el valor de If-Match: una lista de ETags o *.
+// Usa la comparación fuerte, así que un ETag débil (W/) nunca coincide.
+func Match(header string, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// NotModified informa si etag está en header, el valor de If-None-Match. Usa la
+// comparación débil, que ignora el prefijo W/.
+func NotModified(header string, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+type contextKey struct{}
+
+// NewContext guarda en ctx el header If-Match de la solicitud para que el servicio lo
+// compare con el elemento que va a escribir. Vacío no pone condiciones.
+func NewContext(ctx context.Context, ifMatch string) context.Context {
+	return context.WithValue(ctx, contextKey{}, ifMatch)
+}
+
+// Without quita la condición de ctx, p. ej. cuando ya se comprobó
+func Without(ctx context.Context) context.Context {
+	return NewContext(ctx, "")
+}
+
+// IfMatch devuelve el If-Match guardado en ctx, si hay
+func IfMatch(ctx context.Context) (string, bool) {
+	header, _ := ctx.Value(contextKey{}).(string)
+	return header, header != ""
+}
+
+// Check compara el ETag de current con el If-Match de ctx; sin If-Match no hay condición
+func Check(ctx context.Context, current any) error {
+	header, ok := IfMatch(ctx)
+	if !ok {
+		return nil
+	}
+	etag, err := ETag(current)
+	if err != nil {
+		return err
+	}
+	if !Match(header, etag) {
+		return i18n.Errorf("%w, the current ETag is %s", ErrPreconditionFailed, etag)
+	}
+	return nil
+}
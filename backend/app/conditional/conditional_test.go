@@ -0,0 +1,63 @@
+package conditional
+
+import (
+	apperrors "blog-api/app/errors"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type item struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestETag(t *testing.T) {
+	etag, err := ETag(item{ID: 1, Name: "a"})
+	require.NoError(t, err)
+	require.Equal(t, Tag([]byte(`{"id":1,"name":"a"}`)), etag)
+	require.Regexp(t, `^"[A-Za-z0-9_-]{22}"$`, etag)
+
+	other, err := ETag(item{ID: 1, Name: "b"})
+	require.NoError(t, err)
+	require.NotEqual(t, etag, other)
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		header      string
+		match       bool
+		notModified bool
+	}{
+		{`"abc"`, true, true},
+		{`"xyz", "abc"`, true, true},
+		{`*`, true, true},
+		{`W/"abc"`, false, true},
+		{`"xyz"`, false, false},
+		{`abc`, false, false},
+	}
+	for _, tc := range tests {
+		require.Equal(t, tc.match, Match(tc.header, `"abc"`), tc.header)
+		require.Equal(t, tc.notModified, NotModified(tc.header, `"abc"`), tc.header)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	current := item{ID: 1, Name: "a"}
+	etag, err := ETag(current)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, Check(ctx, current))
+	require.NoError(t, Check(NewContext(ctx, etag), current))
+	require.NoError(t, Check(NewContext(ctx, "*"), current))
+
+	err = Check(NewContext(ctx, `"stale"`), current)
+	require.ErrorIs(t, err, ErrPreconditionFailed)
+	require.ErrorIs(t, err, apperrors.ErrPreconditionFailed)
+	require.EqualError(t, err, "the resource has changed, the current ETag is "+etag)
+
+	// Without quita la condición, p. ej. después de comprobarla
+	require.NoError(t, Check(Without(NewContext(ctx, `"stale"`)), current))
+}
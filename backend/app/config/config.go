@@ -0,0 +1,359 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultUpstreamURL es la raíz usada cuando no se define UPSTREAM_URL
+const DefaultUpstreamURL = "https://jsonplaceholder.typicode.com"
+
+// Modos de funcionamiento: reenviar al upstream, guardar en la base de datos local
+// o leer del upstream y guardar las escrituras localmente
+const (
+	ModeProxy   = "proxy"
+	ModeLocal   = "local"
+	ModeOverlay = "overlay"
+)
+
+// Valores por defecto de la base de datos local
+const (
+	DefaultDBDriver = "sqlite"
+	DefaultDBDSN    = "blog.db"
+)
+
+// Valores por defecto de la caché de respuestas
+const (
+	DefaultCacheTTL   = time.Minute
+	DefaultCacheStale = 5 * time.Minute
+)
+
+// Valores por defecto de las imágenes subidas
+const (
+	DefaultUploadDir     = "uploads"
+	DefaultUploadMaxSize = 10 << 20
+	DefaultThumbnailSize = 150
+)
+
+// Resources son las colecciones que expone la API
+var Resources = []string{"albums", "comments", "photos", "posts", "todos", "users"}
+
+// Upstream es la raíz y el path de la colección upstream de un recurso
+type Upstream struct {
+	Root string
+	Path string
+}
+
+// URL devuelve la URL de la colección, p. ej. https://host/posts
+func (u Upstream) URL() string {
+	return strings.TrimRight(u.Root, "/") + "/" + strings.Trim(u.Path, "/")
+}
+
+// Timeouts son los tiempos de espera de las llamadas al upstream. Cero significa
+// usar el valor por defecto del cliente.
+type Timeouts struct {
+	Connect time.Duration
+	Read    time.Duration
+	Total   time.Duration
+}
+
+// Retry es la política de reintentos de las llamadas al upstream. Cero
+// significa usar el valor por defecto del cliente.
+type Retry struct {
+	Attempts  int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// Breaker es la configuración del circuit breaker de cada host upstream. Cero
+// significa usar el valor por defecto del cliente.
+type Breaker struct {
+	Threshold int
+	CoolDown  time.Duration
+}
+
+// Cache es la configuración de la caché de respuestas del upstream
+type Cache struct {
+	// Size es la cantidad máxima de entradas. Cero usa el valor por defecto de la caché
+	Size int
+	// TTL es el tiempo que una respuesta es fresca, salvo que el recurso defina el suyo
+	TTL time.Duration
+	// Stale es el tiempo extra que se sirve una respuesta vencida mientras se revalida
+	Stale time.Duration
+	// TTLs son los tiempos propios de cada recurso
+	TTLs map[string]time.Duration
+}
+
+// Database es la conexión de los modos local y overlay
+type Database struct {
+	// Driver es sqlite o postgres
+	Driver string
+	// DSN es el archivo de SQLite o la cadena de conexión de Postgres
+	DSN string
+}
+
+// Uploads es la configuración de las imágenes subidas
+type Uploads struct {
+	// Dir es el directorio donde se guardan los archivos
+	Dir string
+	// MaxSize es el tamaño máximo de un archivo en bytes
+	MaxSize int64
+	// ThumbnailSize es el lado máximo de las miniaturas en píxeles
+	ThumbnailSize int
+}
+
+// Idempotency es la configuración de Idempotency-Key en las altas. Cero significa usar
+// el valor por defecto del middleware.
+type Idempotency struct {
+	// TTL es el tiempo que se guarda la respuesta de cada clave
+	TTL time.Duration
+	// Wait es lo que una solicitud repetida espera a la original en curso
+	Wait time.Duration
+}
+
+// Bulk es la configuración de POST /v1/{resource}/bulk. Cero significa usar el valor
+// por defecto del manejador.
+type Bulk struct {
+	// MaxItems es la cantidad máxima de operaciones de una solicitud
+	MaxItems int
+	// Concurrency es la cantidad de operaciones que se aplican a la vez
+	Concurrency int
+}
+
+// Batch es la configuración de POST /v1/batch. Cero significa usar el valor por defecto
+// del manejador.
+type Batch struct {
+	// MaxRequests es la cantidad máxima de subsolicitudes de un batch
+	MaxRequests int
+	// MaxSize es el tamaño máximo del cuerpo de un batch en bytes
+	MaxSize int64
+	// Concurrency es la cantidad de subsolicitudes que se atienden a la vez
+	Concurrency int
+}
+
+// Config es la configuración de la aplicación
+type Config struct {
+	Mode             string
+	Database         Database
+	Upstreams        map[string]Upstream
+	UpstreamTimeouts Timeouts
+	UpstreamRetry    Retry
+	UpstreamBreaker  Breaker
+	Cache            Cache
+	Uploads          Uploads
+	Idempotency      Idempotency
+	Bulk             Bulk
+	Batch            Batch
+}
+
+// Upstream devuelve la colección upstream de un recurso. Si no fue configurado
+// se usa la raíz por defecto y el nombre del recurso como path.
+func (c *Config) Upstream(resource string) Upstream {
+	if upstream, ok := c.Upstreams[resource]; ok {
+		return upstream
+	}
+	return Upstream{Root: DefaultUpstreamURL, Path: resource}
+}
+
+// CacheTTL devuelve el tiempo que es fresca una respuesta del recurso
+func (c *Config) CacheTTL(resource string) time.Duration {
+	if ttl, ok := c.Cache.TTLs[resource]; ok {
+		return ttl
+	}
+	return c.Cache.TTL
+}
+
+// Load lee la configuración de las variables de entorno:
+//
+//	MODE                       proxy (por defecto), local u overlay
+//	DB_DRIVER                  base de datos de los modos local y overlay: sqlite (por defecto) o postgres
+//	DB_DSN                     archivo de SQLite o cadena de conexión de Postgres
+//	UPSTREAM_URL               raíz común de todos los recursos
+//	<RESOURCE>_UPSTREAM_URL    raíz de un recurso, p. ej. POSTS_UPSTREAM_URL
+//	<RESOURCE>_UPSTREAM_PATH   path de un recurso, por defecto su nombre
+//	UPSTREAM_CONNECT_TIMEOUT   tiempo máximo para conectar, p. ej. 5s
+//	UPSTREAM_READ_TIMEOUT      tiempo máximo de espera de la respuesta
+//	UPSTREAM_TIMEOUT           tiempo máximo de la solicitud completa, con sus reintentos
+//	UPSTREAM_RETRY_ATTEMPTS    intentos por solicitud, 1 desactiva los reintentos
+//	UPSTREAM_RETRY_BASE_DELAY  espera antes del primer reintento, p. ej. 100ms
+//	UPSTREAM_RETRY_MAX_DELAY   espera máxima entre reintentos
+//	UPSTREAM_BREAKER_THRESHOLD fallas seguidas que abren el circuito de un host
+//	UPSTREAM_BREAKER_COOLDOWN  tiempo que el circuito queda abierto, p. ej. 30s
+//	CACHE_SIZE                 entradas máximas de la caché de respuestas
+//	CACHE_TTL                  tiempo que una respuesta es fresca, por defecto 1m
+//	<RESOURCE>_CACHE_TTL       tiempo propio de un recurso, p. ej. USERS_CACHE_TTL
+//	CACHE_STALE                tiempo que se sirve vencida mientras se revalida, por defecto 5m
+//	UPLOAD_DIR                 directorio de las imágenes subidas, por defecto uploads
+//	UPLOAD_MAX_SIZE            tamaño máximo de una imagen en bytes, por defecto 10 MiB
+//	THUMBNAIL_SIZE             lado máximo de las miniaturas en píxeles, por defecto 150
+//	IDEMPOTENCY_TTL            tiempo que se guarda la respuesta de un Idempotency-Key, por defecto 24h
+//	IDEMPOTENCY_WAIT           espera de una repetición a la solicitud original en curso, por defecto 10s
+//	BULK_MAX_ITEMS             operaciones máximas de un bulk, por defecto 1000
+//	BULK_CONCURRENCY           operaciones de un bulk que se aplican a la vez, por defecto 4
+//	BATCH_MAX_REQUESTS         subsolicitudes máximas de un batch, por defecto 20
+//	BATCH_MAX_SIZE             tamaño máximo del cuerpo de un batch en bytes, por defecto 1 MiB
+//	BATCH_CONCURRENCY          subsolicitudes de un batch que se atienden a la vez, por defecto 4
+func Load() (*Config, error) {
+	return load(os.Getenv)
+}
+
+func load(getenv func(string) string) (*Config, error) {
+	root := getenv("UPSTREAM_URL")
+	if root == "" {
+		root = DefaultUpstreamURL
+	}
+	cfg := &Config{
+		Mode:      ModeProxy,
+		Database:  Database{Driver: DefaultDBDriver, DSN: DefaultDBDSN},
+		Upstreams: map[string]Upstream{},
+		Cache:     Cache{TTL: DefaultCacheTTL, Stale: DefaultCacheStale, TTLs: map[string]time.Duration{}},
+		Uploads:   Uploads{Dir: DefaultUploadDir, MaxSize: DefaultUploadMaxSize, ThumbnailSize: DefaultThumbnailSize},
+	}
+	if err := cfg.loadMode(getenv); err != nil {
+		return nil, err
+	}
+	for _, resource := range Resources {
+		prefix := strings.ToUpper(resource) + "_UPSTREAM_"
+		upstream := Upstream{Root: root, Path: resource}
+		if value := getenv(prefix + "URL"); value != "" {
+			upstream.Root = value
+		}
+		if value := getenv(prefix + "PATH"); value != "" {
+			upstream.Path = value
+		}
+		cfg.Upstreams[resource] = upstream
+	}
+	var err error
+	for _, resource := range Resources {
+		key := strings.ToUpper(resource) + "_CACHE_TTL"
+		if getenv(key) == "" {
+			continue
+		}
+		if cfg.Cache.TTLs[resource], err = duration(getenv, key); err != nil {
+			return nil, err
+		}
+	}
+	for key, target := range map[string]*time.Duration{"CACHE_TTL": &cfg.Cache.TTL, "CACHE_STALE": &cfg.Cache.Stale} {
+		if getenv(key) == "" {
+			continue
+		}
+		if *target, err = duration(getenv, key); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Cache.Size, err = count(getenv, "CACHE_SIZE"); err != nil {
+		return nil, err
+	}
+	timeouts := map[string]*time.Duration{
+		"UPSTREAM_CONNECT_TIMEOUT":  &cfg.UpstreamTimeouts.Connect,
+		"UPSTREAM_READ_TIMEOUT":     &cfg.UpstreamTimeouts.Read,
+		"UPSTREAM_TIMEOUT":          &cfg.UpstreamTimeouts.Total,
+		"UPSTREAM_RETRY_BASE_DELAY": &cfg.UpstreamRetry.BaseDelay,
+		"UPSTREAM_RETRY_MAX_DELAY":  &cfg.UpstreamRetry.MaxDelay,
+		"UPSTREAM_BREAKER_COOLDOWN": &cfg.UpstreamBreaker.CoolDown,
+		"IDEMPOTENCY_TTL":           &cfg.Idempotency.TTL,
+		"IDEMPOTENCY_WAIT":          &cfg.Idempotency.Wait,
+	}
+	for key, target := range timeouts {
+		if *target, err = duration(getenv, key); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.UpstreamRetry.Attempts, err = count(getenv, "UPSTREAM_RETRY_ATTEMPTS"); err != nil {
+		return nil, err
+	}
+	if cfg.UpstreamBreaker.Threshold, err = count(getenv, "UPSTREAM_BREAKER_THRESHOLD"); err != nil {
+		return nil, err
+	}
+	if cfg.Bulk.MaxItems, err = count(getenv, "BULK_MAX_ITEMS"); err != nil {
+		return nil, err
+	}
+	if cfg.Bulk.Concurrency, err = count(getenv, "BULK_CONCURRENCY"); err != nil {
+		return nil, err
+	}
+	if err := cfg.loadBatch(getenv); err != nil {
+		return nil, err
+	}
+	if err := cfg.loadUploads(getenv); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) loadMode(getenv func(string) string) error {
+	if value := getenv("MODE"); value != "" {
+		if value != ModeProxy && value != ModeLocal && value != ModeOverlay {
+			return fmt.Errorf("invalid MODE %q", value)
+		}
+		c.Mode = value
+	}
+	if value := getenv("DB_DRIVER"); value != "" {
+		c.Database.Driver = value
+	}
+	if value := getenv("DB_DSN"); value != "" {
+		c.Database.DSN = value
+	}
+	return nil
+}
+
+func (c *Config) loadBatch(getenv func(string) string) error {
+	var err error
+	if c.Batch.MaxRequests, err = count(getenv, "BATCH_MAX_REQUESTS"); err != nil {
+		return err
+	}
+	size, err := count(getenv, "BATCH_MAX_SIZE")
+	if err != nil {
+		return err
+	}
+	c.Batch.MaxSize = int64(size)
+	c.Batch.Concurrency, err = count(getenv, "BATCH_CONCURRENCY")
+	return err
+}
+
+func (c *Config) loadUploads(getenv func(string) string) error {
+	if value := getenv("UPLOAD_DIR"); value != "" {
+		c.Uploads.Dir = value
+	}
+	if getenv("UPLOAD_MAX_SIZE") != "" {
+		size, err := count(getenv, "UPLOAD_MAX_SIZE")
+		if err != nil {
+			return err
+		}
+		c.Uploads.MaxSize = int64(size)
+	}
+	if getenv("THUMBNAIL_SIZE") != "" {
+		size, err := count(getenv, "THUMBNAIL_SIZE")
+		if err != nil {
+			return err
+		}
+		c.Uploads.ThumbnailSize = size
+	}
+	return nil
+}
+
+func count(getenv func(string) string, key string) (int, error) {
+	value := getenv(key)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid %s %q", key, value)
+	}
+	return n, nil
+}
+
+func duration(getenv func(string) string, key string) (time.Duration, error) {
+	value := getenv(key)
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid %s %q", key, value)
+	}
+	return d, nil
+}
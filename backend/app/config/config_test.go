@@ -0,0 +1,146 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpstream_URL(t *testing.T) {
+	require.Equal(t, "https://host/posts", Upstream{Root: "https://host/", Path: "/posts"}.URL())
+	require.Equal(t, "https://host/api/v2/posts", Upstream{Root: "https://host/api", Path: "v2/posts"}.URL())
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		cfg, err := load(func(string) string { return "" })
+		require.NoError(t, err)
+		for _, resource := range Resources {
+			require.Equal(t, DefaultUpstreamURL+"/"+resource, cfg.Upstream(resource).URL())
+		}
+	})
+	t.Run("overrides", func(t *testing.T) {
+		env := map[string]string{
+			"UPSTREAM_URL":               "https://upstream.local",
+			"POSTS_UPSTREAM_URL":         "https://posts.local",
+			"USERS_UPSTREAM_PATH":        "people",
+			"ALBUMS_UPSTREAM_PATH":       "v2/albums",
+			"UPSTREAM_READ_TIMEOUT":      "2s",
+			"UPSTREAM_TIMEOUT":           "1m",
+			"UPSTREAM_RETRY_ATTEMPTS":    "5",
+			"UPSTREAM_RETRY_MAX_DELAY":   "3s",
+			"UPSTREAM_BREAKER_THRESHOLD": "8",
+			"CACHE_TTL":                  "10s",
+			"USERS_CACHE_TTL":            "1h",
+			"CACHE_STALE":                "0s",
+		}
+		cfg, err := load(func(key string) string { return env[key] })
+		require.NoError(t, err)
+		require.Equal(t, "https://posts.local/posts", cfg.Upstream("posts").URL())
+		require.Equal(t, "https://upstream.local/people", cfg.Upstream("users").URL())
+		require.Equal(t, "https://upstream.local/v2/albums", cfg.Upstream("albums").URL())
+		require.Equal(t, "https://upstream.local/todos", cfg.Upstream("todos").URL())
+		require.Equal(t, Timeouts{Read: 2 * time.Second, Total: time.Minute}, cfg.UpstreamTimeouts)
+		require.Equal(t, Retry{Attempts: 5, MaxDelay: 3 * time.Second}, cfg.UpstreamRetry)
+		require.Equal(t, Breaker{Threshold: 8}, cfg.UpstreamBreaker)
+		require.Equal(t, time.Hour, cfg.CacheTTL("users"))
+		require.Equal(t, 10*time.Second, cfg.CacheTTL("posts"))
+		require.Equal(t, time.Duration(0), cfg.Cache.Stale)
+	})
+	t.Run("invalid timeout", func(t *testing.T) {
+		_, err := load(func(key string) string {
+			if key == "UPSTREAM_CONNECT_TIMEOUT" {
+				return "soon"
+			}
+			return ""
+		})
+		require.Error(t, err)
+	})
+	t.Run("local mode", func(t *testing.T) {
+		env := map[string]string{"MODE": "local", "DB_DRIVER": "postgres", "DB_DSN": "postgres://db/blog"}
+		cfg, err := load(func(key string) string { return env[key] })
+		require.NoError(t, err)
+		require.Equal(t, ModeLocal, cfg.Mode)
+		require.Equal(t, Database{Driver: "postgres", DSN: "postgres://db/blog"}, cfg.Database)
+
+		cfg, err = load(func(key string) string {
+			if key == "MODE" {
+				return "overlay"
+			}
+			return ""
+		})
+		require.NoError(t, err)
+		require.Equal(t, ModeOverlay, cfg.Mode)
+
+		_, err = load(func(key string) string {
+			if key == "MODE" {
+				return "offline"
+			}
+			return ""
+		})
+		require.Error(t, err)
+	})
+	t.Run("invalid retry attempts", func(t *testing.T) {
+		_, err := load(func(key string) string {
+			if key == "UPSTREAM_RETRY_ATTEMPTS" {
+				return "0"
+			}
+			return ""
+		})
+		require.Error(t, err)
+	})
+	t.Run("unknown resource", func(t *testing.T) {
+		cfg, err := load(func(string) string { return "" })
+		require.NoError(t, err)
+		require.Equal(t, DefaultUpstreamURL+"/videos", cfg.Upstream("videos").URL())
+	})
+	t.Run("uploads", func(t *testing.T) {
+		cfg, err := load(func(string) string { return "" })
+		require.NoError(t, err)
+		require.Equal(t, Uploads{Dir: DefaultUploadDir, MaxSize: DefaultUploadMaxSize, ThumbnailSize: DefaultThumbnailSize}, cfg.Uploads)
+
+		env := map[string]string{"UPLOAD_DIR": "/var/blog", "UPLOAD_MAX_SIZE": "1024", "THUMBNAIL_SIZE": "64"}
+		cfg, err = load(func(key string) string { return env[key] })
+		require.NoError(t, err)
+		require.Equal(t, Uploads{Dir: "/var/blog", MaxSize: 1024, ThumbnailSize: 64}, cfg.Uploads)
+
+		_, err = load(func(key string) string {
+			if key == "UPLOAD_MAX_SIZE" {
+				return "10MB"
+			}
+			return ""
+		})
+		require.Error(t, err)
+	})
+	t.Run("bulk", func(t *testing.T) {
+		env := map[string]string{"BULK_MAX_ITEMS": "50", "BULK_CONCURRENCY": "2"}
+		cfg, err := load(func(key string) string { return env[key] })
+		require.NoError(t, err)
+		require.Equal(t, Bulk{MaxItems: 50, Concurrency: 2}, cfg.Bulk)
+
+		env["BULK_CONCURRENCY"] = "0"
+		_, err = load(func(key string) string { return env[key] })
+		require.Error(t, err)
+	})
+	t.Run("batch", func(t *testing.T) {
+		env := map[string]string{"BATCH_MAX_REQUESTS": "10", "BATCH_MAX_SIZE": "4096", "BATCH_CONCURRENCY": "2"}
+		cfg, err := load(func(key string) string { return env[key] })
+		require.NoError(t, err)
+		require.Equal(t, Batch{MaxRequests: 10, MaxSize: 4096, Concurrency: 2}, cfg.Batch)
+
+		env["BATCH_MAX_SIZE"] = "1MB"
+		_, err = load(func(key string) string { return env[key] })
+		require.Error(t, err)
+	})
+	t.Run("idempotency", func(t *testing.T) {
+		env := map[string]string{"IDEMPOTENCY_TTL": "1h", "IDEMPOTENCY_WAIT": "2s"}
+		cfg, err := load(func(key string) string { return env[key] })
+		require.NoError(t, err)
+		require.Equal(t, Idempotency{TTL: time.Hour, Wait: 2 * time.Second}, cfg.Idempotency)
+
+		env["IDEMPOTENCY_TTL"] = "forever"
+		_, err = load(func(key string) string { return env[key] })
+		require.Error(t, err)
+	})
+}
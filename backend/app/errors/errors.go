@@ -1,15 +1,181 @@
 package errors
 
-type AppError struct {
-	Message    []string `json:"message"`
-	Error      string   `json:"error"`
-	StatusCode int      `json:"statusCode"`
+import (
+	"blog-api/app/i18n"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Code es el identificador estable de un tipo de error, pensado para que los clientes
+// decidan qué hacer sin interpretar los mensajes
+type Code string
+
+// Error es un error de dominio: su tipo define el código, el status HTTP y el título con
+// que se responde. Los tipos del catálogo, p. ej. ErrNotFound, se comparan con errors.Is
+// y los errores creados con New o Newf son de su tipo.
+type Error struct {
+	Code        Code
+	Status      int
+	Title       string
+	Description string
+	message     string
+	format      string
+	args        []any
+	kind        *Error
+	err         error
+}
+
+func (e *Error) Error() string {
+	return e.message
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Localize escribe el mensaje en lang
+func (e *Error) Localize(lang string) string {
+	return i18n.Sprintf(lang, e.format, e.args...)
+}
+
+// Is informa si target es el tipo del error
+func (e *Error) Is(target error) bool {
+	return e.kind != nil && target == e.kind
+}
+
+// FieldError es un campo que no cumple una regla, p. ej. {"field": "title", "rule": "required"}
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+	format  string
+	args    []any
+}
+
+// NewFieldError crea el error de un campo con el mensaje de format, que se traduce al
+// idioma de la respuesta
+func NewFieldError(field string, rule string, param string, format string, args ...any) FieldError {
+	return FieldError{Field: field, Rule: rule, Param: param, Message: fmt.Sprintf(format, args...), format: format, args: args}
+}
+
+// Fielder es un error que detalla los campos inválidos, p. ej. el de validation, con
+// los mensajes en lang
+type Fielder interface {
+	error
+	FieldErrors(lang string) []FieldError
+}
+
+// WithFields agrega a err el detalle de los campos que lo causaron. Los mensajes de los
+// campos creados con NewFieldError se traducen al responder.
+func WithFields(err *Error, fields ...FieldError) Fielder {
+	return &fieldsError{err: err, fields: fields}
+}
+
+type fieldsError struct {
+	err    *Error
+	fields []FieldError
+}
+
+func (e *fieldsError) Error() string {
+	return e.err.Error()
+}
+
+func (e *fieldsError) Unwrap() error {
+	return e.err
+}
+
+func (e *fieldsError) Localize(lang string) string {
+	return e.err.Localize(lang)
+}
+
+func (e *fieldsError) FieldErrors(lang string) []FieldError {
+	fields := make([]FieldError, len(e.fields))
+	for i, field := range e.fields {
+		if field.format != "" {
+			field.Message = i18n.Sprintf(lang, field.format, field.args...)
+		}
+		fields[i] = field
+	}
+	return fields
+}
+
+// catalog son los tipos de error en el orden en que se documentan
+var catalog []*Error
+
+func kind(code Code, status int, message string, description string) *Error {
+	e := &Error{Code: code, Status: status, Title: http.StatusText(status), Description: description, message: message, format: message}
+	catalog = append(catalog, e)
+	return e
+}
+
+// Catálogo de errores. Los códigos no cambian; los mensajes sí pueden cambiar.
+var (
+	ErrBadRequest           = kind("bad_request", http.StatusBadRequest, "bad request", "The request is malformed: a body that is not JSON, a value of the wrong type or an invalid query parameter.")
+	ErrUnauthorized         = kind("unauthorized", http.StatusUnauthorized, "unauthorized", "The request lacks valid credentials.")
+	ErrForbidden            = kind("forbidden", http.StatusForbidden, "forbidden", "The credentials do not allow this operation.")
+	ErrNotFound             = kind("not_found", http.StatusNotFound, "not found", "The resource, or the parent in a nested route, does not exist.")
+	ErrMethodNotAllowed     = kind("method_not_allowed", http.StatusMethodNotAllowed, "method not allowed", "The route does not support this HTTP method.")
+	ErrConflict             = kind("conflict", http.StatusConflict, "conflict", "The request conflicts with the current state of the resource.")
+	ErrPreconditionFailed   = kind("precondition_failed", http.StatusPreconditionFailed, "precondition failed", "If-Match does not match the current ETag: the resource changed since it was read. Read it again and retry.")
+	ErrPayloadTooLarge      = kind("payload_too_large", http.StatusRequestEntityTooLarge, "payload too large", "The body or uploaded file exceeds the allowed size.")
+	ErrUnsupportedMediaType = kind("unsupported_media_type", http.StatusUnsupportedMediaType, "unsupported media type", "The body or uploaded file has a type the endpoint does not accept.")
+	ErrValidation           = kind("validation_failed", http.StatusUnprocessableEntity, "validation failed", "One or more fields break the rules of the resource; errors lists each field, rule and message.")
+	ErrPatchFailed          = kind("patch_failed", http.StatusUnprocessableEntity, "patch can´t be applied", "The patch document is well formed but cannot be applied: a path does not exist or a test operation failed.")
+	ErrIdempotencyKeyReused = kind("idempotency_key_reused", http.StatusUnprocessableEntity, "idempotency key reused", "The Idempotency-Key was already used with a different request; send a new key for a new request.")
+	ErrUnprocessable        = kind("unprocessable_entity", http.StatusUnprocessableEntity, "unprocessable entity", "The record is well formed but references another one that does not exist or does not fit.")
+	ErrFailedDependency     = kind("failed_dependency", http.StatusFailedDependency, "failed dependency", "The operation was not applied because another one it depends on failed, e.g. in an atomic bulk request or a batch.")
+	ErrInternal             = kind("internal_error", http.StatusInternalServerError, "internal error", "An unexpected error; the details are in the server log under the request id.")
+	ErrUpstream             = kind("upstream_error", http.StatusBadGateway, "upstream error", "The upstream answered with an unexpected error.")
+	ErrUpstreamUnavailable  = kind("upstream_unavailable", http.StatusServiceUnavailable, "upstream unavailable", "The upstream cannot be reached or its circuit is open; retry after the Retry-After header.")
+)
+
+// Catalog devuelve los tipos de error documentados
+func Catalog() []*Error {
+	return append([]*Error(nil), catalog...)
+}
+
+// New crea un error del tipo kind con message; message se traduce con el catálogo de i18n
+func New(kind *Error, message string) *Error {
+	return &Error{Code: kind.Code, Status: kind.Status, Title: kind.Title, message: message, format: message, kind: kind}
+}
+
+// Newf crea un error del tipo kind con el mensaje de fmt.Errorf, que puede envolver otro
+// error con %w. El formato se traduce con el catálogo de i18n al responder.
+func Newf(kind *Error, format string, args ...any) *Error {
+	err := fmt.Errorf(format, args...)
+	e := New(kind, err.Error())
+	e.format, e.args = format, args
+	e.err = errors.Unwrap(err)
+	return e
 }
 
-func NewAppError(message []string, errorMessage string, statusCode int) *AppError {
-	return &AppError{
-		Message:    message,
-		Error:      errorMessage,
-		StatusCode: statusCode,
+// FromStatus convierte el status de una respuesta del upstream en el error de dominio
+// equivalente, así un 404 del upstream se responde como 404 y no como un error interno
+func FromStatus(status int, format string, args ...any) *Error {
+	switch status {
+	case http.StatusBadRequest:
+		return Newf(ErrBadRequest, format, args...)
+	case http.StatusUnauthorized:
+		return Newf(ErrUnauthorized, format, args...)
+	case http.StatusForbidden:
+		return Newf(ErrForbidden, format, args...)
+	case http.StatusNotFound, http.StatusGone:
+		return Newf(ErrNotFound, format, args...)
+	case http.StatusConflict:
+		return Newf(ErrConflict, format, args...)
+	case http.StatusPreconditionFailed:
+		return Newf(ErrPreconditionFailed, format, args...)
+	case http.StatusRequestEntityTooLarge:
+		return Newf(ErrPayloadTooLarge, format, args...)
+	case http.StatusUnsupportedMediaType:
+		return Newf(ErrUnsupportedMediaType, format, args...)
+	case http.StatusUnprocessableEntity:
+		return Newf(ErrUnprocessable, format, args...)
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return Newf(ErrUpstreamUnavailable, format, args...)
+	default:
+		return Newf(ErrUpstream, format, args...)
 	}
 }
@@ -0,0 +1,63 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	err := fmt.Errorf("Post can´t be found. %w", New(ErrNotFound, "post 7 not found"))
+	require.ErrorIs(t, err, ErrNotFound)
+	require.NotErrorIs(t, err, ErrConflict)
+
+	var domain *Error
+	require.True(t, errors.As(err, &domain))
+	require.Equal(t, Code("not_found"), domain.Code)
+	require.Equal(t, http.StatusNotFound, domain.Status)
+	require.Equal(t, "post 7 not found", domain.Error())
+}
+
+func TestNewf(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := Newf(ErrUpstreamUnavailable, "posts can´t be listed: %w", cause)
+	require.EqualError(t, err, "posts can´t be listed: connection refused")
+	require.ErrorIs(t, err, ErrUpstreamUnavailable)
+	require.ErrorIs(t, err, cause)
+}
+
+func TestFromStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   *Error
+	}{
+		{http.StatusBadRequest, ErrBadRequest},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusGone, ErrNotFound},
+		{http.StatusConflict, ErrConflict},
+		{http.StatusUnprocessableEntity, ErrUnprocessable},
+		{http.StatusTooManyRequests, ErrUpstreamUnavailable},
+		{http.StatusGatewayTimeout, ErrUpstreamUnavailable},
+		{http.StatusInternalServerError, ErrUpstream},
+		{http.StatusTeapot, ErrUpstream},
+	}
+	for _, tc := range tests {
+		err := FromStatus(tc.status, "upstream answered")
+		require.ErrorIs(t, err, tc.want, tc.status)
+		require.Equal(t, tc.want.Status, err.Status, tc.status)
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	codes := map[Code]bool{}
+	for _, kind := range Catalog() {
+		require.False(t, codes[kind.Code], "duplicated code %s", kind.Code)
+		codes[kind.Code] = true
+		require.NotEmpty(t, kind.Title, kind.Code)
+		require.NotEmpty(t, kind.Description, kind.Code)
+	}
+	require.True(t, codes["validation_failed"])
+}
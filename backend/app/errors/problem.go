@@ -0,0 +1,141 @@
+package errors
+
+import (
+	"blog-api/app/i18n"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// ContentType es el tipo de las respuestas de error (RFC 7807)
+const ContentType = "application/problem+json"
+
+// TypePrefix es la ruta donde se documenta cada código; el type de un problem es TypePrefix + código
+const TypePrefix = "/problems/"
+
+// Problem es el cuerpo de las respuestas de error según RFC 7807. Además de los campos
+// estándar tiene el código estable, el id de la solicitud y los campos inválidos.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      Code         `json:"code"`
+	RequestID string       `json:"requestId,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// NewProblem arma el problem de err en la solicitud r, en el idioma negociado por
+// i18n.Middleware. Si err no es un error de dominio se informa como fallback, o como
+// ErrInternal si fallback es nil; el detalle de los errores internos no se expone y
+// solo queda en el log.
+func NewProblem(r *http.Request, err error, fallback *Error) *Problem {
+	lang := i18n.Language(r.Context())
+	domain, typed := kindOf(err)
+	if !typed {
+		domain = fallback
+		if domain == nil {
+			domain = ErrInternal
+		}
+	}
+	problem := &Problem{
+		Type:      TypePrefix + string(domain.Code),
+		Title:     i18n.Translate(lang, domain.Title),
+		Status:    domain.Status,
+		Instance:  r.URL.Path,
+		Code:      domain.Code,
+		RequestID: middleware.GetReqID(r.Context()),
+	}
+	if typed || domain.Status < http.StatusInternalServerError {
+		problem.Detail = i18n.Message(lang, err)
+	}
+	var fielder Fielder
+	if errors.As(err, &fielder) {
+		problem.Errors = fielder.FieldErrors(lang)
+	}
+	return problem
+}
+
+// kindOf busca el error de dominio de err: uno de este paquete en la cadena o, si no
+// hay, el tipo del catálogo con el que err se declara equivalente con Is
+func kindOf(err error) (*Error, bool) {
+	var domain *Error
+	if errors.As(err, &domain) {
+		return domain, true
+	}
+	for _, kind := range catalog {
+		if errors.Is(err, kind) {
+			return kind, true
+		}
+	}
+	return nil, false
+}
+
+// Write responde err como application/problem+json; ver NewProblem
+func Write(w http.ResponseWriter, r *http.Request, err error, fallback *Error) {
+	problem := NewProblem(r, err, fallback)
+	if problem.Status >= http.StatusInternalServerError {
+		slog.Error("request failed", "method", r.Method, "path", r.URL.Path, "requestId", problem.RequestID, "status", problem.Status, "error", err)
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
+}
+
+// NotFound responde las rutas que no existen
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	Write(w, r, Newf(ErrNotFound, "no route for %s", r.URL.Path), nil)
+}
+
+// MethodNotAllowed responde los métodos que la ruta no acepta
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	Write(w, r, Newf(ErrMethodNotAllowed, "%s is not supported on %s", r.Method, r.URL.Path), nil)
+}
+
+// entry es un código del catálogo tal como se documenta
+type entry struct {
+	Type        string `json:"type"`
+	Code        Code   `json:"code"`
+	Status      int    `json:"status"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+func newEntry(lang string, kind *Error) entry {
+	return entry{
+		Type:        TypePrefix + string(kind.Code),
+		Code:        kind.Code,
+		Status:      kind.Status,
+		Title:       i18n.Translate(lang, kind.Title),
+		Description: i18n.Translate(lang, kind.Description),
+	}
+}
+
+// CatalogHandler responde el catálogo de códigos de error; con el parámetro {code}
+// responde solo ese código, así el type de cada problem lleva a su documentación
+func CatalogHandler(w http.ResponseWriter, r *http.Request) {
+	code := Code(chi.URLParam(r, "code"))
+	lang := i18n.Language(r.Context())
+	w.Header().Set("Content-Type", "application/json")
+	if code == "" {
+		entries := make([]entry, len(catalog))
+		for i, kind := range catalog {
+			entries[i] = newEntry(lang, kind)
+		}
+		json.NewEncoder(w).Encode(entries)
+		return
+	}
+	for _, kind := range catalog {
+		if kind.Code == code {
+			json.NewEncoder(w).Encode(newEntry(lang, kind))
+			return
+		}
+	}
+	Write(w, r, Newf(ErrNotFound, "unknown error code %s", code), nil)
+}
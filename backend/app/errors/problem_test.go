@@ -0,0 +1,102 @@
+package errors
+
+import (
+	"blog-api/app/i18n"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// invalid es un error de otro paquete que se declara equivalente a ErrValidation
+type invalid struct{}
+
+func (invalid) Error() string { return "validation failed: title is required" }
+
+func (invalid) Is(target error) bool { return target == ErrValidation }
+
+func (invalid) FieldErrors(lang string) []FieldError {
+	return []FieldError{{Field: "title", Rule: "required", Message: "title is required"}}
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		fallback *Error
+		want     string
+	}{
+		{"domain error", New(ErrNotFound, "post 7 not found"), ErrInternal, `{
+			"type": "/problems/not_found", "title": "Not Found", "status": 404,
+			"detail": "post 7 not found", "instance": "/v1/posts/7", "code": "not_found"
+		}`},
+		{"equivalent error", invalid{}, nil, `{
+			"type": "/problems/validation_failed", "title": "Unprocessable Entity", "status": 422,
+			"detail": "validation failed: title is required", "instance": "/v1/posts/7", "code": "validation_failed",
+			"errors": [{"field": "title", "rule": "required", "message": "title is required"}]
+		}`},
+		{"fallback", errors.New("post can´t be found"), ErrNotFound, `{
+			"type": "/problems/not_found", "title": "Not Found", "status": 404,
+			"detail": "post can´t be found", "instance": "/v1/posts/7", "code": "not_found"
+		}`},
+		// El detalle de los errores internos solo queda en el log
+		{"internal", errors.New("sql: database is closed"), nil, `{
+			"type": "/problems/internal_error", "title": "Internal Server Error", "status": 500,
+			"instance": "/v1/posts/7", "code": "internal_error"
+		}`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRecorder := httptest.NewRecorder()
+			Write(mockRecorder, httptest.NewRequest(http.MethodGet, "/v1/posts/7", nil), tc.err, tc.fallback)
+			require.Equal(t, ContentType, mockRecorder.Header().Get("Content-Type"))
+			require.JSONEq(t, tc.want, mockRecorder.Body.String())
+		})
+	}
+}
+
+func TestWrite_Localized(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/v1/posts/7", nil)
+	r = r.WithContext(i18n.NewContext(r.Context(), i18n.Spanish))
+	mockRecorder := httptest.NewRecorder()
+	err := i18n.Errorf("%s can´t be found. %w", "Post", New(ErrNotFound, "not found"))
+	Write(mockRecorder, r, err, ErrInternal)
+	require.JSONEq(t, `{
+		"type": "/problems/not_found", "title": "No encontrado", "status": 404,
+		"detail": "No se encontró el recurso Post. no encontrado", "instance": "/v1/posts/7", "code": "not_found"
+	}`, mockRecorder.Body.String())
+}
+
+func TestCatalogHandler(t *testing.T) {
+	r := chi.NewRouter()
+	r.NotFound(NotFound)
+	r.Get("/problems", CatalogHandler)
+	r.Get("/problems/{code}", CatalogHandler)
+
+	mockRecorder := httptest.NewRecorder()
+	r.ServeHTTP(mockRecorder, httptest.NewRequest(http.MethodGet, "/problems", nil))
+	var entries []map[string]any
+	require.NoError(t, json.Unmarshal(mockRecorder.Body.Bytes(), &entries))
+	require.Len(t, entries, len(Catalog()))
+
+	mockRecorder = httptest.NewRecorder()
+	r.ServeHTTP(mockRecorder, httptest.NewRequest(http.MethodGet, "/problems/conflict", nil))
+	require.Equal(t, http.StatusOK, mockRecorder.Code)
+	require.JSONEq(t, `{
+		"type": "/problems/conflict", "code": "conflict", "status": 409, "title": "Conflict",
+		"description": "The request conflicts with the current state of the resource."
+	}`, mockRecorder.Body.String())
+
+	mockRecorder = httptest.NewRecorder()
+	r.ServeHTTP(mockRecorder, httptest.NewRequest(http.MethodGet, "/problems/nope", nil))
+	require.Equal(t, http.StatusNotFound, mockRecorder.Code)
+
+	mockRecorder = httptest.NewRecorder()
+	r.ServeHTTP(mockRecorder, httptest.NewRequest(http.MethodGet, "/nope", nil))
+	require.Equal(t, http.StatusNotFound, mockRecorder.Code)
+	require.Equal(t, ContentType, mockRecorder.Header().Get("Content-Type"))
+}
@@ -0,0 +1,157 @@
+package expand
+
+import (
+	apperrors "blog-api/app/errors"
+	"blog-api/app/i18n"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const (
+	// MaxDepth es la cantidad máxima de niveles anidados, p. ej. post.user son dos
+	MaxDepth = 2
+	// MaxRelations es la cantidad máxima de relaciones pedidas en una solicitud
+	MaxRelations = 8
+	// MaxConcurrency es la cantidad máxima de llamadas simultáneas para cargar una relación
+	MaxConcurrency = 8
+	// MaxIDs es la cantidad máxima de elementos distintos que se cargan por relación;
+	// cada uno es una llamada, así una lista sin paginar no multiplica las del upstream
+	MaxIDs = 25
+)
+
+// ErrInvalid indica que el parámetro expand no es válido
+var ErrInvalid = apperrors.New(apperrors.ErrBadRequest, "invalid expand")
+
+// Tree son las relaciones pedidas; cada una con las que se piden dentro de ella.
+// ?expand=user,comments.user es {"user": {}, "comments": {"user": {}}}
+type Tree map[string]Tree
+
+// Parse lee las relaciones de los query params expand y embed, separadas por comas
+func Parse(query url.Values) (Tree, error) {
+	tree := Tree{}
+	count := 0
+	for _, value := range append(query["expand"], query["embed"]...) {
+		for _, path := range strings.Split(value, ",") {
+			path = strings.TrimSpace(path)
+			if path == "" {
+				continue
+			}
+			names := strings.Split(path, ".")
+			if len(names) > MaxDepth {
+				return nil, i18n.Errorf("%w: %q is nested deeper than %d levels", ErrInvalid, path, MaxDepth)
+			}
+			node := tree
+			for _, name := range names {
+				if name == "" {
+					return nil, i18n.Errorf("%w: %q has an empty relation", ErrInvalid, path)
+				}
+				if _, ok := node[name]; !ok {
+					node[name] = Tree{}
+					count++
+				}
+				node = node[name]
+			}
+		}
+	}
+	if count > MaxRelations {
+		return nil, i18n.Errorf("%w: more than %d relations", ErrInvalid, MaxRelations)
+	}
+	return tree, nil
+}
+
+// Relation carga los recursos relacionados de items y los agrega en objects bajo name
+type Relation[T any] interface {
+	embed(ctx context.Context, items []T, objects []map[string]any, name string, nested Tree) error
+	validate(nested Tree) error
+}
+
+// Resolver conoce las relaciones que se pueden incrustar en un recurso
+type Resolver[T any] struct {
+	relations map[string]Relation[T]
+}
+
+// NewResolver crea un resolver sin relaciones
+func NewResolver[T any]() *Resolver[T] {
+	return &Resolver[T]{relations: map[string]Relation[T]{}}
+}
+
+// Add registra la relación name
+func (r *Resolver[T]) Add(name string, relation Relation[T]) *Resolver[T] {
+	r.relations[name] = relation
+	return r
+}
+
+// Expand devuelve la representación JSON de cada elemento con las relaciones de tree incrustadas.
+// Antes de cargar nada comprueba que todas las relaciones de tree existan.
+func (r *Resolver[T]) Expand(ctx context.Context, items []T, tree Tree) ([]map[string]any, error) {
+	if err := r.Validate(tree); err != nil {
+		return nil, err
+	}
+	objects, err := toObjects(items)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(tree))
+	for name := range tree {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := r.relations[name].embed(ctx, items, objects, name, tree[name]); err != nil {
+			return nil, err
+		}
+	}
+	return objects, nil
+}
+
+// Validate devuelve un error ErrInvalid si tree pide una relación que no existe
+func (r *Resolver[T]) Validate(tree Tree) error {
+	for name, nested := range tree {
+		relation, ok := r.relations[name]
+		if !ok {
+			return i18n.Errorf("%w: unknown relation %q", ErrInvalid, name)
+		}
+		if err := relation.validate(nested); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate es Validate para un resolver opcional, sin relaciones anidadas
+func validate[T any](resolver *Resolver[T], tree Tree) error {
+	if resolver == nil {
+		if len(tree) > 0 {
+			return i18n.Errorf("%w: nested relations are not supported here", ErrInvalid)
+		}
+		return nil
+	}
+	return resolver.Validate(tree)
+}
+
+// expandAll es Expand para un resolver opcional: sin relaciones solo convierte a JSON
+func expandAll[T any](ctx context.Context, resolver *Resolver[T], items []T, tree Tree) ([]map[string]any, error) {
+	if resolver == nil {
+		return toObjects(items)
+	}
+	return resolver.Expand(ctx, items, tree)
+}
+
+func toObjects[T any](items []T) ([]map[string]any, error) {
+	raw, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	// Los ids se mantienen como números enteros en la respuesta
+	decoder.UseNumber()
+	objects := []map[string]any{}
+	if err := decoder.Decode(&objects); err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
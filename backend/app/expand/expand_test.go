@@ -0,0 +1,208 @@
+package expand
+
+import (
+	apperrors "blog-api/app/errors"
+	"blog-api/app/pagination"
+	resource "blog-api/app/v1/resource/service"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type author struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type book struct {
+	ID       int    `json:"id"`
+	AuthorID int    `json:"authorId"`
+	Title    string `json:"title"`
+}
+
+type review struct {
+	ID     int `json:"id"`
+	BookID int `json:"bookId"`
+	Stars  int `json:"stars"`
+}
+
+type library struct {
+	authors map[int]author
+	reviews []review
+	gets    atomic.Int32
+	lists   atomic.Int32
+}
+
+func (l *library) getAuthor(ctx context.Context, id int) (*author, error) {
+	l.gets.Add(1)
+	if id < 0 {
+		return nil, errors.New("Author can´t be found. Status Code: 502")
+	}
+	a, ok := l.authors[id]
+	if !ok {
+		return nil, apperrors.New(apperrors.ErrNotFound, "Author can´t be found. Status Code: 404")
+	}
+	return &a, nil
+}
+
+func (l *library) listReviews(ctx context.Context, filter resource.Filter, page pagination.Pagination) (*[]review, int, error) {
+	l.lists.Add(1)
+	items := []review{}
+	for _, r := range l.reviews {
+		if strconv.Itoa(r.BookID) == filter["bookId"] {
+			items = append(items, r)
+		}
+	}
+	return &items, len(items), nil
+}
+
+func newLibrary() (*library, *Resolver[book], *Resolver[review]) {
+	l := &library{
+		authors: map[int]author{1: {ID: 1, Name: "Ana"}, 2: {ID: 2, Name: "Bea"}},
+		reviews: []review{{ID: 1, BookID: 1, Stars: 5}, {ID: 2, BookID: 1, Stars: 3}, {ID: 3, BookID: 3, Stars: 4}},
+	}
+	books := NewResolver[book]()
+	reviews := NewResolver[review]()
+	books.
+		Add("author", BelongsTo(func(b book) int { return b.AuthorID }, l.getAuthor, nil)).
+		Add("reviews", HasMany(func(b book) int { return b.ID }, "bookId", l.listReviews, reviews))
+	bookByID := func(ctx context.Context, id int) (*book, error) {
+		return &book{ID: id, AuthorID: id, Title: "book " + strconv.Itoa(id)}, nil
+	}
+	reviews.Add("book", BelongsTo(func(r review) int { return r.BookID }, bookByID, books))
+	return l, books, reviews
+}
+
+func toJSON(t *testing.T, v any) string {
+	raw, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(raw)
+}
+
+func TestParse(t *testing.T) {
+	tree, err := Parse(url.Values{"expand": {"author, reviews.book"}, "embed": {"author"}})
+	require.NoError(t, err)
+	require.Equal(t, Tree{"author": {}, "reviews": {"book": {}}}, tree)
+
+	tree, err = Parse(url.Values{})
+	require.NoError(t, err)
+	require.Empty(t, tree)
+
+	for _, value := range []string{"a.b.c", "a..b", "a,b,c,d,e,f,g,h,i"} {
+		_, err := Parse(url.Values{"expand": {value}})
+		require.ErrorIs(t, err, ErrInvalid, value)
+	}
+}
+
+func TestResolver_BelongsTo(t *testing.T) {
+	l, books, _ := newLibrary()
+	items := []book{{ID: 1, AuthorID: 1}, {ID: 2, AuthorID: 2}, {ID: 3, AuthorID: 1}, {ID: 4}}
+	objects, err := books.Expand(context.Background(), items, Tree{"author": {}})
+	require.NoError(t, err)
+	require.JSONEq(t, `[
+		{"id": 1, "authorId": 1, "title": "", "author": {"id": 1, "name": "Ana"}},
+		{"id": 2, "authorId": 2, "title": "", "author": {"id": 2, "name": "Bea"}},
+		{"id": 3, "authorId": 1, "title": "", "author": {"id": 1, "name": "Ana"}},
+		{"id": 4, "authorId": 0, "title": "", "author": null}
+	]`, toJSON(t, objects))
+	// Cada autor se carga una sola vez
+	require.Equal(t, int32(2), l.gets.Load())
+}
+
+func TestResolver_HasMany(t *testing.T) {
+	l, books, _ := newLibrary()
+	objects, err := books.Expand(context.Background(), []book{{ID: 1}, {ID: 2}, {ID: 1}}, Tree{"reviews": {}})
+	require.NoError(t, err)
+	require.JSONEq(t, `[
+		{"id": 1, "authorId": 0, "title": "", "reviews": [{"id": 1, "bookId": 1, "stars": 5}, {"id": 2, "bookId": 1, "stars": 3}]},
+		{"id": 2, "authorId": 0, "title": "", "reviews": []},
+		{"id": 1, "authorId": 0, "title": "", "reviews": [{"id": 1, "bookId": 1, "stars": 5}, {"id": 2, "bookId": 1, "stars": 3}]}
+	]`, toJSON(t, objects))
+	require.Equal(t, int32(2), l.lists.Load())
+}
+
+func TestResolver_Nested(t *testing.T) {
+	_, _, reviews := newLibrary()
+	objects, err := reviews.Expand(context.Background(), []review{{ID: 3, BookID: 3, Stars: 4}}, Tree{"book": {"reviews": {}}})
+	require.NoError(t, err)
+	require.JSONEq(t, `[{"id": 3, "bookId": 3, "stars": 4, "book": {
+		"id": 3, "authorId": 3, "title": "book 3", "reviews": [{"id": 3, "bookId": 3, "stars": 4}]
+	}}]`, toJSON(t, objects))
+
+	_, err = reviews.Expand(context.Background(), []review{{ID: 3, BookID: 3}}, Tree{"book": {"author": {"books": {}}}})
+	require.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestResolver_Errors(t *testing.T) {
+	_, books, _ := newLibrary()
+	_, err := books.Expand(context.Background(), []book{{ID: 1}}, Tree{"publisher": {}})
+	require.ErrorIs(t, err, ErrInvalid)
+
+	_, err = books.Expand(context.Background(), []book{{ID: 1, AuthorID: -1}}, Tree{"author": {}})
+	require.EqualError(t, err, "Author can´t be found. Status Code: 502")
+
+	// Un recurso relacionado que no existe se incrusta como null
+	objects, err := books.Expand(context.Background(), []book{{ID: 1, AuthorID: 9}, {ID: 2, AuthorID: 1}}, Tree{"author": {}})
+	require.NoError(t, err)
+	require.JSONEq(t, `[
+		{"id": 1, "authorId": 9, "title": "", "author": null},
+		{"id": 2, "authorId": 1, "title": "", "author": {"id": 1, "name": "Ana"}}
+	]`, toJSON(t, objects))
+}
+
+func TestResolver_MaxIDs(t *testing.T) {
+	l, books, _ := newLibrary()
+	items := make([]book, MaxIDs+1)
+	for i := range items {
+		items[i] = book{ID: i + 1, AuthorID: i + 1}
+	}
+	_, err := books.Expand(context.Background(), items, Tree{"reviews": {}})
+	require.ErrorIs(t, err, ErrInvalid)
+	_, err = books.Expand(context.Background(), items, Tree{"author": {}})
+	require.ErrorIs(t, err, ErrInvalid)
+	require.Zero(t, l.gets.Load()+l.lists.Load(), "nothing is loaded above the limit")
+
+	_, err = books.Expand(context.Background(), items[:MaxIDs], Tree{"reviews": {}})
+	require.NoError(t, err)
+}
+
+func TestForEach_BoundsConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	running, peak := 0, 0
+	err := forEach(context.Background(), 50, func(ctx context.Context, i int) error {
+		mu.Lock()
+		running++
+		if running > peak {
+			peak = running
+		}
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil
+	})
+	require.NoError(t, err)
+	require.LessOrEqual(t, peak, MaxConcurrency)
+
+	failed := errors.New("failed")
+	var calls atomic.Int32
+	err = forEach(context.Background(), 50, func(ctx context.Context, i int) error {
+		calls.Add(1)
+		if i == 0 {
+			return failed
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	require.ErrorIs(t, err, failed)
+	require.Less(t, calls.Load(), int32(50))
+}
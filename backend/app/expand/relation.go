@@ -0,0 +1,197 @@
+package expand
+
+import (
+	apperrors "blog-api/app/errors"
+	"blog-api/app/i18n"
+	"blog-api/app/pagination"
+	resource "blog-api/app/v1/resource/service"
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+)
+
+// belongsTo incrusta el recurso al que apunta una clave del elemento, p. ej. el user de un post
+type belongsTo[T any, P any] struct {
+	key      func(item T) int
+	get      func(ctx context.Context, id int) (*P, error)
+	resolver *Resolver[P]
+}
+
+// BelongsTo crea la relación con el recurso cuyo id es key(item); get lo carga y
+// resolver, si no es nil, permite pedir relaciones dentro de él
+func BelongsTo[T any, P any](key func(item T) int, get func(ctx context.Context, id int) (*P, error), resolver *Resolver[P]) Relation[T] {
+	return &belongsTo[T, P]{key: key, get: get, resolver: resolver}
+}
+
+func (b *belongsTo[T, P]) validate(nested Tree) error {
+	return validate(b.resolver, nested)
+}
+
+// embed incrusta null en los elementos cuyo recurso no existe, p. ej. porque se borró
+func (b *belongsTo[T, P]) embed(ctx context.Context, items []T, objects []map[string]any, name string, nested Tree) error {
+	ids, err := unique(items, b.key, name)
+	if err != nil {
+		return err
+	}
+	parents := make([]*P, len(ids))
+	err = forEach(ctx, len(ids), func(ctx context.Context, i int) error {
+		parent, err := b.get(ctx, ids[i])
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		parents[i] = parent
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	var found []P
+	for _, parent := range parents {
+		if parent != nil {
+			found = append(found, *parent)
+		}
+	}
+	expanded, err := expandAll(ctx, b.resolver, found, nested)
+	if err != nil {
+		return err
+	}
+	byID := make(map[int]map[string]any, len(ids))
+	for i, id := range ids {
+		if parents[i] != nil {
+			byID[id] = expanded[0]
+			expanded = expanded[1:]
+		}
+	}
+	for i, item := range items {
+		if parent, ok := byID[b.key(item)]; ok {
+			objects[i][name] = parent
+		} else {
+			objects[i][name] = nil
+		}
+	}
+	return nil
+}
+
+// hasMany incrusta los recursos que apuntan al elemento, p. ej. los comments de un post
+type hasMany[T any, C any] struct {
+	key      func(item T) int
+	field    string
+	list     func(ctx context.Context, filter resource.Filter, page pagination.Pagination) (*[]C, int, error)
+	resolver *Resolver[C]
+}
+
+// HasMany crea la relación con los recursos que list devuelve filtrando field por key(item);
+// resolver, si no es nil, permite pedir relaciones dentro de ellos
+func HasMany[T any, C any](key func(item T) int, field string, list func(ctx context.Context, filter resource.Filter, page pagination.Pagination) (*[]C, int, error), resolver *Resolver[C]) Relation[T] {
+	return &hasMany[T, C]{key: key, field: field, list: list, resolver: resolver}
+}
+
+func (h *hasMany[T, C]) validate(nested Tree) error {
+	return validate(h.resolver, nested)
+}
+
+func (h *hasMany[T, C]) embed(ctx context.Context, items []T, objects []map[string]any, name string, nested Tree) error {
+	ids, err := unique(items, h.key, name)
+	if err != nil {
+		return err
+	}
+	children := make([][]C, len(ids))
+	err = forEach(ctx, len(ids), func(ctx context.Context, i int) error {
+		list, _, err := h.list(ctx, resource.Filter{h.field: strconv.Itoa(ids[i])}, pagination.Pagination{})
+		if err != nil {
+			return err
+		}
+		if list != nil {
+			children[i] = *list
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// Los hijos de todos los elementos se expanden juntos para compartir las cargas anidadas
+	var all []C
+	for _, list := range children {
+		all = append(all, list...)
+	}
+	expanded, err := expandAll(ctx, h.resolver, all, nested)
+	if err != nil {
+		return err
+	}
+	byID := make(map[int][]map[string]any, len(ids))
+	for i, id := range ids {
+		byID[id] = expanded[:len(children[i]):len(children[i])]
+		expanded = expanded[len(children[i]):]
+	}
+	for i, item := range items {
+		list, ok := byID[h.key(item)]
+		if !ok || list == nil {
+			list = []map[string]any{}
+		}
+		objects[i][name] = list
+	}
+	return nil
+}
+
+// unique devuelve las claves distintas de items, sin los ceros, en el orden en que aparecen.
+// Si son más de MaxIDs devuelve un error ErrInvalid para la relación name.
+func unique[T any](items []T, key func(item T) int, name string) ([]int, error) {
+	seen := map[int]bool{}
+	var ids []int
+	for _, item := range items {
+		id := key(item)
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if len(ids) > MaxIDs {
+		return nil, i18n.Errorf("%w: %q would load more than %d items, request a smaller page", ErrInvalid, name, MaxIDs)
+	}
+	return ids, nil
+}
+
+// forEach llama a fn para cada índice de 0 a n-1, con hasta MaxConcurrency llamadas a la vez.
+// El primer error cancela las llamadas pendientes y es el que se devuelve.
+func forEach(ctx context.Context, n int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	slots := make(chan struct{}, MaxConcurrency)
+	for i := 0; i < n; i++ {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			if err := fn(ctx, i); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(i)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,189 @@
+package fixtures
+
+import (
+	"blog-api/app/clients/restclient"
+	"blog-api/app/config"
+	"blog-api/app/repository"
+	"blog-api/data"
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+)
+
+// Format es el formato de un archivo de fixtures
+type Format string
+
+const (
+	// JSON es un único objeto con un arreglo por recurso
+	JSON Format = "json"
+	// NDJSON es un elemento por línea: {"resource": "posts", "item": {...}}. Los cambios
+	// del overlay tienen resource "overlay" y el cambio como item.
+	NDJSON Format = "ndjson"
+)
+
+// FormatFor devuelve el formato pedido, o el que corresponde a la extensión de path si name está vacío
+func FormatFor(name string, path string) (Format, error) {
+	switch name {
+	case "":
+		switch filepath.Ext(path) {
+		case ".ndjson", ".jsonl":
+			return NDJSON, nil
+		}
+		return JSON, nil
+	case string(JSON), string(NDJSON):
+		return Format(name), nil
+	default:
+		return "", fmt.Errorf("unknown format %q, expected json or ndjson", name)
+	}
+}
+
+// line es una línea de un archivo NDJSON
+type line struct {
+	Resource string          `json:"resource"`
+	Item     json.RawMessage `json:"item"`
+}
+
+// Write escribe snapshot en w con el formato dado
+func Write(w io.Writer, snapshot *repository.Snapshot, format Format) error {
+	if format == JSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(snapshot)
+	}
+	encoder := json.NewEncoder(w)
+	write := func(resource string, items any) error {
+		raw, err := json.Marshal(items)
+		if err != nil {
+			return err
+		}
+		var elements []json.RawMessage
+		if err := json.Unmarshal(raw, &elements); err != nil {
+			return err
+		}
+		for _, item := range elements {
+			if err := encoder.Encode(line{Resource: resource, Item: item}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, resource := range []struct {
+		name  string
+		items any
+	}{
+		{"users", snapshot.Users},
+		{"posts", snapshot.Posts},
+		{"albums", snapshot.Albums},
+		{"photos", snapshot.Photos},
+		{"comments", snapshot.Comments},
+		{"todos", snapshot.Todos},
+		{"overlay", snapshot.Overlay},
+	} {
+		if err := write(resource.name, resource.items); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Read lee un snapshot de r con el formato dado. Un archivo sin cambios del overlay
+// tiene la lista vacía, así al importarlo reemplaza los que haya.
+func Read(r io.Reader, format Format) (*repository.Snapshot, error) {
+	snapshot := &repository.Snapshot{Overlay: []repository.OverlayChange{}}
+	if format == JSON {
+		if err := json.NewDecoder(r).Decode(snapshot); err != nil {
+			return nil, err
+		}
+		if snapshot.Overlay == nil {
+			snapshot.Overlay = []repository.OverlayChange{}
+		}
+		return snapshot, nil
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var l line
+		if err := json.Unmarshal(scanner.Bytes(), &l); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		var err error
+		switch l.Resource {
+		case "users":
+			snapshot.Users, err = appendItem(snapshot.Users, l.Item)
+		case "posts":
+			snapshot.Posts, err = appendItem(snapshot.Posts, l.Item)
+		case "albums":
+			snapshot.Albums, err = appendItem(snapshot.Albums, l.Item)
+		case "photos":
+			snapshot.Photos, err = appendItem(snapshot.Photos, l.Item)
+		case "comments":
+			snapshot.Comments, err = appendItem(snapshot.Comments, l.Item)
+		case "todos":
+			snapshot.Todos, err = appendItem(snapshot.Todos, l.Item)
+		case "overlay":
+			snapshot.Overlay, err = appendItem(snapshot.Overlay, l.Item)
+		default:
+			err = fmt.Errorf("unknown resource %q", l.Resource)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+	}
+	return snapshot, scanner.Err()
+}
+
+func appendItem[T any](items []T, raw json.RawMessage) ([]T, error) {
+	var item T
+	if err := json.Unmarshal(raw, &item); err != nil {
+		return items, err
+	}
+	return append(items, item), nil
+}
+
+// FromUpstream descarga las colecciones completas de todos los recursos del upstream configurado
+func FromUpstream(ctx context.Context, client restclient.IRestClient, cfg *config.Config) (*repository.Snapshot, error) {
+	snapshot := &repository.Snapshot{}
+	var err error
+	if snapshot.Users, err = fetch[data.User](ctx, client, cfg.Upstream("users").URL()); err != nil {
+		return nil, err
+	}
+	if snapshot.Posts, err = fetch[data.Post](ctx, client, cfg.Upstream("posts").URL()); err != nil {
+		return nil, err
+	}
+	if snapshot.Albums, err = fetch[data.Album](ctx, client, cfg.Upstream("albums").URL()); err != nil {
+		return nil, err
+	}
+	if snapshot.Photos, err = fetch[data.Photo](ctx, client, cfg.Upstream("photos").URL()); err != nil {
+		return nil, err
+	}
+	if snapshot.Comments, err = fetch[data.Comment](ctx, client, cfg.Upstream("comments").URL()); err != nil {
+		return nil, err
+	}
+	if snapshot.Todos, err = fetch[data.Todo](ctx, client, cfg.Upstream("todos").URL()); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+func fetch[T any](ctx context.Context, client restclient.IRestClient, url string) ([]T, error) {
+	resp, err := client.NewRequest(ctx, http.MethodGet, url, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s. Status Code: %d", url, resp.StatusCode)
+	}
+	var items []T
+	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+		return nil, fmt.Errorf("GET %s: %w", url, err)
+	}
+	return items, nil
+}
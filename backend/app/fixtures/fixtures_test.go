@@ -0,0 +1,116 @@
+package fixtures
+
+import (
+	"blog-api/app/config"
+	"blog-api/app/mocks"
+	"blog-api/app/repository"
+	"blog-api/data"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testSnapshot() *repository.Snapshot {
+	return &repository.Snapshot{
+		Users:    []data.User{{ID: 1, Name: "Ana"}},
+		Posts:    []data.Post{{ID: 1, UserID: 1, Title: "a"}, {ID: 2, UserID: 1, Title: "b"}},
+		Albums:   []data.Album{{ID: 1, UserID: 1, Title: "album"}},
+		Photos:   []data.Photo{{ID: 1, AlbumID: 1, Title: "photo", URL: "https://example.com/1.png"}},
+		Comments: []data.Comment{{ID: 1, PostID: 1, Name: "comment", Email: "ana@example.com"}},
+		Todos:    []data.Todo{{ID: 1, UserID: 1, Title: "todo"}},
+		Overlay:  []repository.OverlayChange{},
+	}
+}
+
+func TestFormatFor(t *testing.T) {
+	for path, expected := range map[string]Format{"dump.ndjson": NDJSON, "dump.jsonl": NDJSON, "dump.json": JSON, "": JSON} {
+		format, err := FormatFor("", path)
+		require.NoError(t, err)
+		require.Equal(t, expected, format)
+	}
+	format, err := FormatFor("ndjson", "dump.json")
+	require.NoError(t, err)
+	require.Equal(t, NDJSON, format)
+	_, err = FormatFor("yaml", "")
+	require.Error(t, err)
+}
+
+func TestWriteRead(t *testing.T) {
+	for _, format := range []Format{JSON, NDJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, Write(&buf, testSnapshot(), format))
+			snapshot, err := Read(&buf, format)
+			require.NoError(t, err)
+			require.Equal(t, testSnapshot(), snapshot)
+		})
+	}
+	t.Run("ndjson lines", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, Write(&buf, &repository.Snapshot{Posts: []data.Post{{ID: 1, UserID: 1, Title: "a"}}}, NDJSON))
+		require.JSONEq(t, `{"resource": "posts", "item": {"id": 1, "userId": 1, "title": "a", "body": ""}}`, buf.String())
+	})
+	t.Run("overlay", func(t *testing.T) {
+		snapshot := &repository.Snapshot{Overlay: []repository.OverlayChange{
+			{Resource: "posts", ID: 1, Item: json.RawMessage(`{"id": 1, "title": "local"}`), UpdatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+			{Resource: "posts", ID: 2, Deleted: true, UpdatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		}}
+		for _, format := range []Format{JSON, NDJSON} {
+			var buf bytes.Buffer
+			require.NoError(t, Write(&buf, snapshot, format))
+			read, err := Read(&buf, format)
+			require.NoError(t, err)
+			require.Len(t, read.Overlay, 2)
+			require.JSONEq(t, `{"id": 1, "title": "local"}`, string(read.Overlay[0].Item))
+			require.Equal(t, snapshot.Overlay[1], read.Overlay[1])
+		}
+	})
+	t.Run("unknown resource", func(t *testing.T) {
+		_, err := Read(strings.NewReader(`{"resource": "videos", "item": {}}`), NDJSON)
+		require.EqualError(t, err, `line 1: unknown resource "videos"`)
+	})
+}
+
+func TestFromUpstream(t *testing.T) {
+	cfg := &config.Config{}
+	mockClient := mocks.NewIRestClient(t)
+	bodies := map[string]string{
+		"users":    `[{"id": 1, "name": "Ana"}]`,
+		"posts":    `[{"id": 1, "userId": 1, "title": "a"}, {"id": 2, "userId": 1, "title": "b"}]`,
+		"albums":   `[{"id": 1, "userId": 1, "title": "album"}]`,
+		"photos":   `[{"id": 1, "albumId": 1, "title": "photo", "url": "https://example.com/1.png"}]`,
+		"comments": `[{"id": 1, "postId": 1, "name": "comment", "email": "ana@example.com"}]`,
+		"todos":    `[{"id": 1, "userId": 1, "title": "todo"}]`,
+	}
+	for resource, body := range bodies {
+		mockClient.On("NewRequest", mock.Anything, "GET", cfg.Upstream(resource).URL(), mock.Anything, mock.Anything).Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil).Once()
+	}
+	snapshot, err := FromUpstream(context.Background(), mockClient, cfg)
+	require.NoError(t, err)
+	// El upstream no tiene cambios del overlay, así seed deja los que haya
+	expected := testSnapshot()
+	expected.Overlay = nil
+	require.Equal(t, expected, snapshot)
+}
+
+func TestFromUpstream_Error(t *testing.T) {
+	cfg := &config.Config{}
+	mockClient := mocks.NewIRestClient(t)
+	mockClient.On("NewRequest", mock.Anything, "GET", cfg.Upstream("users").URL(), mock.Anything, mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusBadGateway,
+		Body:       io.NopCloser(strings.NewReader("")),
+	}, nil).Once()
+	_, err := FromUpstream(context.Background(), mockClient, cfg)
+	require.Error(t, err)
+}
@@ -0,0 +1,46 @@
+package health
+
+import (
+	"blog-api/app/clients/restclient"
+	"encoding/json"
+	"net/http"
+)
+
+// BreakerSource expone el estado de los circuitos del cliente upstream
+type BreakerSource interface {
+	Breakers() []restclient.BreakerStatus
+}
+
+// Health es la respuesta del endpoint de salud
+type Health struct {
+	Status    string                     `json:"status"`
+	Upstreams []restclient.BreakerStatus `json:"upstreams"`
+}
+
+// HealthHandler informa la salud de la API y de sus upstreams
+type HealthHandler struct {
+	upstream BreakerSource
+}
+
+// NewHealthHandler crea el manejador a partir del cliente upstream
+func NewHealthHandler(upstream BreakerSource) *HealthHandler {
+	return &HealthHandler{upstream: upstream}
+}
+
+// GetHealth godoc
+// @Description  Handler to get the API health and the circuit state of each upstream
+// @Tags Health
+// @Produce      json
+// @Success      200
+// @Router       /health [get] .
+func (hh *HealthHandler) GetHealth(w http.ResponseWriter, r *http.Request) {
+	health := Health{Status: "ok", Upstreams: hh.upstream.Breakers()}
+	for _, upstream := range health.Upstreams {
+		if upstream.State != restclient.StateClosed {
+			health.Status = "degraded"
+		}
+	}
+	// La API sigue respondiendo con el circuito abierto, por eso siempre es 200
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(health)
+}
@@ -0,0 +1,37 @@
+package health
+
+import (
+	"blog-api/app/clients/restclient"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type breakerSource []restclient.BreakerStatus
+
+func (s breakerSource) Breakers() []restclient.BreakerStatus {
+	return s
+}
+
+func TestGetHealth(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		handler := NewHealthHandler(breakerSource{{Host: "posts.local", State: restclient.StateClosed}})
+		mockRecorder := httptest.NewRecorder()
+		handler.GetHealth(mockRecorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+		require.Equal(t, http.StatusOK, mockRecorder.Code)
+		require.JSONEq(t, `{"status": "ok", "upstreams": [{"host": "posts.local", "state": "closed", "failures": 0}]}`, mockRecorder.Body.String())
+	})
+	t.Run("degraded", func(t *testing.T) {
+		handler := NewHealthHandler(breakerSource{
+			{Host: "posts.local", State: restclient.StateClosed},
+			{Host: "users.local", State: restclient.StateHalfOpen, Failures: 5},
+		})
+		mockRecorder := httptest.NewRecorder()
+		handler.GetHealth(mockRecorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+		require.Equal(t, http.StatusOK, mockRecorder.Code)
+		require.Contains(t, mockRecorder.Body.String(), `"status":"degraded"`)
+		require.Contains(t, mockRecorder.Body.String(), `"state":"half-open"`)
+	})
+}
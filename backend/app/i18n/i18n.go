@@ -0,0 +1,161 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Idiomas de los mensajes. Los textos se escriben en inglés en el código y sirven de
+// clave en los catálogos de los demás idiomas, como en gettext.
+const (
+	English = "en"
+	Spanish = "es"
+	// Default es el idioma cuando Accept-Language no pide ninguno de los disponibles
+	Default = English
+)
+
+// catalogs son las traducciones de cada idioma; el inglés no tiene catálogo
+var catalogs = map[string]map[string]string{
+	Spanish: spanish,
+}
+
+var matcher = language.NewMatcher([]language.Tag{language.English, language.Spanish})
+
+type contextKey struct{}
+
+// Negotiate elige el idioma de la respuesta según el header Accept-Language,
+// p. ej. "es-AR,es;q=0.9,en;q=0.8" elige es y "fr" elige Default
+func Negotiate(header string) string {
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil || len(tags) == 0 {
+		return Default
+	}
+	tag, _, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Default
+	}
+	base, _ := tag.Base()
+	return base.String()
+}
+
+// NewContext guarda el idioma de la solicitud en ctx
+func NewContext(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, contextKey{}, lang)
+}
+
+// Language obtiene el idioma guardado por Middleware, o Default si no hay
+func Language(ctx context.Context) string {
+	if lang, ok := ctx.Value(contextKey{}).(string); ok {
+		return lang
+	}
+	return Default
+}
+
+// Middleware negocia el idioma de la solicitud, lo deja en el contexto y lo informa
+// en Content-Language
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang := Negotiate(r.Header.Get("Accept-Language"))
+		w.Header().Set("Content-Language", lang)
+		w.Header().Add("Vary", "Accept-Language")
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), lang)))
+	})
+}
+
+// Translate traduce text a lang. Busca en cada idioma de Fallbacks y, como el inglés
+// no tiene catálogo, si ninguno lo tiene devuelve text.
+func Translate(lang string, text string) string {
+	for _, candidate := range Fallbacks(lang) {
+		if translated, ok := catalogs[candidate][text]; ok {
+			return translated
+		}
+	}
+	return text
+}
+
+// Fallbacks es la cadena de idiomas en que se busca un mensaje de lang: el propio
+// idioma, su idioma base y Default, p. ej. es-AR, es y en
+func Fallbacks(lang string) []string {
+	chain := []string{lang}
+	if base, _, ok := strings.Cut(lang, "-"); ok {
+		chain = append(chain, base)
+	}
+	if chain[len(chain)-1] != Default {
+		chain = append(chain, Default)
+	}
+	return chain
+}
+
+// Localizer es un mensaje, o un error, que se puede escribir en otro idioma
+type Localizer interface {
+	Localize(lang string) string
+}
+
+// Sprintf es fmt.Sprintf con format traducido a lang. Los argumentos que son Localizer
+// o errores también se traducen; %w se escribe como %v.
+func Sprintf(lang string, format string, args ...any) string {
+	translated := Translate(lang, format)
+	if len(args) == 0 {
+		return translated
+	}
+	localized := make([]any, len(args))
+	for i, arg := range args {
+		switch value := arg.(type) {
+		case Localizer:
+			localized[i] = value.Localize(lang)
+		case error:
+			localized[i] = Message(lang, value)
+		default:
+			localized[i] = arg
+		}
+	}
+	return fmt.Sprintf(strings.ReplaceAll(translated, "%w", "%v"), localized...)
+}
+
+// Message es el mensaje de err en lang. Los errores creados con Errorf se traducen por
+// partes; el resto se busca entero en el catálogo, p. ej. los de errors.New.
+func Message(lang string, err error) string {
+	if localizer, ok := err.(Localizer); ok {
+		return localizer.Localize(lang)
+	}
+	return Translate(lang, err.Error())
+}
+
+// Error es un error de fmt.Errorf que recuerda su formato para traducirse después,
+// cuando se conoce el idioma de la respuesta
+type Error struct {
+	format string
+	args   []any
+	err    error
+}
+
+// Errorf crea un error como fmt.Errorf, envolviendo los errores de %w, que se puede
+// traducir con Message
+func Errorf(format string, args ...any) error {
+	return &Error{format: format, args: args, err: fmt.Errorf(format, args...)}
+}
+
+func (e *Error) Error() string {
+	return e.err.Error()
+}
+
+func (e *Error) Unwrap() []error {
+	switch wrapped := e.err.(type) {
+	case interface{ Unwrap() []error }:
+		return wrapped.Unwrap()
+	case interface{ Unwrap() error }:
+		if err := wrapped.Unwrap(); err != nil {
+			return []error{err}
+		}
+	}
+	return nil
+}
+
+// Localize escribe el error en lang
+func (e *Error) Localize(lang string) string {
+	return Sprintf(lang, e.format, e.args...)
+}
@@ -0,0 +1,73 @@
+package i18n
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := map[string]string{
+		"":                            English,
+		"es":                          Spanish,
+		"es-AR,es;q=0.9,en;q=0.8":     Spanish,
+		"en-US,en;q=0.9,es;q=0.8":     English,
+		"fr-FR,fr;q=0.9":              Default,
+		"fr-FR,fr;q=0.9,es;q=0.5":     Spanish,
+		"*":                           Default,
+		"not a language header;;q=x,": Default,
+	}
+	for header, want := range tests {
+		require.Equal(t, want, Negotiate(header), header)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	require.Equal(t, "No encontrado", Translate(Spanish, "Not Found"))
+	// es-AR no tiene catálogo propio y usa el de es
+	require.Equal(t, "No encontrado", Translate("es-AR", "Not Found"))
+	require.Equal(t, "Not Found", Translate(English, "Not Found"))
+	// Sin traducción se responde el texto en inglés
+	require.Equal(t, "Teapot", Translate(Spanish, "Teapot"))
+	require.Equal(t, []string{"es-AR", "es", "en"}, Fallbacks("es-AR"))
+	require.Equal(t, []string{"en"}, Fallbacks(English))
+}
+
+func TestErrorf(t *testing.T) {
+	cause := errors.New("not found")
+	err := Errorf("%s can´t be found. %w", "Post", cause)
+	require.ErrorIs(t, err, cause)
+	require.EqualError(t, err, "Post can´t be found. not found")
+	require.Equal(t, "Post can´t be found. not found", Message(English, err))
+	require.Equal(t, "No se encontró el recurso Post. no encontrado", Message(Spanish, err))
+
+	// Los errores envueltos también se traducen
+	wrapped := Errorf("%w: %q is nested deeper than %d levels", Errorf("invalid expand"), "a.b.c", 2)
+	require.Equal(t, `expand inválido: "a.b.c" tiene más de 2 niveles`, Message(Spanish, wrapped))
+}
+
+// Las traducciones deben tener los mismos verbos que su clave para que Sprintf no falle
+func TestSpanish_Verbs(t *testing.T) {
+	verbs := regexp.MustCompile(`%[a-z]`)
+	for key, text := range spanish {
+		require.Equal(t, verbs.FindAllString(key, -1), verbs.FindAllString(text, -1), key)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var lang string
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang = Language(r.Context())
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/v1/posts", nil)
+	req.Header.Set("Accept-Language", "es-MX,es;q=0.9")
+	mockRecorder := httptest.NewRecorder()
+	handler.ServeHTTP(mockRecorder, req)
+	require.Equal(t, Spanish, lang)
+	require.Equal(t, Spanish, mockRecorder.Header().Get("Content-Language"))
+	require.Equal(t, "Accept-Language", mockRecorder.Header().Get("Vary"))
+}
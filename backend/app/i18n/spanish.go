@@ -0,0 +1,160 @@
+package i18n
+
+// spanish traduce los mensajes de la API; la clave es el texto en inglés, o el formato
+// si el mensaje tiene parámetros, y la traducción debe tener los mismos verbos en el mismo orden
+var spanish = map[string]string{
+	// Títulos de los errores, los textos de http.StatusText
+	"Bad Request":              "Solicitud incorrecta",
+	"Unauthorized":             "No autorizado",
+	"Forbidden":                "Prohibido",
+	"Not Found":                "No encontrado",
+	"Method Not Allowed":       "Método no permitido",
+	"Conflict":                 "Conflicto",
+	"Precondition Failed":      "Falló la precondición",
+	"Request Entity Too Large": "Contenido demasiado grande",
+	"Unsupported Media Type":   "Tipo de contenido no soportado",
+	"Unprocessable Entity":     "Entidad no procesable",
+	"Failed Dependency":        "Falló una dependencia",
+	"Internal Server Error":    "Error interno del servidor",
+	"Bad Gateway":              "Puerta de enlace incorrecta",
+	"Service Unavailable":      "Servicio no disponible",
+
+	// Mensajes de los tipos de error
+	"bad request":            "solicitud incorrecta",
+	"unauthorized":           "no autorizado",
+	"forbidden":              "prohibido",
+	"not found":              "no encontrado",
+	"method not allowed":     "método no permitido",
+	"conflict":               "conflicto",
+	"precondition failed":    "falló la precondición",
+	"payload too large":      "contenido demasiado grande",
+	"unsupported media type": "tipo de contenido no soportado",
+	"validation failed":      "validación fallida",
+	"patch can´t be applied": "no se puede aplicar el patch",
+	"idempotency key reused": "clave de idempotencia reutilizada",
+	"unprocessable entity":   "entidad no procesable",
+	"failed dependency":      "falló una dependencia",
+	"internal error":         "error interno",
+	"upstream error":         "error del upstream",
+	"upstream unavailable":   "upstream no disponible",
+
+	// Descripciones del catálogo de errores
+	"The request is malformed: a body that is not JSON, a value of the wrong type or an invalid query parameter.": "La solicitud está mal formada: un cuerpo que no es JSON, un valor de tipo incorrecto o un parámetro de consulta inválido.",
+	"The request lacks valid credentials.":                                                                               "La solicitud no tiene credenciales válidas.",
+	"The credentials do not allow this operation.":                                                                       "Las credenciales no permiten esta operación.",
+	"The resource, or the parent in a nested route, does not exist.":                                                     "El recurso, o el padre en una ruta anidada, no existe.",
+	"The route does not support this HTTP method.":                                                                       "La ruta no admite este método HTTP.",
+	"The request conflicts with the current state of the resource.":                                                      "La solicitud entra en conflicto con el estado actual del recurso.",
+	"If-Match does not match the current ETag: the resource changed since it was read. Read it again and retry.":         "If-Match no coincide con el ETag actual: el recurso cambió después de leerlo. Vuelva a leerlo y reintente.",
+	"The body or uploaded file exceeds the allowed size.":                                                                "El cuerpo o el archivo subido supera el tamaño permitido.",
+	"The body or uploaded file has a type the endpoint does not accept.":                                                 "El cuerpo o el archivo subido es de un tipo que el endpoint no acepta.",
+	"One or more fields break the rules of the resource; errors lists each field, rule and message.":                     "Uno o más campos no cumplen las reglas del recurso; errors lista cada campo, regla y mensaje.",
+	"The patch document is well formed but cannot be applied: a path does not exist or a test operation failed.":         "El documento de patch está bien formado pero no se puede aplicar: una ruta no existe o falló una operación test.",
+	"The Idempotency-Key was already used with a different request; send a new key for a new request.":                   "La Idempotency-Key ya se usó con otra solicitud; envíe una clave nueva para una solicitud nueva.",
+	"The record is well formed but references another one that does not exist or does not fit.":                          "El registro está bien formado pero referencia a otro que no existe o no corresponde.",
+	"The operation was not applied because another one it depends on failed, e.g. in an atomic bulk request or a batch.": "La operación no se aplicó porque falló otra de la que depende, p. ej. en una solicitud bulk atómica o un batch.",
+	"An unexpected error; the details are in the server log under the request id.":                                       "Un error inesperado; el detalle está en el log del servidor con el id de la solicitud.",
+	"The upstream answered with an unexpected error.":                                                                    "El upstream respondió con un error inesperado.",
+	"The upstream cannot be reached or its circuit is open; retry after the Retry-After header.":                         "No se puede acceder al upstream o su circuito está abierto; reintente después del header Retry-After.",
+
+	// Manejadores
+	"request body must be a valid JSON object": "el cuerpo de la solicitud debe ser un objeto JSON válido",
+	"%s must be a %s":                          "%s debe ser de tipo %s",
+	"%s must be an integer":                    "%s debe ser un número entero",
+	"no route for %s":                          "no existe la ruta %s",
+	"%s is not supported on %s":                "%s no está soportado en %s",
+	"unknown error code %s":                    "código de error desconocido %s",
+	"unknown resource %q":                      "recurso desconocido %q",
+	"depth must be between 1 and %d":           "depth debe estar entre 1 y %d",
+	"order must be asc or desc":                "order debe ser asc o desc",
+	"invalid multipart form: %w":               "formulario multipart inválido: %w",
+	"image uploads need local or overlay mode, the upstream doesn´t keep new photos": "las subidas de imágenes necesitan el modo local u overlay, el upstream no guarda las photos nuevas",
+	"title and file are required":               "title y file son obligatorios",
+	"file is larger than %d bytes":              "el archivo supera los %d bytes",
+	"upstream unavailable, retry in %d seconds": "upstream no disponible, reintente en %d segundos",
+	"%s is not a patch type, use %s":            "%s no es un tipo de patch, use %s",
+
+	// Patch
+	"invalid patch document":                 "documento de patch inválido",
+	"%w: the result is not a valid %T":       "%w: el resultado no es un %T válido",
+	"%w: operation %d (%s %s): %w":           "%w: operación %d (%s %s): %w",
+	"%w: operation %d: %w":                   "%w: operación %d: %w",
+	"%w: unexpected data after the document": "%w: hay datos después del documento",
+	"%s needs a path":                        "%s necesita un path",
+	"%s needs a from":                        "%s necesita un from",
+	"%s needs a value":                       "%s necesita un value",
+	"can´t move %s into itself":              "no se puede mover %s dentro de sí mismo",
+	"unknown op %q":                          "op desconocida %q",
+	"test failed, the value is %s":           "falló test, el valor es %s",
+	"%q is not a JSON pointer":               "%q no es un JSON pointer",
+	"the path does not exist":                "la ruta no existe",
+	"the whole document can´t be removed":    "no se puede eliminar el documento completo",
+	"%q is not an array index":               "%q no es una posición de array",
+	"index %d is out of range":               "la posición %d está fuera de rango",
+
+	// Servicios
+	"%ss can´t be listed. %w":                                       "No se pueden listar los %ss. %w",
+	"%s can´t be found. %w":                                         "No se encontró el recurso %s. %w",
+	"%s can´t be created. %w":                                       "No se pudo crear el recurso %s. %w",
+	"%s can´t be updated. %w":                                       "No se pudo actualizar el recurso %s. %w",
+	"%s can´t be deleted. %w":                                       "No se pudo eliminar el recurso %s. %w",
+	"%ss can´t be listed. Status Code: %d":                          "No se pueden listar los %ss. Código de estado: %d",
+	"%s can´t be found. Status Code: %d":                            "No se encontró el recurso %s. Código de estado: %d",
+	"%s can´t be created. Status Code: %d":                          "No se pudo crear el recurso %s. Código de estado: %d",
+	"%s can´t be updated. Status Code: %d":                          "No se pudo actualizar el recurso %s. Código de estado: %d",
+	"%s can´t be deleted. Status Code: %d":                          "No se pudo eliminar el recurso %s. Código de estado: %d",
+	"validation failed: %s":                                         "validación fallida: %s",
+	"parent comment can´t be found":                                 "no se encontró el comment padre",
+	"%w: a comment can´t reply to itself":                           "%w: un comment no puede responderse a sí mismo",
+	"%w: a comment can´t reply to one of its replies":               "%w: un comment no puede responder a una de sus respuestas",
+	"%w: a reply must belong to the same post as its parent":        "%w: una respuesta debe pertenecer al mismo post que su padre",
+	"%w: parent comment %d can´t be found. %w":                      "%w: no se encontró el comment padre %d. %w",
+	"unsupported image type":                                        "tipo de imagen no soportado",
+	"image dimensions too large":                                    "las dimensiones de la imagen son demasiado grandes",
+	"blob not found":                                                "archivo no encontrado",
+	"invalid expand":                                                "expand inválido",
+	"%w: %q would load more than %d items, request a smaller page":  "%w: %q cargaría más de %d elementos, pida una página más chica",
+	"%w: %q is nested deeper than %d levels":                        "%w: %q tiene más de %d niveles",
+	"%w: %q has an empty relation":                                  "%w: %q tiene una relación vacía",
+	"%w: more than %d relations":                                    "%w: más de %d relaciones",
+	"%w: unknown relation %q":                                       "%w: relación desconocida %q",
+	"%w: nested relations are not supported here":                   "%w: aquí no se admiten relaciones anidadas",
+	"%w: this resource has no relations":                            "%w: este recurso no tiene relaciones",
+	"invalid page %q":                                               "page inválido %q",
+	"invalid limit %q":                                              "limit inválido %q",
+	"invalid cursor":                                                "cursor inválido",
+	"the resource has changed":                                      "el recurso cambió",
+	"%w, the current ETag is %s":                                    "%w, el ETag actual es %s",
+	"the item was changed by another request":                       "otra solicitud cambió el elemento",
+	"the Idempotency-Key was already used with a different request": "la Idempotency-Key ya se usó con otra solicitud",
+	"a request with this Idempotency-Key is still in progress":      "una solicitud con esta Idempotency-Key todavía está en curso",
+	"%s must be at most %d characters":                              "%s debe tener como máximo %d caracteres",
+	"request body is larger than %d bytes":                          "el cuerpo de la solicitud supera los %d bytes",
+	"invalid atomic %q":                                             "atomic inválido %q",
+	"atomic bulk requests need a local database":                    "las solicitudes bulk atómicas necesitan una base de datos local",
+	"%s is not a bulk type, use application/json or %s":             "%s no es un tipo de bulk, use application/json o %s",
+	"request body must be a JSON array of operations":               "el cuerpo de la solicitud debe ser un array JSON de operaciones",
+	"a bulk request can have at most %d operations":                 "una solicitud bulk puede tener como máximo %d operaciones",
+	"operation %d must be a valid JSON object":                      "la operación %d debe ser un objeto JSON válido",
+	"request body has data after the operations":                    "el cuerpo de la solicitud tiene datos después de las operaciones",
+	"a bulk request needs at least one operation":                   "una solicitud bulk necesita al menos una operación",
+	"not applied because operation %d failed":                       "no se aplicó porque falló la operación %d",
+	"%s needs an id":                                                "%s necesita un id",
+	"%s needs an item":                                              "%s necesita un item",
+	"unknown op %q, use create, update or delete":                   "op desconocida %q, use create, update o delete",
+	"item must be a valid JSON object":                              "item debe ser un objeto JSON válido",
+	"invalid %s %q":                                                 "%s inválido %q",
+	"a batch needs at least one request":                            "un batch necesita al menos una solicitud",
+	"a batch can have at most %d requests":                          "un batch puede tener como máximo %d solicitudes",
+	"request %d: id %q is already used":                             "solicitud %d: el id %q ya se usa",
+	"request %d: method %q is not supported":                        "solicitud %d: el método %q no está soportado",
+	"request %d: path must start with /":                            "solicitud %d: el path debe empezar con /",
+	"batch requests cannot be nested":                               "los batch no se pueden anidar",
+	"request %d: batch requests cannot be nested":                   "solicitud %d: los batch no se pueden anidar",
+	"request %d: invalid reference %s, use {{id.body.field}}":       "solicitud %d: referencia inválida %s, use {{id.body.campo}}",
+	"request %d: unknown request %q":                                "solicitud %d: solicitud desconocida %q",
+	"the requests have circular dependencies":                       "las solicitudes tienen dependencias circulares",
+	"not run because request %q failed":                             "no se atendió porque falló la solicitud %q",
+	"reference %s does not exist":                                   "la referencia %s no existe",
+	"path must start with /":                                        "el path debe empezar con /",
+}
@@ -0,0 +1,211 @@
+package idempotency
+
+import (
+	apperrors "blog-api/app/errors"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	// Header es el header con la clave que elige el cliente, p. ej. un UUID por cada alta
+	Header = "Idempotency-Key"
+	// ReplayedHeader marca las respuestas que se repiten desde el almacenamiento
+	ReplayedHeader = "Idempotent-Replayed"
+)
+
+// Valores por defecto de Options
+const (
+	DefaultTTL      = 24 * time.Hour
+	DefaultInFlight = time.Minute
+	DefaultWait     = 10 * time.Second
+	DefaultMaxBody  = 16 << 20
+)
+
+// maxKeyLength es el largo máximo de una clave
+const maxKeyLength = 255
+
+var (
+	// ErrKeyReused indica que la clave ya se usó con otra solicitud
+	ErrKeyReused = apperrors.New(apperrors.ErrIdempotencyKeyReused, "the Idempotency-Key was already used with a different request")
+	// ErrInProgress indica que la solicitud original todavía no terminó
+	ErrInProgress = apperrors.New(apperrors.ErrConflict, "a request with this Idempotency-Key is still in progress")
+)
+
+// Options configura Middleware. Los valores en cero usan los valores por defecto.
+type Options struct {
+	// TTL es el tiempo que se guarda la respuesta de una clave
+	TTL time.Duration
+	// InFlight es el tiempo máximo que una clave queda en curso; si la solicitud no
+	// termina antes, p. ej. porque el proceso se cayó, la clave se puede volver a usar
+	InFlight time.Duration
+	// Wait es lo que una solicitud repetida espera a la original en curso antes de responder 409
+	Wait time.Duration
+	// MaxBody es el tamaño máximo del cuerpo que se lee para comparar las solicitudes
+	MaxBody int64
+}
+
+func (o Options) withDefaults() Options {
+	if o.TTL <= 0 {
+		o.TTL = DefaultTTL
+	}
+	if o.InFlight <= 0 {
+		o.InFlight = DefaultInFlight
+	}
+	if o.Wait <= 0 {
+		o.Wait = DefaultWait
+	}
+	if o.MaxBody <= 0 {
+		o.MaxBody = DefaultMaxBody
+	}
+	return o
+}
+
+// Middleware hace idempotentes los POST que traen Idempotency-Key. La primera solicitud
+// con una clave se atiende y su respuesta (status, headers y cuerpo) se guarda en store;
+// las repeticiones con el mismo método, URL y cuerpo reciben esa respuesta sin volver a
+// crear nada, y con otra solicitud responden 422. Una repetición que llega mientras la
+// original está en curso la espera hasta opts.Wait y después responde 409. Las
+// respuestas 5xx no se guardan, así la solicitud se puede reintentar con la misma clave.
+func Middleware(store Store, opts Options) func(http.Handler) http.Handler {
+	opts = opts.withDefaults()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(Header)
+			if key == "" || r.Method != http.MethodPost {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxKeyLength {
+				apperrors.Write(w, r, apperrors.Newf(apperrors.ErrBadRequest, "%s must be at most %d characters", Header, maxKeyLength), nil)
+				return
+			}
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, opts.MaxBody))
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					apperrors.Write(w, r, apperrors.Newf(apperrors.ErrPayloadTooLarge, "request body is larger than %d bytes", opts.MaxBody), nil)
+					return
+				}
+				apperrors.Write(w, r, err, apperrors.ErrBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			fingerprint := Fingerprint(r, body)
+
+			existing, err := reserve(r.Context(), store, key, fingerprint, opts)
+			if err != nil {
+				apperrors.Write(w, r, err, apperrors.ErrInternal)
+				return
+			}
+			if existing != nil {
+				replay(w, existing)
+				return
+			}
+			serve(w, r, next, store, key, fingerprint, opts)
+		})
+	}
+}
+
+// Fingerprint identifica una solicitud por su método, su URL y su cuerpo
+func Fingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	io.WriteString(hash, r.Method+" "+r.URL.RequestURI()+"\n")
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// reserve reserva key para esta solicitud y devuelve nil, o devuelve la respuesta guardada
+// de una solicitud igual. Mientras la original está en curso consulta de nuevo con
+// esperas crecientes, hasta opts.Wait.
+func reserve(ctx context.Context, store Store, key string, fingerprint string, opts Options) (*Record, error) {
+	deadline := time.Now().Add(opts.Wait)
+	delay := 10 * time.Millisecond
+	for {
+		existing, err := store.Reserve(ctx, key, Record{Fingerprint: fingerprint}, opts.InFlight)
+		if err != nil || existing == nil {
+			return nil, err
+		}
+		if existing.Fingerprint != fingerprint {
+			return nil, ErrKeyReused
+		}
+		if existing.Done {
+			return existing, nil
+		}
+		if time.Now().Add(delay).After(deadline) {
+			return nil, ErrInProgress
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, 250*time.Millisecond)
+	}
+}
+
+// serve atiende la solicitud y guarda su respuesta; si falla con 5xx o con un panic
+// libera la clave
+func serve(w http.ResponseWriter, r *http.Request, next http.Handler, store Store, key string, fingerprint string, opts Options) {
+	// La clave se guarda o se libera aunque el cliente se desconecte
+	ctx := context.WithoutCancel(r.Context())
+	recorder := &recorder{ResponseWriter: w}
+	completed := false
+	defer func() {
+		if !completed {
+			store.Release(ctx, key)
+		}
+	}()
+	next.ServeHTTP(recorder, r)
+	if recorder.status == 0 {
+		recorder.WriteHeader(http.StatusOK)
+	}
+	if recorder.status >= http.StatusInternalServerError {
+		return
+	}
+	record := Record{Fingerprint: fingerprint, Done: true, Status: recorder.status, Header: recorder.header, Body: recorder.body.Bytes()}
+	completed = store.Complete(ctx, key, record, opts.TTL) == nil
+}
+
+// replay responde la respuesta guardada de la solicitud original
+func replay(w http.ResponseWriter, record *Record) {
+	for name, values := range record.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(record.Status)
+	w.Write(record.Body)
+}
+
+// recorder responde al cliente y a la vez copia el status, los headers y el cuerpo
+type recorder struct {
+	http.ResponseWriter
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (w *recorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+		w.header = w.Header().Clone()
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
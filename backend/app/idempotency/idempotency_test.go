@@ -0,0 +1,192 @@
+package idempotency
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// creator responde 201 con el cuerpo recibido y cuenta las veces que se llamó
+type creator struct {
+	calls   atomic.Int32
+	status  int
+	release chan struct{}
+}
+
+func (c *creator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := c.calls.Add(1)
+	if c.release != nil {
+		<-c.release
+	}
+	body, _ := io.ReadAll(r.Body)
+	status := c.status
+	if status == 0 {
+		status = http.StatusCreated
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/v1/posts/"+strconv.Itoa(int(n)))
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+func post(h http.Handler, key string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/v1/posts", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(Header, key)
+	}
+	mockRecorder := httptest.NewRecorder()
+	h.ServeHTTP(mockRecorder, req)
+	return mockRecorder
+}
+
+func TestMiddleware_Replay(t *testing.T) {
+	next := &creator{}
+	h := Middleware(NewMemory(), Options{})(next)
+
+	first := post(h, "k1", `{"title":"a"}`)
+	require.Equal(t, http.StatusCreated, first.Code)
+	require.Empty(t, first.Header().Get(ReplayedHeader))
+
+	second := post(h, "k1", `{"title":"a"}`)
+	require.Equal(t, http.StatusCreated, second.Code)
+	require.Equal(t, "true", second.Header().Get(ReplayedHeader))
+	require.Equal(t, first.Header().Get("Location"), second.Header().Get("Location"))
+	require.Equal(t, first.Body.String(), second.Body.String())
+	require.EqualValues(t, 1, next.calls.Load())
+
+	// Otra clave es otra solicitud
+	third := post(h, "k2", `{"title":"a"}`)
+	require.Equal(t, http.StatusCreated, third.Code)
+	require.Empty(t, third.Header().Get(ReplayedHeader))
+	require.EqualValues(t, 2, next.calls.Load())
+}
+
+func TestMiddleware_KeyReused(t *testing.T) {
+	next := &creator{}
+	h := Middleware(NewMemory(), Options{})(next)
+
+	require.Equal(t, http.StatusCreated, post(h, "k1", `{"title":"a"}`).Code)
+	mockRecorder := post(h, "k1", `{"title":"b"}`)
+	require.Equal(t, http.StatusUnprocessableEntity, mockRecorder.Code)
+	require.Contains(t, mockRecorder.Body.String(), `"code":"idempotency_key_reused"`)
+	require.EqualValues(t, 1, next.calls.Load())
+}
+
+func TestMiddleware_Concurrent(t *testing.T) {
+	next := &creator{release: make(chan struct{})}
+	h := Middleware(NewMemory(), Options{Wait: 5 * time.Second})(next)
+
+	var wg sync.WaitGroup
+	results := make([]*httptest.ResponseRecorder, 2)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0] = post(h, "k1", `{}`)
+	}()
+	require.Eventually(t, func() bool { return next.calls.Load() == 1 }, time.Second, time.Millisecond)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[1] = post(h, "k1", `{}`)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(next.release)
+	wg.Wait()
+
+	// La repetición espera a la original y recibe su respuesta
+	require.Equal(t, http.StatusCreated, results[0].Code)
+	require.Equal(t, http.StatusCreated, results[1].Code)
+	require.Equal(t, "true", results[1].Header().Get(ReplayedHeader))
+	require.EqualValues(t, 1, next.calls.Load())
+}
+
+func TestMiddleware_InProgress(t *testing.T) {
+	next := &creator{release: make(chan struct{})}
+	h := Middleware(NewMemory(), Options{Wait: 50 * time.Millisecond})(next)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		post(h, "k1", `{}`)
+	}()
+	require.Eventually(t, func() bool { return next.calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	mockRecorder := post(h, "k1", `{}`)
+	require.Equal(t, http.StatusConflict, mockRecorder.Code)
+	require.Contains(t, mockRecorder.Body.String(), "still in progress")
+	close(next.release)
+	<-done
+}
+
+func TestMiddleware_ServerErrorReleases(t *testing.T) {
+	next := &creator{status: http.StatusBadGateway}
+	store := NewMemory()
+	h := Middleware(store, Options{})(next)
+
+	require.Equal(t, http.StatusBadGateway, post(h, "k1", `{}`).Code)
+	require.Equal(t, 0, store.Len())
+
+	// El reintento con la misma clave vuelve a llamar al manejador
+	next.status = http.StatusCreated
+	mockRecorder := post(h, "k1", `{}`)
+	require.Equal(t, http.StatusCreated, mockRecorder.Code)
+	require.Empty(t, mockRecorder.Header().Get(ReplayedHeader))
+	require.EqualValues(t, 2, next.calls.Load())
+
+	// Una respuesta 4xx se guarda como cualquier otra
+	next.status = http.StatusUnprocessableEntity
+	require.Equal(t, http.StatusUnprocessableEntity, post(h, "k2", `{}`).Code)
+	require.Equal(t, "true", post(h, "k2", `{}`).Header().Get(ReplayedHeader))
+}
+
+func TestMiddleware_PassThrough(t *testing.T) {
+	next := &creator{}
+	h := Middleware(NewMemory(), Options{MaxBody: 8})(next)
+
+	// Sin clave, y en métodos que no son POST, no cambia nada
+	post(h, "", `{}`)
+	post(h, "", `{}`)
+	require.EqualValues(t, 2, next.calls.Load())
+	req := httptest.NewRequest(http.MethodPut, "/v1/posts/1", strings.NewReader(`{}`))
+	req.Header.Set(Header, "k1")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	require.EqualValues(t, 4, next.calls.Load())
+
+	require.Equal(t, http.StatusBadRequest, post(h, strings.Repeat("k", maxKeyLength+1), `{}`).Code)
+	require.Equal(t, http.StatusRequestEntityTooLarge, post(h, "k1", `{"title":"long"}`).Code)
+	require.EqualValues(t, 4, next.calls.Load())
+}
+
+func TestMemory_Expires(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+
+	existing, err := m.Reserve(ctx, "k1", Record{Fingerprint: "a"}, time.Minute)
+	require.NoError(t, err)
+	require.Nil(t, existing)
+	existing, err = m.Reserve(ctx, "k1", Record{Fingerprint: "b"}, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, "a", existing.Fingerprint)
+
+	require.NoError(t, m.Complete(ctx, "k1", Record{Fingerprint: "a", Done: true, Status: http.StatusCreated}, time.Hour))
+	now = now.Add(time.Hour)
+	existing, err = m.Reserve(ctx, "k1", Record{Fingerprint: "b"}, time.Minute)
+	require.NoError(t, err)
+	require.Nil(t, existing, "the key expired")
+	require.Equal(t, 1, m.Len())
+
+	require.NoError(t, m.Release(ctx, "k1"))
+	require.Equal(t, 0, m.Len())
+}
@@ -0,0 +1,102 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Record es el estado de una clave: en curso mientras se atiende la primera solicitud y
+// terminado cuando se guardó su respuesta
+type Record struct {
+	// Fingerprint identifica la solicitud que usó la clave: el método, la URL y el cuerpo
+	Fingerprint string      `json:"fingerprint"`
+	Done        bool        `json:"done"`
+	Status      int         `json:"status,omitempty"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+}
+
+// Store guarda las claves usadas. Memory es la implementación por defecto; un backend
+// tipo Redis implementa Reserve con SET NX y serializa Record.
+type Store interface {
+	// Reserve guarda record durante ttl si key no existe y devuelve nil; si ya existe no
+	// cambia nada y devuelve el registro guardado
+	Reserve(ctx context.Context, key string, record Record, ttl time.Duration) (*Record, error)
+	// Complete reemplaza el registro de key por record durante ttl
+	Complete(ctx context.Context, key string, record Record, ttl time.Duration) error
+	// Release borra key para que la solicitud se pueda reintentar
+	Release(ctx context.Context, key string) error
+}
+
+// sweepInterval es cada cuánto Memory descarta las claves vencidas
+const sweepInterval = time.Minute
+
+// Memory guarda las claves en memoria; cada una se descarta al vencer su ttl
+type Memory struct {
+	mu      sync.Mutex
+	records map[string]memoryRecord
+	swept   time.Time
+	now     func() time.Time
+}
+
+type memoryRecord struct {
+	record    Record
+	expiresAt time.Time
+}
+
+// NewMemory crea el almacenamiento en memoria
+func NewMemory() *Memory {
+	return &Memory{records: map[string]memoryRecord{}, now: time.Now}
+}
+
+// Reserve guarda record si key no existe o venció
+func (m *Memory) Reserve(ctx context.Context, key string, record Record, ttl time.Duration) (*Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	m.sweep(now)
+	if stored, ok := m.records[key]; ok && now.Before(stored.expiresAt) {
+		existing := stored.record
+		return &existing, nil
+	}
+	m.records[key] = memoryRecord{record: record, expiresAt: now.Add(ttl)}
+	return nil, nil
+}
+
+// Complete reemplaza el registro de key
+func (m *Memory) Complete(ctx context.Context, key string, record Record, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records[key] = memoryRecord{record: record, expiresAt: m.now().Add(ttl)}
+	return nil
+}
+
+// Release borra key
+func (m *Memory) Release(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.records, key)
+	return nil
+}
+
+// Len devuelve la cantidad de claves guardadas, incluidas las vencidas que no se descartaron
+func (m *Memory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.records)
+}
+
+// sweep descarta las claves vencidas, como mucho una vez por sweepInterval
+func (m *Memory) sweep(now time.Time) {
+	if now.Sub(m.swept) < sweepInterval {
+		return
+	}
+	m.swept = now
+	for key, stored := range m.records {
+		if !now.Before(stored.expiresAt) {
+			delete(m.records, key)
+		}
+	}
+}
@@ -3,7 +3,10 @@
 package mocks
 
 import (
+	pagination "blog-api/app/pagination"
+	resource "blog-api/app/v1/resource/service"
 	data "blog-api/data"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -13,9 +16,9 @@ type IAlbumService struct {
 	mock.Mock
 }
 
-// CreateAlbum provides a mock function with given fields: album
-func (_m *IAlbumService) CreateAlbum(album data.Album) (*data.Album, error) {
-	ret := _m.Called(album)
+// CreateAlbum provides a mock function with given fields: ctx, album
+func (_m *IAlbumService) CreateAlbum(ctx context.Context, album data.Album) (*data.Album, error) {
+	ret := _m.Called(ctx, album)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateAlbum")
@@ -23,19 +26,19 @@ func (_m *IAlbumService) CreateAlbum(album data.Album) (*data.Album, error) {
 
 	var r0 *data.Album
 	var r1 error
-	if rf, ok := ret.Get(0).(func(data.Album) (*data.Album, error)); ok {
-		return rf(album)
+	if rf, ok := ret.Get(0).(func(context.Context, data.Album) (*data.Album, error)); ok {
+		return rf(ctx, album)
 	}
-	if rf, ok := ret.Get(0).(func(data.Album) *data.Album); ok {
-		r0 = rf(album)
+	if rf, ok := ret.Get(0).(func(context.Context, data.Album) *data.Album); ok {
+		r0 = rf(ctx, album)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*data.Album)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(data.Album) error); ok {
-		r1 = rf(album)
+	if rf, ok := ret.Get(1).(func(context.Context, data.Album) error); ok {
+		r1 = rf(ctx, album)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -43,17 +46,17 @@ func (_m *IAlbumService) CreateAlbum(album data.Album) (*data.Album, error) {
 	return r0, r1
 }
 
-// DeleteAlbum provides a mock function with given fields: id
-func (_m *IAlbumService) DeleteAlbum(id int) error {
-	ret := _m.Called(id)
+// DeleteAlbum provides a mock function with given fields: ctx, id
+func (_m *IAlbumService) DeleteAlbum(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteAlbum")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -61,9 +64,9 @@ func (_m *IAlbumService) DeleteAlbum(id int) error {
 	return r0
 }
 
-// GetAlbum provides a mock function with given fields: id
-func (_m *IAlbumService) GetAlbum(id int) (*data.Album, error) {
-	ret := _m.Called(id)
+// GetAlbum provides a mock function with given fields: ctx, id
+func (_m *IAlbumService) GetAlbum(ctx context.Context, id int) (*data.Album, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAlbum")
@@ -71,19 +74,19 @@ func (_m *IAlbumService) GetAlbum(id int) (*data.Album, error) {
 
 	var r0 *data.Album
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) (*data.Album, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*data.Album, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(int) *data.Album); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) *data.Album); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*data.Album)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -91,29 +94,66 @@ func (_m *IAlbumService) GetAlbum(id int) (*data.Album, error) {
 	return r0, r1
 }
 
-// GetAlbums provides a mock function with given fields: title, userID, id
-func (_m *IAlbumService) GetAlbums(title string, userID int, id int) (*[]data.Album, error) {
-	ret := _m.Called(title, userID, id)
+// GetAlbums provides a mock function with given fields: ctx, filter, page
+func (_m *IAlbumService) GetAlbums(ctx context.Context, filter resource.Filter, page pagination.Pagination) (*[]data.Album, int, error) {
+	ret := _m.Called(ctx, filter, page)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAlbums")
 	}
 
 	var r0 *[]data.Album
-	var r1 error
-	if rf, ok := ret.Get(0).(func(string, int, int) (*[]data.Album, error)); ok {
-		return rf(title, userID, id)
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, resource.Filter, pagination.Pagination) (*[]data.Album, int, error)); ok {
+		return rf(ctx, filter, page)
 	}
-	if rf, ok := ret.Get(0).(func(string, int, int) *[]data.Album); ok {
-		r0 = rf(title, userID, id)
+	if rf, ok := ret.Get(0).(func(context.Context, resource.Filter, pagination.Pagination) *[]data.Album); ok {
+		r0 = rf(ctx, filter, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*[]data.Album)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, int, int) error); ok {
-		r1 = rf(title, userID, id)
+	if rf, ok := ret.Get(1).(func(context.Context, resource.Filter, pagination.Pagination) int); ok {
+		r1 = rf(ctx, filter, page)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, resource.Filter, pagination.Pagination) error); ok {
+		r2 = rf(ctx, filter, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// PatchAlbum provides a mock function with given fields: ctx, id, apply
+func (_m *IAlbumService) PatchAlbum(ctx context.Context, id int, apply func(current data.Album) (data.Album, error)) (*data.Album, error) {
+	ret := _m.Called(ctx, id, apply)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchAlbum")
+	}
+
+	var r0 *data.Album
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, func(current data.Album) (data.Album, error)) (*data.Album, error)); ok {
+		return rf(ctx, id, apply)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, func(current data.Album) (data.Album, error)) *data.Album); ok {
+		r0 = rf(ctx, id, apply)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*data.Album)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, func(current data.Album) (data.Album, error)) error); ok {
+		r1 = rf(ctx, id, apply)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -121,9 +161,9 @@ func (_m *IAlbumService) GetAlbums(title string, userID int, id int) (*[]data.Al
 	return r0, r1
 }
 
-// UpdateAlbum provides a mock function with given fields: id, album
-func (_m *IAlbumService) UpdateAlbum(id int, album data.Album) (*data.Album, error) {
-	ret := _m.Called(id, album)
+// UpdateAlbum provides a mock function with given fields: ctx, id, album
+func (_m *IAlbumService) UpdateAlbum(ctx context.Context, id int, album data.Album) (*data.Album, error) {
+	ret := _m.Called(ctx, id, album)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateAlbum")
@@ -131,19 +171,19 @@ func (_m *IAlbumService) UpdateAlbum(id int, album data.Album) (*data.Album, err
 
 	var r0 *data.Album
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int, data.Album) (*data.Album, error)); ok {
-		return rf(id, album)
+	if rf, ok := ret.Get(0).(func(context.Context, int, data.Album) (*data.Album, error)); ok {
+		return rf(ctx, id, album)
 	}
-	if rf, ok := ret.Get(0).(func(int, data.Album) *data.Album); ok {
-		r0 = rf(id, album)
+	if rf, ok := ret.Get(0).(func(context.Context, int, data.Album) *data.Album); ok {
+		r0 = rf(ctx, id, album)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*data.Album)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int, data.Album) error); ok {
-		r1 = rf(id, album)
+	if rf, ok := ret.Get(1).(func(context.Context, int, data.Album) error); ok {
+		r1 = rf(ctx, id, album)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -3,7 +3,10 @@
 package mocks
 
 import (
+	pagination "blog-api/app/pagination"
+	resource "blog-api/app/v1/resource/service"
 	data "blog-api/data"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -13,9 +16,9 @@ type ICommentService struct {
 	mock.Mock
 }
 
-// CreateComment provides a mock function with given fields: album
-func (_m *ICommentService) CreateComment(album data.Comment) (*data.Comment, error) {
-	ret := _m.Called(album)
+// CreateComment provides a mock function with given fields: ctx, comment
+func (_m *ICommentService) CreateComment(ctx context.Context, comment data.Comment) (*data.Comment, error) {
+	ret := _m.Called(ctx, comment)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateComment")
@@ -23,19 +26,19 @@ func (_m *ICommentService) CreateComment(album data.Comment) (*data.Comment, err
 
 	var r0 *data.Comment
 	var r1 error
-	if rf, ok := ret.Get(0).(func(data.Comment) (*data.Comment, error)); ok {
-		return rf(album)
+	if rf, ok := ret.Get(0).(func(context.Context, data.Comment) (*data.Comment, error)); ok {
+		return rf(ctx, comment)
 	}
-	if rf, ok := ret.Get(0).(func(data.Comment) *data.Comment); ok {
-		r0 = rf(album)
+	if rf, ok := ret.Get(0).(func(context.Context, data.Comment) *data.Comment); ok {
+		r0 = rf(ctx, comment)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*data.Comment)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(data.Comment) error); ok {
-		r1 = rf(album)
+	if rf, ok := ret.Get(1).(func(context.Context, data.Comment) error); ok {
+		r1 = rf(ctx, comment)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -43,17 +46,17 @@ func (_m *ICommentService) CreateComment(album data.Comment) (*data.Comment, err
 	return r0, r1
 }
 
-// DeleteComment provides a mock function with given fields: id
-func (_m *ICommentService) DeleteComment(id int) error {
-	ret := _m.Called(id)
+// DeleteComment provides a mock function with given fields: ctx, id
+func (_m *ICommentService) DeleteComment(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteComment")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -61,9 +64,9 @@ func (_m *ICommentService) DeleteComment(id int) error {
 	return r0
 }
 
-// GetComment provides a mock function with given fields: id
-func (_m *ICommentService) GetComment(id int) (*data.Comment, error) {
-	ret := _m.Called(id)
+// GetComment provides a mock function with given fields: ctx, id
+func (_m *ICommentService) GetComment(ctx context.Context, id int) (*data.Comment, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetComment")
@@ -71,19 +74,19 @@ func (_m *ICommentService) GetComment(id int) (*data.Comment, error) {
 
 	var r0 *data.Comment
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) (*data.Comment, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*data.Comment, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(int) *data.Comment); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) *data.Comment); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*data.Comment)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -91,29 +94,126 @@ func (_m *ICommentService) GetComment(id int) (*data.Comment, error) {
 	return r0, r1
 }
 
-// GetComments provides a mock function with given fields: title, userID, id
-func (_m *ICommentService) GetComments(title string, userID int, id int) (*[]data.Comment, error) {
-	ret := _m.Called(title, userID, id)
+// GetCommentThread provides a mock function with given fields: ctx, postID, depth, newest
+func (_m *ICommentService) GetCommentThread(ctx context.Context, postID int, depth int, newest bool) ([]*data.CommentThread, error) {
+	ret := _m.Called(ctx, postID, depth, newest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCommentThread")
+	}
+
+	var r0 []*data.CommentThread
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, bool) ([]*data.CommentThread, error)); ok {
+		return rf(ctx, postID, depth, newest)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, bool) []*data.CommentThread); ok {
+		r0 = rf(ctx, postID, depth, newest)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*data.CommentThread)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, bool) error); ok {
+		r1 = rf(ctx, postID, depth, newest)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetComments provides a mock function with given fields: ctx, filter, page
+func (_m *ICommentService) GetComments(ctx context.Context, filter resource.Filter, page pagination.Pagination) (*[]data.Comment, int, error) {
+	ret := _m.Called(ctx, filter, page)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetComments")
 	}
 
 	var r0 *[]data.Comment
-	var r1 error
-	if rf, ok := ret.Get(0).(func(string, int, int) (*[]data.Comment, error)); ok {
-		return rf(title, userID, id)
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, resource.Filter, pagination.Pagination) (*[]data.Comment, int, error)); ok {
+		return rf(ctx, filter, page)
 	}
-	if rf, ok := ret.Get(0).(func(string, int, int) *[]data.Comment); ok {
-		r0 = rf(title, userID, id)
+	if rf, ok := ret.Get(0).(func(context.Context, resource.Filter, pagination.Pagination) *[]data.Comment); ok {
+		r0 = rf(ctx, filter, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*[]data.Comment)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, int, int) error); ok {
-		r1 = rf(title, userID, id)
+	if rf, ok := ret.Get(1).(func(context.Context, resource.Filter, pagination.Pagination) int); ok {
+		r1 = rf(ctx, filter, page)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, resource.Filter, pagination.Pagination) error); ok {
+		r2 = rf(ctx, filter, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// PatchComment provides a mock function with given fields: ctx, id, apply
+func (_m *ICommentService) PatchComment(ctx context.Context, id int, apply func(current data.Comment) (data.Comment, error)) (*data.Comment, error) {
+	ret := _m.Called(ctx, id, apply)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchComment")
+	}
+
+	var r0 *data.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, func(current data.Comment) (data.Comment, error)) (*data.Comment, error)); ok {
+		return rf(ctx, id, apply)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, func(current data.Comment) (data.Comment, error)) *data.Comment); ok {
+		r0 = rf(ctx, id, apply)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*data.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, func(current data.Comment) (data.Comment, error)) error); ok {
+		r1 = rf(ctx, id, apply)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplyComment provides a mock function with given fields: ctx, parentID, reply
+func (_m *ICommentService) ReplyComment(ctx context.Context, parentID int, reply data.Comment) (*data.Comment, error) {
+	ret := _m.Called(ctx, parentID, reply)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplyComment")
+	}
+
+	var r0 *data.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, data.Comment) (*data.Comment, error)); ok {
+		return rf(ctx, parentID, reply)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, data.Comment) *data.Comment); ok {
+		r0 = rf(ctx, parentID, reply)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*data.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, data.Comment) error); ok {
+		r1 = rf(ctx, parentID, reply)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -121,9 +221,9 @@ func (_m *ICommentService) GetComments(title string, userID int, id int) (*[]dat
 	return r0, r1
 }
 
-// UpdateComment provides a mock function with given fields: id, album
-func (_m *ICommentService) UpdateComment(id int, album data.Comment) (*data.Comment, error) {
-	ret := _m.Called(id, album)
+// UpdateComment provides a mock function with given fields: ctx, id, comment
+func (_m *ICommentService) UpdateComment(ctx context.Context, id int, comment data.Comment) (*data.Comment, error) {
+	ret := _m.Called(ctx, id, comment)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateComment")
@@ -131,19 +231,19 @@ func (_m *ICommentService) UpdateComment(id int, album data.Comment) (*data.Comm
 
 	var r0 *data.Comment
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int, data.Comment) (*data.Comment, error)); ok {
-		return rf(id, album)
+	if rf, ok := ret.Get(0).(func(context.Context, int, data.Comment) (*data.Comment, error)); ok {
+		return rf(ctx, id, comment)
 	}
-	if rf, ok := ret.Get(0).(func(int, data.Comment) *data.Comment); ok {
-		r0 = rf(id, album)
+	if rf, ok := ret.Get(0).(func(context.Context, int, data.Comment) *data.Comment); ok {
+		r0 = rf(ctx, id, comment)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*data.Comment)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int, data.Comment) error); ok {
-		r1 = rf(id, album)
+	if rf, ok := ret.Get(1).(func(context.Context, int, data.Comment) error); ok {
+		r1 = rf(ctx, id, comment)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -3,7 +3,10 @@
 package mocks
 
 import (
+	pagination "blog-api/app/pagination"
+	resource "blog-api/app/v1/resource/service"
 	data "blog-api/data"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -13,9 +16,9 @@ type IPostService struct {
 	mock.Mock
 }
 
-// CreatePost provides a mock function with given fields: post
-func (_m *IPostService) CreatePost(post data.Post) (*data.Post, error) {
-	ret := _m.Called(post)
+// CreatePost provides a mock function with given fields: ctx, post
+func (_m *IPostService) CreatePost(ctx context.Context, post data.Post) (*data.Post, error) {
+	ret := _m.Called(ctx, post)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreatePost")
@@ -23,19 +26,19 @@ func (_m *IPostService) CreatePost(post data.Post) (*data.Post, error) {
 
 	var r0 *data.Post
 	var r1 error
-	if rf, ok := ret.Get(0).(func(data.Post) (*data.Post, error)); ok {
-		return rf(post)
+	if rf, ok := ret.Get(0).(func(context.Context, data.Post) (*data.Post, error)); ok {
+		return rf(ctx, post)
 	}
-	if rf, ok := ret.Get(0).(func(data.Post) *data.Post); ok {
-		r0 = rf(post)
+	if rf, ok := ret.Get(0).(func(context.Context, data.Post) *data.Post); ok {
+		r0 = rf(ctx, post)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*data.Post)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(data.Post) error); ok {
-		r1 = rf(post)
+	if rf, ok := ret.Get(1).(func(context.Context, data.Post) error); ok {
+		r1 = rf(ctx, post)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -43,17 +46,17 @@ func (_m *IPostService) CreatePost(post data.Post) (*data.Post, error) {
 	return r0, r1
 }
 
-// DeletePost provides a mock function with given fields: id
-func (_m *IPostService) DeletePost(id int) error {
-	ret := _m.Called(id)
+// DeletePost provides a mock function with given fields: ctx, id
+func (_m *IPostService) DeletePost(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeletePost")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -61,9 +64,9 @@ func (_m *IPostService) DeletePost(id int) error {
 	return r0
 }
 
-// GetPost provides a mock function with given fields: id
-func (_m *IPostService) GetPost(id int) (*data.Post, error) {
-	ret := _m.Called(id)
+// GetPost provides a mock function with given fields: ctx, id
+func (_m *IPostService) GetPost(ctx context.Context, id int) (*data.Post, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetPost")
@@ -71,19 +74,19 @@ func (_m *IPostService) GetPost(id int) (*data.Post, error) {
 
 	var r0 *data.Post
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) (*data.Post, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*data.Post, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(int) *data.Post); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) *data.Post); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*data.Post)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -91,29 +94,66 @@ func (_m *IPostService) GetPost(id int) (*data.Post, error) {
 	return r0, r1
 }
 
-// GetPosts provides a mock function with given fields: title, userID, id
-func (_m *IPostService) GetPosts(title string, userID int, id int) (*[]data.Post, error) {
-	ret := _m.Called(title, userID, id)
+// GetPosts provides a mock function with given fields: ctx, filter, page
+func (_m *IPostService) GetPosts(ctx context.Context, filter resource.Filter, page pagination.Pagination) (*[]data.Post, int, error) {
+	ret := _m.Called(ctx, filter, page)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetPosts")
 	}
 
 	var r0 *[]data.Post
-	var r1 error
-	if rf, ok := ret.Get(0).(func(string, int, int) (*[]data.Post, error)); ok {
-		return rf(title, userID, id)
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, resource.Filter, pagination.Pagination) (*[]data.Post, int, error)); ok {
+		return rf(ctx, filter, page)
 	}
-	if rf, ok := ret.Get(0).(func(string, int, int) *[]data.Post); ok {
-		r0 = rf(title, userID, id)
+	if rf, ok := ret.Get(0).(func(context.Context, resource.Filter, pagination.Pagination) *[]data.Post); ok {
+		r0 = rf(ctx, filter, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*[]data.Post)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, int, int) error); ok {
-		r1 = rf(title, userID, id)
+	if rf, ok := ret.Get(1).(func(context.Context, resource.Filter, pagination.Pagination) int); ok {
+		r1 = rf(ctx, filter, page)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, resource.Filter, pagination.Pagination) error); ok {
+		r2 = rf(ctx, filter, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// PatchPost provides a mock function with given fields: ctx, id, apply
+func (_m *IPostService) PatchPost(ctx context.Context, id int, apply func(current data.Post) (data.Post, error)) (*data.Post, error) {
+	ret := _m.Called(ctx, id, apply)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchPost")
+	}
+
+	var r0 *data.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, func(current data.Post) (data.Post, error)) (*data.Post, error)); ok {
+		return rf(ctx, id, apply)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, func(current data.Post) (data.Post, error)) *data.Post); ok {
+		r0 = rf(ctx, id, apply)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*data.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, func(current data.Post) (data.Post, error)) error); ok {
+		r1 = rf(ctx, id, apply)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -121,9 +161,9 @@ func (_m *IPostService) GetPosts(title string, userID int, id int) (*[]data.Post
 	return r0, r1
 }
 
-// UpdatePost provides a mock function with given fields: id, post
-func (_m *IPostService) UpdatePost(id int, post data.Post) (*data.Post, error) {
-	ret := _m.Called(id, post)
+// UpdatePost provides a mock function with given fields: ctx, id, post
+func (_m *IPostService) UpdatePost(ctx context.Context, id int, post data.Post) (*data.Post, error) {
+	ret := _m.Called(ctx, id, post)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdatePost")
@@ -131,19 +171,19 @@ func (_m *IPostService) UpdatePost(id int, post data.Post) (*data.Post, error) {
 
 	var r0 *data.Post
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int, data.Post) (*data.Post, error)); ok {
-		return rf(id, post)
+	if rf, ok := ret.Get(0).(func(context.Context, int, data.Post) (*data.Post, error)); ok {
+		return rf(ctx, id, post)
 	}
-	if rf, ok := ret.Get(0).(func(int, data.Post) *data.Post); ok {
-		r0 = rf(id, post)
+	if rf, ok := ret.Get(0).(func(context.Context, int, data.Post) *data.Post); ok {
+		r0 = rf(ctx, id, post)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*data.Post)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int, data.Post) error); ok {
-		r1 = rf(id, post)
+	if rf, ok := ret.Get(1).(func(context.Context, int, data.Post) error); ok {
+		r1 = rf(ctx, id, post)
 	} else {
 		r1 = ret.Error(1)
 	}
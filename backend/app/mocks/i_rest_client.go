@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	context "context"
 	io "io"
 	http "net/http"
 
@@ -14,9 +15,9 @@ type IRestClient struct {
 	mock.Mock
 }
 
-// NewRequest provides a mock function with given fields: method, url, body, headers
-func (_m *IRestClient) NewRequest(method string, url string, body io.Reader, headers map[string]string) (*http.Response, error) {
-	ret := _m.Called(method, url, body, headers)
+// NewRequest provides a mock function with given fields: ctx, method, url, body, headers
+func (_m *IRestClient) NewRequest(ctx context.Context, method string, url string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	ret := _m.Called(ctx, method, url, body, headers)
 
 	if len(ret) == 0 {
 		panic("no return value specified for NewRequest")
@@ -24,19 +25,19 @@ func (_m *IRestClient) NewRequest(method string, url string, body io.Reader, hea
 
 	var r0 *http.Response
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string, io.Reader, map[string]string) (*http.Response, error)); ok {
-		return rf(method, url, body, headers)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, io.Reader, map[string]string) (*http.Response, error)); ok {
+		return rf(ctx, method, url, body, headers)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, io.Reader, map[string]string) *http.Response); ok {
-		r0 = rf(method, url, body, headers)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, io.Reader, map[string]string) *http.Response); ok {
+		r0 = rf(ctx, method, url, body, headers)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*http.Response)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, io.Reader, map[string]string) error); ok {
-		r1 = rf(method, url, body, headers)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, io.Reader, map[string]string) error); ok {
+		r1 = rf(ctx, method, url, body, headers)
 	} else {
 		r1 = ret.Error(1)
 	}
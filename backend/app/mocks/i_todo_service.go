@@ -3,7 +3,10 @@
 package mocks
 
 import (
+	pagination "blog-api/app/pagination"
+	resource "blog-api/app/v1/resource/service"
 	data "blog-api/data"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -13,9 +16,9 @@ type ITodoService struct {
 	mock.Mock
 }
 
-// CreateTodo provides a mock function with given fields: album
-func (_m *ITodoService) CreateTodo(album data.Todo) (*data.Todo, error) {
-	ret := _m.Called(album)
+// CreateTodo provides a mock function with given fields: ctx, todo
+func (_m *ITodoService) CreateTodo(ctx context.Context, todo data.Todo) (*data.Todo, error) {
+	ret := _m.Called(ctx, todo)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateTodo")
@@ -23,19 +26,19 @@ func (_m *ITodoService) CreateTodo(album data.Todo) (*data.Todo, error) {
 
 	var r0 *data.Todo
 	var r1 error
-	if rf, ok := ret.Get(0).(func(data.Todo) (*data.Todo, error)); ok {
-		return rf(album)
+	if rf, ok := ret.Get(0).(func(context.Context, data.Todo) (*data.Todo, error)); ok {
+		return rf(ctx, todo)
 	}
-	if rf, ok := ret.Get(0).(func(data.Todo) *data.Todo); ok {
-		r0 = rf(album)
+	if rf, ok := ret.Get(0).(func(context.Context, data.Todo) *data.Todo); ok {
+		r0 = rf(ctx, todo)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*data.Todo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(data.Todo) error); ok {
-		r1 = rf(album)
+	if rf, ok := ret.Get(1).(func(context.Context, data.Todo) error); ok {
+		r1 = rf(ctx, todo)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -43,17 +46,17 @@ func (_m *ITodoService) CreateTodo(album data.Todo) (*data.Todo, error) {
 	return r0, r1
 }
 
-// DeleteTodo provides a mock function with given fields: id
-func (_m *ITodoService) DeleteTodo(id int) error {
-	ret := _m.Called(id)
+// DeleteTodo provides a mock function with given fields: ctx, id
+func (_m *ITodoService) DeleteTodo(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteTodo")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -61,9 +64,9 @@ func (_m *ITodoService) DeleteTodo(id int) error {
 	return r0
 }
 
-// GetTodo provides a mock function with given fields: id
-func (_m *ITodoService) GetTodo(id int) (*data.Todo, error) {
-	ret := _m.Called(id)
+// GetTodo provides a mock function with given fields: ctx, id
+func (_m *ITodoService) GetTodo(ctx context.Context, id int) (*data.Todo, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetTodo")
@@ -71,19 +74,19 @@ func (_m *ITodoService) GetTodo(id int) (*data.Todo, error) {
 
 	var r0 *data.Todo
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) (*data.Todo, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*data.Todo, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(int) *data.Todo); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) *data.Todo); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*data.Todo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -91,29 +94,66 @@ func (_m *ITodoService) GetTodo(id int) (*data.Todo, error) {
 	return r0, r1
 }
 
-// GetTodos provides a mock function with given fields: title, userID, id
-func (_m *ITodoService) GetTodos(title string, userID int, id int) (*[]data.Todo, error) {
-	ret := _m.Called(title, userID, id)
+// GetTodos provides a mock function with given fields: ctx, filter, page
+func (_m *ITodoService) GetTodos(ctx context.Context, filter resource.Filter, page pagination.Pagination) (*[]data.Todo, int, error) {
+	ret := _m.Called(ctx, filter, page)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetTodos")
 	}
 
 	var r0 *[]data.Todo
-	var r1 error
-	if rf, ok := ret.Get(0).(func(string, int, int) (*[]data.Todo, error)); ok {
-		return rf(title, userID, id)
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, resource.Filter, pagination.Pagination) (*[]data.Todo, int, error)); ok {
+		return rf(ctx, filter, page)
 	}
-	if rf, ok := ret.Get(0).(func(string, int, int) *[]data.Todo); ok {
-		r0 = rf(title, userID, id)
+	if rf, ok := ret.Get(0).(func(context.Context, resource.Filter, pagination.Pagination) *[]data.Todo); ok {
+		r0 = rf(ctx, filter, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*[]data.Todo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, int, int) error); ok {
-		r1 = rf(title, userID, id)
+	if rf, ok := ret.Get(1).(func(context.Context, resource.Filter, pagination.Pagination) int); ok {
+		r1 = rf(ctx, filter, page)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, resource.Filter, pagination.Pagination) error); ok {
+		r2 = rf(ctx, filter, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// PatchTodo provides a mock function with given fields: ctx, id, apply
+func (_m *ITodoService) PatchTodo(ctx context.Context, id int, apply func(current data.Todo) (data.Todo, error)) (*data.Todo, error) {
+	ret := _m.Called(ctx, id, apply)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchTodo")
+	}
+
+	var r0 *data.Todo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, func(current data.Todo) (data.Todo, error)) (*data.Todo, error)); ok {
+		return rf(ctx, id, apply)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, func(current data.Todo) (data.Todo, error)) *data.Todo); ok {
+		r0 = rf(ctx, id, apply)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*data.Todo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, func(current data.Todo) (data.Todo, error)) error); ok {
+		r1 = rf(ctx, id, apply)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -121,9 +161,9 @@ func (_m *ITodoService) GetTodos(title string, userID int, id int) (*[]data.Todo
 	return r0, r1
 }
 
-// UpdateTodo provides a mock function with given fields: id, album
-func (_m *ITodoService) UpdateTodo(id int, album data.Todo) (*data.Todo, error) {
-	ret := _m.Called(id, album)
+// UpdateTodo provides a mock function with given fields: ctx, id, todo
+func (_m *ITodoService) UpdateTodo(ctx context.Context, id int, todo data.Todo) (*data.Todo, error) {
+	ret := _m.Called(ctx, id, todo)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateTodo")
@@ -131,19 +171,19 @@ func (_m *ITodoService) UpdateTodo(id int, album data.Todo) (*data.Todo, error)
 
 	var r0 *data.Todo
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int, data.Todo) (*data.Todo, error)); ok {
-		return rf(id, album)
+	if rf, ok := ret.Get(0).(func(context.Context, int, data.Todo) (*data.Todo, error)); ok {
+		return rf(ctx, id, todo)
 	}
-	if rf, ok := ret.Get(0).(func(int, data.Todo) *data.Todo); ok {
-		r0 = rf(id, album)
+	if rf, ok := ret.Get(0).(func(context.Context, int, data.Todo) *data.Todo); ok {
+		r0 = rf(ctx, id, todo)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*data.Todo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int, data.Todo) error); ok {
-		r1 = rf(id, album)
+	if rf, ok := ret.Get(1).(func(context.Context, int, data.Todo) error); ok {
+		r1 = rf(ctx, id, todo)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -3,7 +3,10 @@
 package mocks
 
 import (
+	pagination "blog-api/app/pagination"
+	resource "blog-api/app/v1/resource/service"
 	data "blog-api/data"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -13,9 +16,9 @@ type IUserService struct {
 	mock.Mock
 }
 
-// CreateUser provides a mock function with given fields: album
-func (_m *IUserService) CreateUser(album data.User) (*data.User, error) {
-	ret := _m.Called(album)
+// CreateUser provides a mock function with given fields: ctx, user
+func (_m *IUserService) CreateUser(ctx context.Context, user data.User) (*data.User, error) {
+	ret := _m.Called(ctx, user)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateUser")
@@ -23,19 +26,19 @@ func (_m *IUserService) CreateUser(album data.User) (*data.User, error) {
 
 	var r0 *data.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(data.User) (*data.User, error)); ok {
-		return rf(album)
+	if rf, ok := ret.Get(0).(func(context.Context, data.User) (*data.User, error)); ok {
+		return rf(ctx, user)
 	}
-	if rf, ok := ret.Get(0).(func(data.User) *data.User); ok {
-		r0 = rf(album)
+	if rf, ok := ret.Get(0).(func(context.Context, data.User) *data.User); ok {
+		r0 = rf(ctx, user)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*data.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(data.User) error); ok {
-		r1 = rf(album)
+	if rf, ok := ret.Get(1).(func(context.Context, data.User) error); ok {
+		r1 = rf(ctx, user)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -43,17 +46,17 @@ func (_m *IUserService) CreateUser(album data.User) (*data.User, error) {
 	return r0, r1
 }
 
-// DeleteUser provides a mock function with given fields: id
-func (_m *IUserService) DeleteUser(id int) error {
-	ret := _m.Called(id)
+// DeleteUser provides a mock function with given fields: ctx, id
+func (_m *IUserService) DeleteUser(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteUser")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -61,9 +64,9 @@ func (_m *IUserService) DeleteUser(id int) error {
 	return r0
 }
 
-// GetUser provides a mock function with given fields: id
-func (_m *IUserService) GetUser(id int) (*data.User, error) {
-	ret := _m.Called(id)
+// GetUser provides a mock function with given fields: ctx, id
+func (_m *IUserService) GetUser(ctx context.Context, id int) (*data.User, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUser")
@@ -71,19 +74,19 @@ func (_m *IUserService) GetUser(id int) (*data.User, error) {
 
 	var r0 *data.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) (*data.User, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*data.User, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(int) *data.User); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) *data.User); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*data.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -91,29 +94,66 @@ func (_m *IUserService) GetUser(id int) (*data.User, error) {
 	return r0, r1
 }
 
-// GetUsers provides a mock function with given fields: title, userID, id
-func (_m *IUserService) GetUsers(title string, userID int, id int) (*[]data.User, error) {
-	ret := _m.Called(title, userID, id)
+// GetUsers provides a mock function with given fields: ctx, filter, page
+func (_m *IUserService) GetUsers(ctx context.Context, filter resource.Filter, page pagination.Pagination) (*[]data.User, int, error) {
+	ret := _m.Called(ctx, filter, page)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUsers")
 	}
 
 	var r0 *[]data.User
-	var r1 error
-	if rf, ok := ret.Get(0).(func(string, int, int) (*[]data.User, error)); ok {
-		return rf(title, userID, id)
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, resource.Filter, pagination.Pagination) (*[]data.User, int, error)); ok {
+		return rf(ctx, filter, page)
 	}
-	if rf, ok := ret.Get(0).(func(string, int, int) *[]data.User); ok {
-		r0 = rf(title, userID, id)
+	if rf, ok := ret.Get(0).(func(context.Context, resource.Filter, pagination.Pagination) *[]data.User); ok {
+		r0 = rf(ctx, filter, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*[]data.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, int, int) error); ok {
-		r1 = rf(title, userID, id)
+	if rf, ok := ret.Get(1).(func(context.Context, resource.Filter, pagination.Pagination) int); ok {
+		r1 = rf(ctx, filter, page)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, resource.Filter, pagination.Pagination) error); ok {
+		r2 = rf(ctx, filter, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// PatchUser provides a mock function with given fields: ctx, id, apply
+func (_m *IUserService) PatchUser(ctx context.Context, id int, apply func(current data.User) (data.User, error)) (*data.User, error) {
+	ret := _m.Called(ctx, id, apply)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchUser")
+	}
+
+	var r0 *data.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, func(current data.User) (data.User, error)) (*data.User, error)); ok {
+		return rf(ctx, id, apply)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, func(current data.User) (data.User, error)) *data.User); ok {
+		r0 = rf(ctx, id, apply)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*data.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, func(current data.User) (data.User, error)) error); ok {
+		r1 = rf(ctx, id, apply)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -121,9 +161,9 @@ func (_m *IUserService) GetUsers(title string, userID int, id int) (*[]data.User
 	return r0, r1
 }
 
-// UpdateUser provides a mock function with given fields: id, album
-func (_m *IUserService) UpdateUser(id int, album data.User) (*data.User, error) {
-	ret := _m.Called(id, album)
+// UpdateUser provides a mock function with given fields: ctx, id, user
+func (_m *IUserService) UpdateUser(ctx context.Context, id int, user data.User) (*data.User, error) {
+	ret := _m.Called(ctx, id, user)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateUser")
@@ -131,19 +171,19 @@ func (_m *IUserService) UpdateUser(id int, album data.User) (*data.User, error)
 
 	var r0 *data.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int, data.User) (*data.User, error)); ok {
-		return rf(id, album)
+	if rf, ok := ret.Get(0).(func(context.Context, int, data.User) (*data.User, error)); ok {
+		return rf(ctx, id, user)
 	}
-	if rf, ok := ret.Get(0).(func(int, data.User) *data.User); ok {
-		r0 = rf(id, album)
+	if rf, ok := ret.Get(0).(func(context.Context, int, data.User) *data.User); ok {
+		r0 = rf(ctx, id, user)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*data.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int, data.User) error); ok {
-		r1 = rf(id, album)
+	if rf, ok := ret.Get(1).(func(context.Context, int, data.User) error); ok {
+		r1 = rf(ctx, id, user)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,219 @@
+package pagination
+
+import (
+	apperrors "blog-api/app/errors"
+	"blog-api/app/i18n"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultLimit es el tamaño de página usado cuando solo se envía page o cursor
+	DefaultLimit = 10
+	// MaxLimit es el tamaño de página máximo aceptado
+	MaxLimit = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type contextKey struct{}
+
+// Pagination describe la porción de una colección pedida por el cliente.
+// El valor cero significa "sin paginar" y devuelve la colección completa.
+type Pagination struct {
+	Page   int
+	Limit  int
+	Cursor bool
+}
+
+type cursor struct {
+	Page  int `json:"p"`
+	Limit int `json:"l"`
+}
+
+// Enabled indica si el cliente pidió paginar
+func (p Pagination) Enabled() bool {
+	return p.Limit > 0
+}
+
+// Offset devuelve el índice del primer elemento de la página
+func (p Pagination) Offset() int {
+	if !p.Enabled() {
+		return 0
+	}
+	return (p.Page - 1) * p.Limit
+}
+
+// LastPage devuelve el número de la última página para el total dado
+func (p Pagination) LastPage(total int) int {
+	if !p.Enabled() || total <= 0 {
+		return 1
+	}
+	return (total + p.Limit - 1) / p.Limit
+}
+
+// HasNext indica si hay elementos después de la página actual
+func (p Pagination) HasNext(total int) bool {
+	return p.Enabled() && p.Page < p.LastPage(total)
+}
+
+// NextCursor devuelve el cursor opaco de la página siguiente, o "" si no hay más
+func (p Pagination) NextCursor(total int) string {
+	if !p.HasNext(total) {
+		return ""
+	}
+	return EncodeCursor(Pagination{Page: p.Page + 1, Limit: p.Limit})
+}
+
+// EncodeCursor serializa una página como un cursor opaco
+func EncodeCursor(p Pagination) string {
+	raw, _ := json.Marshal(cursor{Page: p.Page, Limit: p.Limit})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor interpreta un cursor generado por EncodeCursor
+func DecodeCursor(value string) (Pagination, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return Pagination{}, ErrInvalidCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return Pagination{}, ErrInvalidCursor
+	}
+	if c.Page < 1 || c.Limit < 1 || c.Limit > MaxLimit {
+		return Pagination{}, ErrInvalidCursor
+	}
+	return Pagination{Page: c.Page, Limit: c.Limit, Cursor: true}, nil
+}
+
+// Parse lee page, limit y cursor de los query params
+func Parse(q url.Values) (Pagination, error) {
+	if value := q.Get("cursor"); value != "" {
+		return DecodeCursor(value)
+	}
+	pageValue, limitValue := q.Get("page"), q.Get("limit")
+	if pageValue == "" && limitValue == "" {
+		return Pagination{}, nil
+	}
+	p := Pagination{Page: 1, Limit: DefaultLimit}
+	if pageValue != "" {
+		page, err := strconv.Atoi(pageValue)
+		if err != nil || page < 1 {
+			return Pagination{}, i18n.Errorf("invalid page %q", pageValue)
+		}
+		p.Page = page
+	}
+	if limitValue != "" {
+		limit, err := strconv.Atoi(limitValue)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			return Pagination{}, i18n.Errorf("invalid limit %q", limitValue)
+		}
+		p.Limit = limit
+	}
+	return p, nil
+}
+
+// NewContext guarda la paginación en el contexto
+func NewContext(ctx context.Context, p Pagination) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext obtiene la paginación guardada por Paginate
+func FromContext(ctx context.Context) Pagination {
+	p, _ := ctx.Value(contextKey{}).(Pagination)
+	return p
+}
+
+// Paginate es el middleware que interpreta los parámetros de paginación
+// y los deja en el contexto de la solicitud
+func Paginate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, err := Parse(r.URL.Query())
+		if err != nil {
+			apperrors.Write(w, r, err, apperrors.ErrBadRequest)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), p)))
+	})
+}
+
+// Apply recorta localmente una colección completa a la página pedida
+func Apply[T any](items []T, p Pagination) []T {
+	if !p.Enabled() {
+		return items
+	}
+	start := p.Offset()
+	if start >= len(items) {
+		return []T{}
+	}
+	end := start + p.Limit
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[start:end]
+}
+
+// SetHeaders escribe X-Total-Count, Link y X-Next-Cursor en la respuesta
+func SetHeaders(w http.ResponseWriter, r *http.Request, p Pagination, total int) {
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	if !p.Enabled() {
+		return
+	}
+	links := []string{
+		link(r, p, 1, "first"),
+	}
+	if p.Page > 1 {
+		links = append(links, link(r, p, p.Page-1, "prev"))
+	}
+	if p.HasNext(total) {
+		links = append(links, link(r, p, p.Page+1, "next"))
+		w.Header().Set("X-Next-Cursor", p.NextCursor(total))
+	}
+	links = append(links, link(r, p, p.LastPage(total), "last"))
+	w.Header().Set("Link", strings.Join(links, ", "))
+}
+
+func link(r *http.Request, p Pagination, page int, rel string) string {
+	q := r.URL.Query()
+	q.Del("page")
+	q.Del("limit")
+	q.Del("cursor")
+	if p.Cursor {
+		q.Set("cursor", EncodeCursor(Pagination{Page: page, Limit: p.Limit}))
+	} else {
+		q.Set("page", strconv.Itoa(page))
+		q.Set("limit", strconv.Itoa(p.Limit))
+	}
+	u := url.URL{Path: r.URL.Path, RawQuery: q.Encode()}
+	return fmt.Sprintf("<%s>; rel=%q", u.String(), rel)
+}
+
+// Query agrega _page y _limit a los parámetros que se envían al upstream
+func (p Pagination) Query(q url.Values) {
+	if !p.Enabled() {
+		return
+	}
+	q.Set("_page", strconv.Itoa(p.Page))
+	q.Set("_limit", strconv.Itoa(p.Limit))
+}
+
+// Total lee X-Total-Count de la respuesta del upstream
+func Total(header http.Header) (int, bool) {
+	value := header.Get("X-Total-Count")
+	if value == "" {
+		return 0, false
+	}
+	total, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false
+	}
+	return total, true
+}
@@ -0,0 +1,107 @@
+package pagination
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("no params", func(t *testing.T) {
+		p, err := Parse(url.Values{})
+		require.NoError(t, err)
+		require.False(t, p.Enabled())
+	})
+	t.Run("page and limit", func(t *testing.T) {
+		p, err := Parse(url.Values{"page": {"3"}, "limit": {"5"}})
+		require.NoError(t, err)
+		require.Equal(t, Pagination{Page: 3, Limit: 5}, p)
+		require.Equal(t, 10, p.Offset())
+	})
+	t.Run("page only uses default limit", func(t *testing.T) {
+		p, err := Parse(url.Values{"page": {"2"}})
+		require.NoError(t, err)
+		require.Equal(t, Pagination{Page: 2, Limit: DefaultLimit}, p)
+	})
+	t.Run("cursor", func(t *testing.T) {
+		cursor := EncodeCursor(Pagination{Page: 4, Limit: 20})
+		p, err := Parse(url.Values{"cursor": {cursor}})
+		require.NoError(t, err)
+		require.Equal(t, Pagination{Page: 4, Limit: 20, Cursor: true}, p)
+	})
+	t.Run("invalid values", func(t *testing.T) {
+		for _, q := range []url.Values{
+			{"page": {"0"}},
+			{"limit": {"abc"}},
+			{"limit": {"1000"}},
+			{"cursor": {"not-a-cursor"}},
+		} {
+			_, err := Parse(q)
+			require.Error(t, err, q.Encode())
+		}
+	})
+}
+
+func TestPaginate_Middleware(t *testing.T) {
+	var got Pagination
+	handler := Paginate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = FromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/posts?page=2&limit=10", nil)
+	mockRecorder := httptest.NewRecorder()
+	handler.ServeHTTP(mockRecorder, req)
+	require.Equal(t, http.StatusOK, mockRecorder.Code)
+	require.Equal(t, Pagination{Page: 2, Limit: 10}, got)
+
+	req = httptest.NewRequest(http.MethodGet, "/posts?page=-1", nil)
+	mockRecorder = httptest.NewRecorder()
+	handler.ServeHTTP(mockRecorder, req)
+	require.Equal(t, http.StatusBadRequest, mockRecorder.Code)
+}
+
+func TestApply(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	require.Equal(t, items, Apply(items, Pagination{}))
+	require.Equal(t, []int{3, 4}, Apply(items, Pagination{Page: 2, Limit: 2}))
+	require.Equal(t, []int{5}, Apply(items, Pagination{Page: 3, Limit: 2}))
+	require.Equal(t, []int{}, Apply(items, Pagination{Page: 4, Limit: 2}))
+}
+
+func TestSetHeaders(t *testing.T) {
+	t.Run("page mode", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/posts?page=2&limit=10&userId=1", nil)
+		mockRecorder := httptest.NewRecorder()
+		SetHeaders(mockRecorder, req, Pagination{Page: 2, Limit: 10}, 35)
+		require.Equal(t, "35", mockRecorder.Header().Get("X-Total-Count"))
+		require.Equal(t, `</v1/posts?limit=10&page=1&userId=1>; rel="first", `+
+			`</v1/posts?limit=10&page=1&userId=1>; rel="prev", `+
+			`</v1/posts?limit=10&page=3&userId=1>; rel="next", `+
+			`</v1/posts?limit=10&page=4&userId=1>; rel="last"`, mockRecorder.Header().Get("Link"))
+		require.Equal(t, EncodeCursor(Pagination{Page: 3, Limit: 10}), mockRecorder.Header().Get("X-Next-Cursor"))
+	})
+	t.Run("last page has no next cursor", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/posts?page=4&limit=10", nil)
+		mockRecorder := httptest.NewRecorder()
+		SetHeaders(mockRecorder, req, Pagination{Page: 4, Limit: 10}, 35)
+		require.Empty(t, mockRecorder.Header().Get("X-Next-Cursor"))
+		require.NotContains(t, mockRecorder.Header().Get("Link"), `rel="next"`)
+	})
+	t.Run("cursor mode", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/posts", nil)
+		mockRecorder := httptest.NewRecorder()
+		SetHeaders(mockRecorder, req, Pagination{Page: 1, Limit: 10, Cursor: true}, 20)
+		next := EncodeCursor(Pagination{Page: 2, Limit: 10})
+		require.Contains(t, mockRecorder.Header().Get("Link"), "</v1/posts?cursor="+next+`>; rel="next"`)
+	})
+	t.Run("unpaginated", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/posts", nil)
+		mockRecorder := httptest.NewRecorder()
+		SetHeaders(mockRecorder, req, Pagination{}, 100)
+		require.Equal(t, "100", mockRecorder.Header().Get("X-Total-Count"))
+		require.Empty(t, mockRecorder.Header().Get("Link"))
+	})
+}
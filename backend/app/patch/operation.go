@@ -0,0 +1,329 @@
+package patch
+
+import (
+	"blog-api/app/i18n"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// operation es una operación de un JSON patch
+type operation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+	path  pointer
+	from  pointer
+	value any
+}
+
+// parse lee las operaciones y comprueba que cada una tenga los miembros que necesita
+func parse(document []byte) ([]operation, error) {
+	var operations []operation
+	if err := decode(document, &operations); err != nil {
+		return nil, err
+	}
+	for i := range operations {
+		if err := operations[i].prepare(); err != nil {
+			return nil, i18n.Errorf("%w: operation %d: %w", ErrInvalid, i, err)
+		}
+	}
+	return operations, nil
+}
+
+func (op *operation) prepare() error {
+	if op.Path == nil {
+		return i18n.Errorf("%s needs a path", op.Op)
+	}
+	var err error
+	if op.path, err = parsePointer(*op.Path); err != nil {
+		return err
+	}
+	switch op.Op {
+	case "add", "replace", "test":
+		if len(op.Value) == 0 {
+			return i18n.Errorf("%s needs a value", op.Op)
+		}
+		return decode(op.Value, &op.value)
+	case "move", "copy":
+		if op.From == nil {
+			return i18n.Errorf("%s needs a from", op.Op)
+		}
+		if op.from, err = parsePointer(*op.From); err != nil {
+			return err
+		}
+		if op.Op == "move" && op.path.within(op.from) && len(op.path) > len(op.from) {
+			return i18n.Errorf("can´t move %s into itself", *op.From)
+		}
+		return nil
+	case "remove":
+		return nil
+	default:
+		return i18n.Errorf("unknown op %q", op.Op)
+	}
+}
+
+// apply aplica la operación a doc y devuelve el documento resultante
+func (op *operation) apply(doc any) (any, error) {
+	switch op.Op {
+	case "add":
+		return op.path.set(doc, op.value, true)
+	case "remove":
+		doc, _, err := op.path.remove(doc)
+		return doc, err
+	case "replace":
+		if _, err := op.path.get(doc); err != nil {
+			return nil, err
+		}
+		return op.path.set(doc, op.value, false)
+	case "move":
+		doc, value, err := op.from.remove(doc)
+		if err != nil {
+			return nil, err
+		}
+		return op.path.set(doc, value, true)
+	case "copy":
+		value, err := op.from.get(doc)
+		if err != nil {
+			return nil, err
+		}
+		return op.path.set(doc, clone(value), true)
+	default: // test
+		value, err := op.path.get(doc)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(value, op.value) {
+			return nil, i18n.Errorf("test failed, the value is %s", mustMarshal(value))
+		}
+		return doc, nil
+	}
+}
+
+// pointer es un JSON pointer (RFC 6901) separado en sus referencias
+type pointer []string
+
+func parsePointer(value string) (pointer, error) {
+	if value == "" {
+		return pointer{}, nil
+	}
+	if !strings.HasPrefix(value, "/") {
+		return nil, i18n.Errorf("%q is not a JSON pointer", value)
+	}
+	tokens := strings.Split(value[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+// within informa si p está dentro de parent o es el mismo
+func (p pointer) within(parent pointer) bool {
+	if len(p) < len(parent) {
+		return false
+	}
+	for i := range parent {
+		if p[i] != parent[i] {
+			return false
+		}
+	}
+	return true
+}
+
+var errNoPath = errors.New("the path does not exist")
+
+func (p pointer) get(doc any) (any, error) {
+	for _, token := range p {
+		switch container := doc.(type) {
+		case map[string]any:
+			value, ok := container[token]
+			if !ok {
+				return nil, errNoPath
+			}
+			doc = value
+		case []any:
+			i, err := index(token, len(container)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = container[i]
+		default:
+			return nil, errNoPath
+		}
+	}
+	return doc, nil
+}
+
+// set agrega o reemplaza el valor en p. En un array insert agrega en la posición,
+// corriendo los siguientes, y - agrega al final.
+func (p pointer) set(doc any, value any, insert bool) (any, error) {
+	if len(p) == 0 {
+		return value, nil
+	}
+	token, rest := p[0], p[1:]
+	switch container := doc.(type) {
+	case map[string]any:
+		if len(rest) == 0 {
+			container[token] = value
+			return container, nil
+		}
+		child, ok := container[token]
+		if !ok {
+			return nil, errNoPath
+		}
+		child, err := rest.set(child, value, insert)
+		if err != nil {
+			return nil, err
+		}
+		container[token] = child
+		return container, nil
+	case []any:
+		if len(rest) == 0 && insert {
+			if token == "-" {
+				return append(container, value), nil
+			}
+			i, err := index(token, len(container))
+			if err != nil {
+				return nil, err
+			}
+			container = append(container[:i], append([]any{value}, container[i:]...)...)
+			return container, nil
+		}
+		i, err := index(token, len(container)-1)
+		if err != nil {
+			return nil, err
+		}
+		if len(rest) == 0 {
+			container[i] = value
+			return container, nil
+		}
+		child, err := rest.set(container[i], value, insert)
+		if err != nil {
+			return nil, err
+		}
+		container[i] = child
+		return container, nil
+	default:
+		return nil, errNoPath
+	}
+}
+
+// remove quita el valor en p y lo devuelve junto con el documento resultante
+func (p pointer) remove(doc any) (any, any, error) {
+	if len(p) == 0 {
+		return nil, nil, i18n.Errorf("the whole document can´t be removed")
+	}
+	token, rest := p[0], p[1:]
+	switch container := doc.(type) {
+	case map[string]any:
+		child, ok := container[token]
+		if !ok {
+			return nil, nil, errNoPath
+		}
+		if len(rest) == 0 {
+			delete(container, token)
+			return container, child, nil
+		}
+		child, removed, err := rest.remove(child)
+		if err != nil {
+			return nil, nil, err
+		}
+		container[token] = child
+		return container, removed, nil
+	case []any:
+		i, err := index(token, len(container)-1)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(rest) == 0 {
+			removed := container[i]
+			return append(container[:i], container[i+1:]...), removed, nil
+		}
+		child, removed, err := rest.remove(container[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		container[i] = child
+		return container, removed, nil
+	default:
+		return nil, nil, errNoPath
+	}
+}
+
+// index lee la posición de un array, entre 0 y max y sin ceros a la izquierda
+func index(token string, max int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (len(token) > 1 && token[0] == '0') || strings.HasPrefix(token, "+") {
+		return 0, i18n.Errorf("%q is not an array index", token)
+	}
+	if i > max {
+		return 0, i18n.Errorf("index %d is out of range", i)
+	}
+	return i, nil
+}
+
+// equal compara dos valores JSON; los números se comparan por su valor, 1 es igual a 1.0
+func equal(a any, b any) bool {
+	switch x := a.(type) {
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		fx, errX := x.Float64()
+		fy, errY := y.Float64()
+		return errX == nil && errY == nil && fx == fy
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for name, value := range x {
+			other, ok := y[name]
+			if !ok || !equal(value, other) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equal(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+// clone copia un valor JSON para que copy no comparta objetos ni arrays con el origen
+func clone(value any) any {
+	switch x := value.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(x))
+		for name, item := range x {
+			copied[name] = clone(item)
+		}
+		return copied
+	case []any:
+		copied := make([]any, len(x))
+		for i, item := range x {
+			copied[i] = clone(item)
+		}
+		return copied
+	default:
+		return value
+	}
+}
+
+func mustMarshal(value any) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
@@ -0,0 +1,147 @@
+package patch
+
+import (
+	apperrors "blog-api/app/errors"
+	"blog-api/app/i18n"
+	"blog-api/app/validation"
+	"bytes"
+	"encoding/json"
+)
+
+// Tipos de documento que acepta PATCH
+const (
+	// MergePatch es un documento con los campos a cambiar; null elimina un campo (RFC 7396)
+	MergePatch = "application/merge-patch+json"
+	// JSONPatch es una lista de operaciones add, remove, replace, move, copy y test (RFC 6902)
+	JSONPatch = "application/json-patch+json"
+)
+
+// Accept es el valor del header Accept-Patch
+const Accept = MergePatch + ", " + JSONPatch
+
+var (
+	// ErrInvalid indica que el documento de patch está mal formado
+	ErrInvalid = apperrors.New(apperrors.ErrBadRequest, "invalid patch document")
+	// ErrFailed indica que el patch no se puede aplicar al documento actual
+	ErrFailed = apperrors.New(apperrors.ErrPatchFailed, "patch can´t be applied")
+)
+
+// Func devuelve la función que aplica document a un elemento según contentType. Un
+// documento application/json se aplica como MergePatch. Devuelve ErrInvalid si el
+// documento está mal formado, antes de leer el elemento.
+func Func[T any](contentType string, document []byte) (func(current T) (T, error), error) {
+	var patch func(doc []byte) ([]byte, error)
+	switch contentType {
+	case MergePatch, "application/json":
+		var value any
+		if err := decode(document, &value); err != nil {
+			return nil, err
+		}
+		patch = func(doc []byte) ([]byte, error) {
+			return mergeDocument(doc, value)
+		}
+	case JSONPatch:
+		operations, err := parse(document)
+		if err != nil {
+			return nil, err
+		}
+		patch = func(doc []byte) ([]byte, error) {
+			return applyDocument(doc, operations)
+		}
+	default:
+		return nil, apperrors.Newf(apperrors.ErrUnsupportedMediaType, "%s is not a patch type, use %s", contentType, Accept)
+	}
+	return func(current T) (T, error) {
+		var result T
+		doc, err := json.Marshal(current)
+		if err != nil {
+			return result, err
+		}
+		patched, err := patch(doc)
+		if err != nil {
+			return result, err
+		}
+		if err := json.Unmarshal(patched, &result); err != nil {
+			if typeErr := validation.TypeError(apperrors.ErrValidation, err); typeErr != nil {
+				return result, typeErr
+			}
+			return result, i18n.Errorf("%w: the result is not a valid %T", ErrFailed, result)
+		}
+		return result, nil
+	}, nil
+}
+
+// Merge aplica el merge patch a doc (RFC 7396)
+func Merge(doc []byte, patch []byte) ([]byte, error) {
+	var value any
+	if err := decode(patch, &value); err != nil {
+		return nil, err
+	}
+	return mergeDocument(doc, value)
+}
+
+func mergeDocument(doc []byte, patch any) ([]byte, error) {
+	var target any
+	if err := decode(doc, &target); err != nil {
+		return nil, err
+	}
+	return json.Marshal(merge(target, patch))
+}
+
+// merge aplica patch a target: los objetos se combinan campo por campo, null elimina
+// el campo y cualquier otro valor reemplaza al actual
+func merge(target any, patch any) any {
+	fields, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	object, ok := target.(map[string]any)
+	if !ok {
+		object = map[string]any{}
+	}
+	for name, value := range fields {
+		if value == nil {
+			delete(object, name)
+			continue
+		}
+		object[name] = merge(object[name], value)
+	}
+	return object
+}
+
+// Apply aplica las operaciones del JSON patch a doc (RFC 6902). Si una falla no se
+// aplica ninguna.
+func Apply(doc []byte, patch []byte) ([]byte, error) {
+	operations, err := parse(patch)
+	if err != nil {
+		return nil, err
+	}
+	return applyDocument(doc, operations)
+}
+
+func applyDocument(doc []byte, operations []operation) ([]byte, error) {
+	var target any
+	if err := decode(doc, &target); err != nil {
+		return nil, err
+	}
+	for i, op := range operations {
+		var err error
+		if target, err = op.apply(target); err != nil {
+			return nil, i18n.Errorf("%w: operation %d (%s %s): %w", ErrFailed, i, op.Op, *op.Path, err)
+		}
+	}
+	return json.Marshal(target)
+}
+
+// decode lee un único valor JSON conservando los números como json.Number
+func decode(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return i18n.Errorf("%w: %w", ErrInvalid, err)
+	}
+	if decoder.More() {
+		return i18n.Errorf("%w: unexpected data after the document", ErrInvalid)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package patch
+
+import (
+	apperrors "blog-api/app/errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	// Ejemplos del apéndice A de RFC 7396
+	tests := []struct {
+		doc   string
+		patch string
+		want  string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.patch, func(t *testing.T) {
+			result, err := Merge([]byte(tc.doc), []byte(tc.patch))
+			require.NoError(t, err)
+			require.JSONEq(t, tc.want, string(result))
+		})
+	}
+
+	_, err := Merge([]byte(`{}`), []byte(`{"a":`))
+	require.ErrorIs(t, err, ErrInvalid)
+	_, err = Merge([]byte(`{}`), []byte(`{} {}`))
+	require.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestApply(t *testing.T) {
+	// Ejemplos del apéndice A de RFC 6902
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{"add a member", `{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{"add an array element", `{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{"add to the end", `{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`},
+		{"remove a member", `{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{"remove an array element", `{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{"replace", `{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{"move a member", `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{"move an array element", `{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{"copy", `{"foo":{"a":1}}`, `[{"op":"copy","from":"/foo","path":"/bar"},{"op":"replace","path":"/bar/a","value":2}]`, `{"foo":{"a":1},"bar":{"a":2}}`},
+		{"test", `{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`, `{"baz":"qux","foo":["a",2,"c"]}`},
+		{"test numbers by value", `{"n":1}`, `[{"op":"test","path":"/n","value":1.0}]`, `{"n":1}`},
+		{"escaped pointer", `{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10},{"op":"remove","path":"/~1"}]`, `{"~1":10}`},
+		{"replace the document", `{"foo":"bar"}`, `[{"op":"replace","path":"","value":{"baz":"qux"}}]`, `{"baz":"qux"}`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := Apply([]byte(tc.doc), []byte(tc.patch))
+			require.NoError(t, err)
+			require.JSONEq(t, tc.want, string(result))
+		})
+	}
+}
+
+func TestApply_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  error
+		msg   string
+	}{
+		{"failed test", `{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"bar"}]`, ErrFailed, `patch can´t be applied: operation 0 (test /baz): test failed, the value is "qux"`},
+		{"missing member", `{"foo":"bar"}`, `[{"op":"replace","path":"/baz","value":1}]`, ErrFailed, "patch can´t be applied: operation 0 (replace /baz): the path does not exist"},
+		{"missing parent", `{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`, ErrFailed, "patch can´t be applied: operation 0 (add /baz/bat): the path does not exist"},
+		{"index out of range", `{"foo":["bar"]}`, `[{"op":"add","path":"/foo/2","value":"qux"}]`, ErrFailed, "patch can´t be applied: operation 0 (add /foo/2): index 2 is out of range"},
+		{"leading zero", `{"foo":["bar","baz"]}`, `[{"op":"remove","path":"/foo/01"}]`, ErrFailed, `patch can´t be applied: operation 0 (remove /foo/01): "01" is not an array index`},
+		{"remove the document", `{}`, `[{"op":"remove","path":""}]`, ErrFailed, "patch can´t be applied: operation 0 (remove ): the whole document can´t be removed"},
+		{"not a list", `{}`, `{"op":"add","path":"/a","value":1}`, ErrInvalid, ""},
+		{"missing value", `{}`, `[{"op":"add","path":"/a"}]`, ErrInvalid, "invalid patch document: operation 0: add needs a value"},
+		{"null value", `{}`, `[{"op":"add","path":"/a","value":null}]`, nil, ""},
+		{"missing path", `{}`, `[{"op":"remove"}]`, ErrInvalid, "invalid patch document: operation 0: remove needs a path"},
+		{"missing from", `{}`, `[{"op":"copy","path":"/a"}]`, ErrInvalid, "invalid patch document: operation 0: copy needs a from"},
+		{"bad pointer", `{}`, `[{"op":"remove","path":"a"}]`, ErrInvalid, `invalid patch document: operation 0: "a" is not a JSON pointer`},
+		{"move into itself", `{"a":{}}`, `[{"op":"move","from":"/a","path":"/a/b"}]`, ErrInvalid, "invalid patch document: operation 0: can´t move /a into itself"},
+		{"unknown op", `{}`, `[{"op":"rename","path":"/a"}]`, ErrInvalid, `invalid patch document: operation 0: unknown op "rename"`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Apply([]byte(tc.doc), []byte(tc.patch))
+			if tc.want == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tc.want)
+			if tc.msg != "" {
+				require.EqualError(t, err, tc.msg)
+			}
+		})
+	}
+}
+
+type item struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	Done  bool   `json:"done"`
+}
+
+func TestFunc(t *testing.T) {
+	current := item{ID: 1, Title: "a", Done: true}
+
+	apply, err := Func[item](MergePatch, []byte(`{"done": false}`))
+	require.NoError(t, err)
+	result, err := apply(current)
+	require.NoError(t, err)
+	require.Equal(t, item{ID: 1, Title: "a"}, result)
+
+	apply, err = Func[item](JSONPatch, []byte(`[{"op":"replace","path":"/title","value":"b"},{"op":"test","path":"/done","value":false}]`))
+	require.NoError(t, err)
+	_, err = apply(current)
+	require.ErrorIs(t, err, ErrFailed)
+	require.ErrorIs(t, err, apperrors.ErrPatchFailed)
+
+	// Un resultado con un campo de otro tipo es un error de validación del campo
+	apply, err = Func[item](MergePatch, []byte(`{"title": 5}`))
+	require.NoError(t, err)
+	_, err = apply(current)
+	require.ErrorIs(t, err, apperrors.ErrValidation)
+	var fielder apperrors.Fielder
+	require.ErrorAs(t, err, &fielder)
+	require.Equal(t, "title", fielder.FieldErrors("en")[0].Field)
+
+	apply, err = Func[item](MergePatch, []byte(`[1]`))
+	require.NoError(t, err)
+	_, err = apply(current)
+	require.ErrorIs(t, err, ErrFailed)
+
+	// El documento se valida antes de leer el elemento
+	_, err = Func[item](JSONPatch, []byte(`[{"op":"add"}]`))
+	require.ErrorIs(t, err, ErrInvalid)
+	require.ErrorIs(t, err, apperrors.ErrBadRequest)
+	_, err = Func[item]("text/plain", []byte(`title=b`))
+	require.ErrorIs(t, err, apperrors.ErrUnsupportedMediaType)
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Dialect reúne las diferencias de SQL entre los motores soportados
+type Dialect struct {
+	// Name es el nombre usado en la configuración, p. ej. DB_DRIVER=postgres
+	Name string
+	// Driver es el nombre del driver de database/sql
+	Driver string
+	// PrimaryKey es el tipo de la columna id autoincremental
+	PrimaryKey string
+	// ResetSequence ajusta el próximo id de la tabla %s después de insertar ids explícitos.
+	// SQLite no lo necesita.
+	ResetSequence string
+	// numbered indica si los parámetros son $1, $2… en lugar de ?
+	numbered bool
+}
+
+var (
+	// SQLite guarda los datos en un archivo local, o en memoria con ":memory:"
+	SQLite = Dialect{Name: "sqlite", Driver: "sqlite3", PrimaryKey: "INTEGER PRIMARY KEY AUTOINCREMENT"}
+	// Postgres es compatible con PostgreSQL 10 o superior
+	Postgres = Dialect{
+		Name:          "postgres",
+		Driver:        "postgres",
+		PrimaryKey:    "SERIAL PRIMARY KEY",
+		ResetSequence: "SELECT setval(pg_get_serial_sequence('%[1]s', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM %[1]s",
+		numbered:      true,
+	}
+)
+
+// DialectFor devuelve el dialecto con el nombre dado
+func DialectFor(name string) (Dialect, error) {
+	switch name {
+	case SQLite.Name:
+		return SQLite, nil
+	case Postgres.Name:
+		return Postgres, nil
+	}
+	return Dialect{}, fmt.Errorf("unknown database driver %q", name)
+}
+
+// Placeholder devuelve el parámetro número n (desde 1) de una consulta
+func (d Dialect) Placeholder(n int) string {
+	if d.numbered {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+// Open abre la base de datos y verifica la conexión
+func Open(ctx context.Context, dialect Dialect, dsn string) (*sql.DB, error) {
+	db, err := sql.Open(dialect.Driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if dialect == SQLite {
+		// SQLite admite un solo escritor; además cada conexión a ":memory:" es una base distinta
+		db.SetMaxOpenConns(1)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
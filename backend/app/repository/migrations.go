@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// migrationFiles son los archivos NNNN_nombre.up.sql y NNNN_nombre.down.sql.
+// {{.PrimaryKey}} se reemplaza por el tipo de id del dialecto.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationsTable guarda las versiones aplicadas
+const migrationsTable = "schema_migrations"
+
+// ErrSchemaBehind se devuelve cuando hay migraciones sin aplicar
+var ErrSchemaBehind = errors.New("database schema is behind, run `backend migrate up`")
+
+// Migration es un cambio de esquema versionado
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus es una migración y el momento en que se aplicó, si se aplicó
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Migrator aplica y revierte las migraciones embebidas
+type Migrator struct {
+	db         *sql.DB
+	dialect    Dialect
+	migrations []Migration
+}
+
+// NewMigrator lee las migraciones embebidas para el dialecto dado
+func NewMigrator(db *sql.DB, dialect Dialect) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationFiles, "migrations", dialect)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, dialect: dialect, migrations: migrations}, nil
+}
+
+// Status devuelve todas las migraciones en orden, con su fecha de aplicación
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]MigrationStatus, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = MigrationStatus{Migration: migration}
+		if at, ok := applied[migration.Version]; ok {
+			statuses[i].AppliedAt = &at
+		}
+	}
+	return statuses, nil
+}
+
+// Pending devuelve las migraciones que faltan aplicar
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			pending = append(pending, status.Migration)
+		}
+	}
+	return pending, nil
+}
+
+// Check devuelve ErrSchemaBehind si quedan migraciones sin aplicar
+func (m *Migrator) Check(ctx context.Context) error {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: %d pending migrations", ErrSchemaBehind, len(pending))
+	}
+	return nil
+}
+
+// Up aplica en orden todas las migraciones pendientes
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i, migration := range pending {
+		err := m.exec(ctx, migration.Up, "INSERT INTO "+migrationsTable+" (version, name, applied_at) VALUES ("+
+			m.dialect.Placeholder(1)+", "+m.dialect.Placeholder(2)+", "+m.dialect.Placeholder(3)+")",
+			migration.Version, migration.Name, time.Now().UTC())
+		if err != nil {
+			return pending[:i], fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+	return pending, nil
+}
+
+// Down revierte la última migración aplicada; devuelve nil si no hay ninguna
+func (m *Migrator) Down(ctx context.Context) (*Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(statuses) - 1; i >= 0; i-- {
+		if statuses[i].AppliedAt == nil {
+			continue
+		}
+		migration := statuses[i].Migration
+		err := m.exec(ctx, migration.Down, "DELETE FROM "+migrationsTable+" WHERE version = "+m.dialect.Placeholder(1), migration.Version)
+		if err != nil {
+			return nil, fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		return &migration, nil
+	}
+	return nil, nil
+}
+
+// Redo revierte y vuelve a aplicar la última migración aplicada
+func (m *Migrator) Redo(ctx context.Context) (*Migration, error) {
+	migration, err := m.Down(ctx)
+	if err != nil || migration == nil {
+		return migration, err
+	}
+	if _, err := m.Up(ctx); err != nil {
+		return nil, err
+	}
+	return migration, nil
+}
+
+// exec ejecuta el script de la migración y registra el cambio en la misma transacción
+func (m *Migrator) exec(ctx context.Context, script string, record string, args ...any) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int]time.Time, error) {
+	_, err := m.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+migrationsTable+
+		" (version INTEGER PRIMARY KEY, name TEXT NOT NULL, applied_at TIMESTAMP NOT NULL)")
+	if err != nil {
+		return nil, err
+	}
+	rows, err := m.db.QueryContext(ctx, "SELECT version, applied_at FROM "+migrationsTable)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version] = at
+	}
+	return applied, rows.Err()
+}
+
+// loadMigrations lee las migraciones de dir ordenadas por versión
+func loadMigrations(fsys fs.FS, dir string, dialect Dialect) ([]Migration, error) {
+	files, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int]*Migration{}
+	for _, file := range files {
+		base := strings.TrimSuffix(file.Name(), ".sql")
+		base, direction := strings.TrimSuffix(base, path.Ext(base)), strings.TrimPrefix(path.Ext(base), ".")
+		prefix, name, ok := strings.Cut(base, "_")
+		version, err := strconv.Atoi(prefix)
+		if !ok || err != nil || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("invalid migration file name %q", file.Name())
+		}
+		script, err := render(fsys, path.Join(dir, file.Name()), dialect)
+		if err != nil {
+			return nil, err
+		}
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		}
+		if direction == "up" {
+			migration.Up = script
+		} else {
+			migration.Down = script
+		}
+	}
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both up and down files", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+func render(fsys fs.FS, name string, dialect Dialect) (string, error) {
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := template.New(name).Parse(string(content))
+	if err != nil {
+		return "", err
+	}
+	var script bytes.Buffer
+	if err := tmpl.Execute(&script, dialect); err != nil {
+		return "", err
+	}
+	return script.String(), nil
+}
@@ -0,0 +1,5 @@
+DROP TABLE todos;
+DROP TABLE comments;
+DROP TABLE albums;
+DROP TABLE posts;
+DROP TABLE users;
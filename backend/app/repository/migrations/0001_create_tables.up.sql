@@ -0,0 +1,44 @@
+CREATE TABLE users (
+    id {{.PrimaryKey}},
+    name TEXT NOT NULL,
+    username TEXT NOT NULL,
+    email TEXT NOT NULL DEFAULT '',
+    phone TEXT NOT NULL DEFAULT '',
+    website TEXT NOT NULL DEFAULT '',
+    address_street TEXT NOT NULL DEFAULT '',
+    address_suite TEXT NOT NULL DEFAULT '',
+    address_city TEXT NOT NULL DEFAULT '',
+    address_zipcode TEXT NOT NULL DEFAULT '',
+    address_geo_lat TEXT NOT NULL DEFAULT '',
+    address_geo_lng TEXT NOT NULL DEFAULT '',
+    company_name TEXT NOT NULL DEFAULT '',
+    company_catch_phrase TEXT NOT NULL DEFAULT '',
+    company_bs TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE posts (
+    id {{.PrimaryKey}},
+    user_id INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    body TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE albums (
+    id {{.PrimaryKey}},
+    user_id INTEGER NOT NULL,
+    title TEXT NOT NULL
+);
+
+CREATE TABLE comments (
+    id {{.PrimaryKey}},
+    user_id INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    body TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE todos (
+    id {{.PrimaryKey}},
+    user_id INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    completed BOOLEAN NOT NULL DEFAULT FALSE
+);
@@ -0,0 +1,3 @@
+DROP INDEX todos_user_id;
+DROP INDEX albums_user_id;
+DROP INDEX posts_user_id;
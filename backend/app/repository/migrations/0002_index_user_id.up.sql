@@ -0,0 +1,3 @@
+CREATE INDEX posts_user_id ON posts (user_id);
+CREATE INDEX albums_user_id ON albums (user_id);
+CREATE INDEX todos_user_id ON todos (user_id);
@@ -0,0 +1,8 @@
+CREATE TABLE overlay (
+    resource TEXT NOT NULL,
+    id INTEGER NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (resource, id)
+);
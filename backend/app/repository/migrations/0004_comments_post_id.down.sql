@@ -0,0 +1,2 @@
+DROP INDEX comments_post_id;
+ALTER TABLE comments DROP COLUMN post_id;
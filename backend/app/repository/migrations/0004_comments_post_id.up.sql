@@ -0,0 +1,2 @@
+ALTER TABLE comments ADD COLUMN post_id INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX comments_post_id ON comments (post_id);
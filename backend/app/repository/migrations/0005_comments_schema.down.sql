@@ -0,0 +1,7 @@
+DROP INDEX comments_parent_id;
+ALTER TABLE comments ADD COLUMN user_id INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE comments ADD COLUMN title TEXT NOT NULL DEFAULT '';
+UPDATE comments SET title = name;
+ALTER TABLE comments DROP COLUMN parent_id;
+ALTER TABLE comments DROP COLUMN email;
+ALTER TABLE comments DROP COLUMN name;
@@ -0,0 +1,7 @@
+ALTER TABLE comments ADD COLUMN name TEXT NOT NULL DEFAULT '';
+ALTER TABLE comments ADD COLUMN email TEXT NOT NULL DEFAULT '';
+ALTER TABLE comments ADD COLUMN parent_id INTEGER NOT NULL DEFAULT 0;
+UPDATE comments SET name = title;
+ALTER TABLE comments DROP COLUMN title;
+ALTER TABLE comments DROP COLUMN user_id;
+CREATE INDEX comments_parent_id ON comments (parent_id);
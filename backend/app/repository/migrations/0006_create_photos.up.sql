@@ -0,0 +1,8 @@
+CREATE TABLE photos (
+    id {{.PrimaryKey}},
+    album_id INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    url TEXT NOT NULL DEFAULT '',
+    thumbnail_url TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX photos_album_id ON photos (album_id);
@@ -0,0 +1,6 @@
+ALTER TABLE users DROP COLUMN version;
+ALTER TABLE photos DROP COLUMN version;
+ALTER TABLE todos DROP COLUMN version;
+ALTER TABLE comments DROP COLUMN version;
+ALTER TABLE albums DROP COLUMN version;
+ALTER TABLE posts DROP COLUMN version;
@@ -0,0 +1 @@
+ALTER TABLE overlay DROP COLUMN version;
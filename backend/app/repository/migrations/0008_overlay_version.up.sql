@@ -0,0 +1 @@
+ALTER TABLE overlay ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	db, err := Open(ctx, SQLite, ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	migrator, err := NewMigrator(db, SQLite)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, migrator.Check(ctx), ErrSchemaBehind)
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, applied[0].Version)
+	require.NoError(t, migrator.Check(ctx))
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	require.Empty(t, applied)
+
+	last := migrator.migrations[len(migrator.migrations)-1]
+	reverted, err := migrator.Down(ctx)
+	require.NoError(t, err)
+	require.Equal(t, last.Version, reverted.Version)
+	pending, err := migrator.Pending(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []Migration{last}, pending)
+
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []Migration{last}, applied)
+}
+
+func TestMigrator_DownAll(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	migrator, err := NewMigrator(db, SQLite)
+	require.NoError(t, err)
+
+	redone, err := migrator.Redo(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, redone)
+	require.NoError(t, migrator.Check(ctx))
+
+	for range migrator.migrations {
+		_, err := migrator.Down(ctx)
+		require.NoError(t, err)
+	}
+	reverted, err := migrator.Down(ctx)
+	require.NoError(t, err)
+	require.Nil(t, reverted)
+	_, err = db.ExecContext(ctx, "SELECT * FROM posts")
+	require.Error(t, err, "the tables were dropped")
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	for _, status := range statuses {
+		require.Nil(t, status.AppliedAt)
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"m/0002_b.up.sql":   {Data: []byte("CREATE TABLE b (id {{.PrimaryKey}});")},
+		"m/0002_b.down.sql": {Data: []byte("DROP TABLE b;")},
+		"m/0001_a.up.sql":   {Data: []byte("SELECT 1;")},
+		"m/0001_a.down.sql": {Data: []byte("SELECT 1;")},
+	}
+	migrations, err := loadMigrations(fsys, "m", Postgres)
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	require.Equal(t, "a", migrations[0].Name)
+	require.Equal(t, "CREATE TABLE b (id SERIAL PRIMARY KEY);", migrations[1].Up)
+
+	delete(fsys, "m/0001_a.down.sql")
+	_, err = loadMigrations(fsys, "m", Postgres)
+	require.Error(t, err)
+
+	fsys["m/first.up.sql"] = &fstest.MapFile{}
+	_, err = loadMigrations(fsys, "m", Postgres)
+	require.Error(t, err)
+}
@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"time"
+)
+
+// OverlayIDBase es el id a partir del cual se numeran los elementos creados en
+// el modo overlay, lejos de los ids del upstream
+const OverlayIDBase = 1_000_000
+
+// OverlayStore guarda los cambios locales del modo overlay de todos los recursos:
+// el elemento creado o modificado, o una marca de borrado
+type OverlayStore struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewOverlayStore crea el almacenamiento del overlay
+func NewOverlayStore(db *sql.DB, dialect Dialect) *OverlayStore {
+	return &OverlayStore{db: db, dialect: dialect}
+}
+
+// Reset descarta los cambios locales de resource, o de todos los recursos si está vacío
+func (s *OverlayStore) Reset(ctx context.Context, resource string) error {
+	if resource == "" {
+		_, err := conn(ctx, s.db).ExecContext(ctx, "DELETE FROM overlay")
+		return err
+	}
+	_, err := conn(ctx, s.db).ExecContext(ctx, "DELETE FROM overlay WHERE resource = "+s.dialect.Placeholder(1), resource)
+	return err
+}
+
+// OverlayRecord es el cambio local de un elemento. Version aumenta con cada escritura
+// del elemento; un elemento sin cambio local tiene la versión 0.
+type OverlayRecord[T any] struct {
+	ID      int
+	Item    T
+	Deleted bool
+	Version int
+}
+
+// Overlay es la vista de un recurso dentro del OverlayStore
+type Overlay[T any] struct {
+	store    *OverlayStore
+	resource string
+}
+
+// NewOverlay crea la vista del recurso resource, p. ej. "posts"
+func NewOverlay[T any](store *OverlayStore, resource string) *Overlay[T] {
+	return &Overlay[T]{store: store, resource: resource}
+}
+
+// All devuelve los cambios locales del recurso ordenados por id
+func (o *Overlay[T]) All(ctx context.Context) ([]OverlayRecord[T], error) {
+	rows, err := conn(ctx, o.store.db).QueryContext(ctx, "SELECT id, body, deleted, version FROM overlay WHERE resource = "+
+		o.store.dialect.Placeholder(1)+" ORDER BY id", o.resource)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var records []OverlayRecord[T]
+	for rows.Next() {
+		record, err := o.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, *record)
+	}
+	return records, rows.Err()
+}
+
+// Get devuelve el cambio local de id, o nil si el elemento no fue tocado
+func (o *Overlay[T]) Get(ctx context.Context, id int) (*OverlayRecord[T], error) {
+	row := conn(ctx, o.store.db).QueryRowContext(ctx, "SELECT id, body, deleted, version FROM overlay WHERE resource = "+
+		o.store.dialect.Placeholder(1)+" AND id = "+o.store.dialect.Placeholder(2), o.resource, id)
+	record, err := o.scan(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return record, err
+}
+
+// Create guarda un elemento nuevo con el siguiente id libre desde OverlayIDBase
+func (o *Overlay[T]) Create(ctx context.Context, item T) (*T, error) {
+	// El id se calcula y se guarda en la misma transacción para que dos altas no lo repitan
+	err := Transaction(ctx, o.store.db, func(ctx context.Context) error {
+		var last sql.NullInt64
+		err := conn(ctx, o.store.db).QueryRowContext(ctx, "SELECT MAX(id) FROM overlay WHERE resource = "+o.store.dialect.Placeholder(1)+
+			" AND id > "+o.store.dialect.Placeholder(2), o.resource, OverlayIDBase).Scan(&last)
+		if err != nil {
+			return err
+		}
+		id := OverlayIDBase + 1
+		if last.Valid {
+			id = int(last.Int64) + 1
+		}
+		SetID(&item, id)
+		return o.put(ctx, id, &item, false)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Put guarda el elemento id, que puede venir del upstream o haber sido creado localmente
+func (o *Overlay[T]) Put(ctx context.Context, id int, item T) (*T, error) {
+	SetID(&item, id)
+	if err := o.put(ctx, id, &item, false); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// PutVersion guarda el elemento id si su cambio local todavía tiene version; si no,
+// devuelve ErrVersionConflict
+func (o *Overlay[T]) PutVersion(ctx context.Context, id int, item T, version int) (*T, error) {
+	SetID(&item, id)
+	if err := o.putVersion(ctx, id, &item, false, version); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Delete marca el elemento id como borrado
+func (o *Overlay[T]) Delete(ctx context.Context, id int) error {
+	return o.put(ctx, id, nil, true)
+}
+
+// DeleteVersion marca el elemento id como borrado si su cambio local todavía tiene
+// version; si no, devuelve ErrVersionConflict
+func (o *Overlay[T]) DeleteVersion(ctx context.Context, id int, version int) error {
+	return o.putVersion(ctx, id, nil, true, version)
+}
+
+func (o *Overlay[T]) put(ctx context.Context, id int, item *T, deleted bool) error {
+	body, err := encode(item)
+	if err != nil {
+		return err
+	}
+	p := o.store.dialect.Placeholder
+	_, err = conn(ctx, o.store.db).ExecContext(ctx, "INSERT INTO overlay (resource, id, body, deleted, updated_at) VALUES ("+
+		p(1)+", "+p(2)+", "+p(3)+", "+p(4)+", "+p(5)+") ON CONFLICT (resource, id) DO UPDATE SET "+
+		"body = excluded.body, deleted = excluded.deleted, updated_at = excluded.updated_at, version = overlay.version + 1",
+		o.resource, id, body, deleted, time.Now().UTC())
+	return err
+}
+
+// putVersion escribe solo si el cambio local tiene version: con 0 lo crea si no existe,
+// con otra lo actualiza si nadie lo cambió desde que se leyó
+func (o *Overlay[T]) putVersion(ctx context.Context, id int, item *T, deleted bool, version int) error {
+	body, err := encode(item)
+	if err != nil {
+		return err
+	}
+	p := o.store.dialect.Placeholder
+	var result sql.Result
+	if version == 0 {
+		result, err = conn(ctx, o.store.db).ExecContext(ctx, "INSERT INTO overlay (resource, id, body, deleted, updated_at) VALUES ("+
+			p(1)+", "+p(2)+", "+p(3)+", "+p(4)+", "+p(5)+") ON CONFLICT (resource, id) DO NOTHING",
+			o.resource, id, body, deleted, time.Now().UTC())
+	} else {
+		result, err = conn(ctx, o.store.db).ExecContext(ctx, "UPDATE overlay SET body = "+p(1)+", deleted = "+p(2)+
+			", updated_at = "+p(3)+", version = version + 1 WHERE resource = "+p(4)+" AND id = "+p(5)+" AND version = "+p(6),
+			body, deleted, time.Now().UTC(), o.resource, id, version)
+	}
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// encode devuelve el cuerpo guardado de item, vacío para una marca de borrado
+func encode[T any](item *T) (string, error) {
+	if item == nil {
+		return "", nil
+	}
+	body, err := json.Marshal(item)
+	return string(body), err
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func (o *Overlay[T]) scan(row scanner) (*OverlayRecord[T], error) {
+	var record OverlayRecord[T]
+	var body string
+	if err := row.Scan(&record.ID, &body, &record.Deleted, &record.Version); err != nil {
+		return nil, err
+	}
+	if !record.Deleted {
+		if err := json.Unmarshal([]byte(body), &record.Item); err != nil {
+			return nil, err
+		}
+	}
+	return &record, nil
+}
+
+// ID devuelve el campo ID de una entidad de data
+func ID[T any](item T) int {
+	field := reflect.ValueOf(item).FieldByName("ID")
+	if !field.IsValid() {
+		return 0
+	}
+	return int(field.Int())
+}
+
+// SetID asigna el campo ID de una entidad de data
+func SetID[T any](item *T, id int) {
+	field := reflect.ValueOf(item).Elem().FieldByName("ID")
+	if field.IsValid() {
+		field.SetInt(int64(id))
+	}
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"blog-api/data"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverlay(t *testing.T) {
+	ctx := context.Background()
+	store := NewOverlayStore(openTestDB(t), SQLite)
+	posts := NewOverlay[data.Post](store, "posts")
+	todos := NewOverlay[data.Todo](store, "todos")
+
+	created, err := posts.Create(ctx, data.Post{UserID: 1, Title: "a"})
+	require.NoError(t, err)
+	require.Equal(t, OverlayIDBase+1, created.ID)
+	created, err = posts.Create(ctx, data.Post{UserID: 1, Title: "b"})
+	require.NoError(t, err)
+	require.Equal(t, OverlayIDBase+2, created.ID)
+	todo, err := todos.Create(ctx, data.Todo{Title: "c"})
+	require.NoError(t, err)
+	require.Equal(t, OverlayIDBase+1, todo.ID, "ids are allocated per resource")
+
+	_, err = posts.Put(ctx, 7, data.Post{UserID: 2, Title: "upstream"})
+	require.NoError(t, err)
+	require.NoError(t, posts.Delete(ctx, OverlayIDBase+1))
+
+	record, err := posts.Get(ctx, 7)
+	require.NoError(t, err)
+	require.Equal(t, data.Post{ID: 7, UserID: 2, Title: "upstream"}, record.Item)
+	record, err = posts.Get(ctx, 8)
+	require.NoError(t, err)
+	require.Nil(t, record)
+
+	records, err := posts.All(ctx)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	require.Equal(t, 7, records[0].ID)
+	require.True(t, records[1].Deleted)
+
+	// Las escrituras con versión solo se aplican si nadie cambió el elemento desde que se leyó
+	require.Equal(t, 1, records[0].Version)
+	_, err = posts.PutVersion(ctx, 7, data.Post{UserID: 2, Title: "stale"}, 0)
+	require.ErrorIs(t, err, ErrVersionConflict)
+	_, err = posts.PutVersion(ctx, 7, data.Post{UserID: 2, Title: "mine"}, 1)
+	require.NoError(t, err)
+	require.ErrorIs(t, posts.DeleteVersion(ctx, 7, 1), ErrVersionConflict)
+	_, err = posts.PutVersion(ctx, 9, data.Post{UserID: 2, Title: "new"}, 0)
+	require.NoError(t, err)
+	require.NoError(t, posts.DeleteVersion(ctx, 9, 1))
+	record, err = posts.Get(ctx, 9)
+	require.NoError(t, err)
+	require.True(t, record.Deleted)
+	require.Equal(t, 2, record.Version)
+
+	require.NoError(t, store.Reset(ctx, "posts"))
+	records, err = posts.All(ctx)
+	require.NoError(t, err)
+	require.Empty(t, records)
+	todoRecord, err := todos.Get(ctx, OverlayIDBase+1)
+	require.NoError(t, err)
+	require.NotNil(t, todoRecord)
+
+	require.NoError(t, store.Reset(ctx, ""))
+	todoRecord, err = todos.Get(ctx, OverlayIDBase+1)
+	require.NoError(t, err)
+	require.Nil(t, todoRecord)
+}
@@ -0,0 +1,243 @@
+package repository
+
+import (
+	apperrors "blog-api/app/errors"
+	"blog-api/app/pagination"
+	"blog-api/data"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrNotFound se devuelve cuando no existe un elemento con el id pedido
+var ErrNotFound = apperrors.ErrNotFound
+
+// ErrVersionConflict se devuelve cuando otra escritura cambió el elemento después de leerlo
+var ErrVersionConflict = apperrors.New(apperrors.ErrConflict, "the item was changed by another request")
+
+// Repository define el almacenamiento local de un recurso. Cada elemento tiene una
+// versión que aumenta con cada escritura; los métodos *Version escriben solo si el
+// elemento todavía tiene la versión leída (concurrencia optimista).
+type Repository[T any] interface {
+	List(ctx context.Context, filter map[string]string, page pagination.Pagination) ([]T, int, error)
+	Get(ctx context.Context, id int) (*T, error)
+	GetVersion(ctx context.Context, id int) (*T, int, error)
+	Create(ctx context.Context, item T) (*T, error)
+	Update(ctx context.Context, id int, item T) (*T, error)
+	UpdateVersion(ctx context.Context, id int, item T, version int) (*T, error)
+	Delete(ctx context.Context, id int) error
+	DeleteVersion(ctx context.Context, id int, version int) error
+}
+
+type (
+	PostRepository    = Repository[data.Post]
+	AlbumRepository   = Repository[data.Album]
+	PhotoRepository   = Repository[data.Photo]
+	CommentRepository = Repository[data.Comment]
+	TodoRepository    = Repository[data.Todo]
+	UserRepository    = Repository[data.User]
+)
+
+// column es el criterio de búsqueda de un query param
+type column struct {
+	name  string
+	parse func(string) (any, error)
+}
+
+func text(name string) column {
+	return column{name: name, parse: func(value string) (any, error) { return value, nil }}
+}
+
+func integer(name string) column {
+	return column{name: name, parse: func(value string) (any, error) { return strconv.Atoi(value) }}
+}
+
+func boolean(name string) column {
+	return column{name: name, parse: func(value string) (any, error) { return strconv.ParseBool(value) }}
+}
+
+// table describe cómo se guarda un recurso: sus columnas sin el id, en el mismo
+// orden que devuelven values y fields
+type table[T any] struct {
+	name    string
+	columns []string
+	filters map[string]column
+	// values devuelve los valores de las columnas
+	values func(item *T) []any
+	// fields devuelve los destinos del id y de las columnas para Scan
+	fields func(item *T) []any
+	setID  func(item *T, id int)
+}
+
+// SQLRepository implementa Repository sobre database/sql
+type SQLRepository[T any] struct {
+	db      *sql.DB
+	dialect Dialect
+	table   table[T]
+}
+
+// List obtiene los elementos filtrados, ordenados por id, y el total sin paginar
+func (r *SQLRepository[T]) List(ctx context.Context, filter map[string]string, page pagination.Pagination) ([]T, int, error) {
+	var where []string
+	var args []any
+	for key, value := range filter {
+		col, ok := r.table.filters[key]
+		if !ok {
+			continue
+		}
+		arg, err := col.parse(value)
+		if err != nil {
+			return nil, 0, apperrors.Newf(apperrors.ErrBadRequest, "invalid %s %q", key, value)
+		}
+		args = append(args, arg)
+		where = append(where, col.name+" = "+r.dialect.Placeholder(len(args)))
+	}
+	conditions := ""
+	if len(where) > 0 {
+		conditions = " WHERE " + strings.Join(where, " AND ")
+	}
+	var total int
+	err := r.conn(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM "+r.table.name+conditions, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+	query := r.selectFrom() + conditions + " ORDER BY id"
+	if page.Enabled() {
+		args = append(args, page.Limit, page.Offset())
+		query += fmt.Sprintf(" LIMIT %s OFFSET %s", r.dialect.Placeholder(len(args)-1), r.dialect.Placeholder(len(args)))
+	}
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	items := []T{}
+	for rows.Next() {
+		var item T
+		if err := rows.Scan(r.table.fields(&item)...); err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+	return items, total, rows.Err()
+}
+
+// Get obtiene un elemento por id
+func (r *SQLRepository[T]) Get(ctx context.Context, id int) (*T, error) {
+	item, _, err := r.GetVersion(ctx, id)
+	return item, err
+}
+
+// GetVersion obtiene un elemento por id junto con su versión
+func (r *SQLRepository[T]) GetVersion(ctx context.Context, id int) (*T, int, error) {
+	var item T
+	var version int
+	query := "SELECT id, " + strings.Join(r.table.columns, ", ") + ", version FROM " + r.table.name + " WHERE id = " + r.dialect.Placeholder(1)
+	err := r.conn(ctx).QueryRowContext(ctx, query, id).Scan(append(r.table.fields(&item), &version)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, 0, ErrNotFound
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return &item, version, nil
+}
+
+// Create guarda un elemento nuevo; el id lo asigna la base de datos
+func (r *SQLRepository[T]) Create(ctx context.Context, item T) (*T, error) {
+	placeholders := make([]string, len(r.table.columns))
+	for i := range placeholders {
+		placeholders[i] = r.dialect.Placeholder(i + 1)
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING id",
+		r.table.name, strings.Join(r.table.columns, ", "), strings.Join(placeholders, ", "))
+	var id int
+	if err := r.conn(ctx).QueryRowContext(ctx, query, r.table.values(&item)...).Scan(&id); err != nil {
+		return nil, err
+	}
+	r.table.setID(&item, id)
+	return &item, nil
+}
+
+// Update reemplaza un elemento existente
+func (r *SQLRepository[T]) Update(ctx context.Context, id int, item T) (*T, error) {
+	return r.update(ctx, id, item, nil)
+}
+
+// UpdateVersion reemplaza un elemento si todavía tiene version; si no, devuelve ErrVersionConflict
+func (r *SQLRepository[T]) UpdateVersion(ctx context.Context, id int, item T, version int) (*T, error) {
+	return r.update(ctx, id, item, &version)
+}
+
+func (r *SQLRepository[T]) update(ctx context.Context, id int, item T, version *int) (*T, error) {
+	assignments := make([]string, len(r.table.columns))
+	for i, name := range r.table.columns {
+		assignments[i] = name + " = " + r.dialect.Placeholder(i+1)
+	}
+	args := append(r.table.values(&item), id)
+	query := fmt.Sprintf("UPDATE %s SET %s, version = version + 1 WHERE id = %s",
+		r.table.name, strings.Join(assignments, ", "), r.dialect.Placeholder(len(args)))
+	if version != nil {
+		args = append(args, *version)
+		query += " AND version = " + r.dialect.Placeholder(len(args))
+	}
+	result, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.affected(ctx, result, id, version != nil); err != nil {
+		return nil, err
+	}
+	r.table.setID(&item, id)
+	return &item, nil
+}
+
+// Delete elimina un elemento
+func (r *SQLRepository[T]) Delete(ctx context.Context, id int) error {
+	result, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM "+r.table.name+" WHERE id = "+r.dialect.Placeholder(1), id)
+	if err != nil {
+		return err
+	}
+	return r.affected(ctx, result, id, false)
+}
+
+// DeleteVersion elimina un elemento si todavía tiene version; si no, devuelve ErrVersionConflict
+func (r *SQLRepository[T]) DeleteVersion(ctx context.Context, id int, version int) error {
+	result, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM "+r.table.name+" WHERE id = "+r.dialect.Placeholder(1)+
+		" AND version = "+r.dialect.Placeholder(2), id, version)
+	if err != nil {
+		return err
+	}
+	return r.affected(ctx, result, id, true)
+}
+
+// conn devuelve la transacción de ctx, si hay, o la base de datos
+func (r *SQLRepository[T]) conn(ctx context.Context) querier {
+	return conn(ctx, r.db)
+}
+
+func (r *SQLRepository[T]) selectFrom() string {
+	return "SELECT id, " + strings.Join(r.table.columns, ", ") + " FROM " + r.table.name
+}
+
+// affected comprueba que la escritura encontró el elemento. Si tenía una versión y no
+// cambió nada, distingue un elemento borrado de uno que cambió de versión.
+func (r *SQLRepository[T]) affected(ctx context.Context, result sql.Result, id int, versioned bool) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+	if !versioned {
+		return ErrNotFound
+	}
+	if _, _, err := r.GetVersion(ctx, id); err != nil {
+		return err
+	}
+	return ErrVersionConflict
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	apperrors "blog-api/app/errors"
+	"blog-api/app/pagination"
+	"blog-api/data"
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	ctx := context.Background()
+	db, err := Open(ctx, SQLite, ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	migrator, err := NewMigrator(db, SQLite)
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	return db
+}
+
+func TestDialect_Placeholder(t *testing.T) {
+	require.Equal(t, "?", SQLite.Placeholder(2))
+	require.Equal(t, "$2", Postgres.Placeholder(2))
+	dialect, err := DialectFor("postgres")
+	require.NoError(t, err)
+	require.Equal(t, Postgres, dialect)
+	_, err = DialectFor("mysql")
+	require.Error(t, err)
+}
+
+func TestSQLRepository_CRUD(t *testing.T) {
+	ctx := context.Background()
+	repo := NewTodoRepository(openTestDB(t), SQLite)
+
+	created, err := repo.Create(ctx, data.Todo{UserID: 1, Title: "a"})
+	require.NoError(t, err)
+	require.Equal(t, data.Todo{ID: 1, UserID: 1, Title: "a"}, *created)
+
+	item, err := repo.Get(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, *created, *item)
+
+	updated, err := repo.Update(ctx, 1, data.Todo{UserID: 2, Title: "b", Completed: true})
+	require.NoError(t, err)
+	require.Equal(t, data.Todo{ID: 1, UserID: 2, Title: "b", Completed: true}, *updated)
+
+	require.NoError(t, repo.Delete(ctx, 1))
+	_, err = repo.Get(ctx, 1)
+	require.ErrorIs(t, err, ErrNotFound)
+	require.ErrorIs(t, repo.Delete(ctx, 1), ErrNotFound)
+	_, err = repo.Update(ctx, 1, data.Todo{Title: "d"})
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestSQLRepository_Version(t *testing.T) {
+	ctx := context.Background()
+	repo := NewTodoRepository(openTestDB(t), SQLite)
+
+	created, err := repo.Create(ctx, data.Todo{UserID: 1, Title: "a"})
+	require.NoError(t, err)
+	_, version, err := repo.GetVersion(ctx, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, version)
+
+	// Cada escritura aumenta la versión, también las que no la comprueban
+	_, err = repo.Update(ctx, created.ID, data.Todo{UserID: 1, Title: "b"})
+	require.NoError(t, err)
+	_, err = repo.UpdateVersion(ctx, created.ID, data.Todo{UserID: 1, Title: "c"}, version)
+	require.ErrorIs(t, err, ErrVersionConflict)
+	item, version, err := repo.GetVersion(ctx, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, 2, version)
+	require.Equal(t, "b", item.Title)
+
+	_, err = repo.UpdateVersion(ctx, created.ID, data.Todo{UserID: 1, Title: "c"}, version)
+	require.NoError(t, err)
+	require.ErrorIs(t, repo.DeleteVersion(ctx, created.ID, version), ErrVersionConflict)
+	require.NoError(t, repo.DeleteVersion(ctx, created.ID, version+1))
+	require.ErrorIs(t, repo.DeleteVersion(ctx, created.ID, version+1), ErrNotFound)
+	_, err = repo.UpdateVersion(ctx, created.ID, data.Todo{UserID: 1, Title: "d"}, version+1)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestSQLRepository_List(t *testing.T) {
+	ctx := context.Background()
+	repo := NewTodoRepository(openTestDB(t), SQLite)
+	for i, title := range []string{"a", "b", "c", "d"} {
+		_, err := repo.Create(ctx, data.Todo{UserID: 1 + i%2, Title: title, Completed: i < 2})
+		require.NoError(t, err)
+	}
+
+	items, total, err := repo.List(ctx, map[string]string{"userId": "1"}, pagination.Pagination{})
+	require.NoError(t, err)
+	require.Equal(t, 2, total)
+	require.Equal(t, []string{"a", "c"}, titles(items))
+
+	items, total, err = repo.List(ctx, map[string]string{"completed": "true"}, pagination.Pagination{})
+	require.NoError(t, err)
+	require.Equal(t, 2, total)
+	require.Equal(t, []string{"a", "b"}, titles(items))
+
+	items, total, err = repo.List(ctx, nil, pagination.Pagination{Page: 2, Limit: 3})
+	require.NoError(t, err)
+	require.Equal(t, 4, total)
+	require.Equal(t, []string{"d"}, titles(items))
+
+	_, _, err = repo.List(ctx, map[string]string{"userId": "one"}, pagination.Pagination{})
+	require.ErrorIs(t, err, apperrors.ErrBadRequest)
+}
+
+func TestSQLRepository_User(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository(openTestDB(t), SQLite)
+
+	user := data.User{Name: "Leanne", Username: "Bret", Address: data.Address{City: "Gwenborough", Geo: data.Geo{Lat: "-37.3159"}}}
+	created, err := repo.Create(ctx, user)
+	require.NoError(t, err)
+	user.Address.Geo.Lng = "81.1496"
+	_, err = repo.Update(ctx, created.ID, user)
+	require.NoError(t, err)
+	item, err := repo.Get(ctx, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Gwenborough", item.Address.City)
+	require.Equal(t, data.Geo{Lat: "-37.3159", Lng: "81.1496"}, item.Address.Geo)
+}
+
+func titles(items []data.Todo) []string {
+	result := make([]string, len(items))
+	for i, item := range items {
+		result[i] = item.Title
+	}
+	return result
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"blog-api/data"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Snapshot es el contenido completo del almacenamiento local
+type Snapshot struct {
+	Users    []data.User    `json:"users"`
+	Posts    []data.Post    `json:"posts"`
+	Albums   []data.Album   `json:"albums"`
+	Photos   []data.Photo   `json:"photos"`
+	Comments []data.Comment `json:"comments"`
+	Todos    []data.Todo    `json:"todos"`
+	// Overlay son los cambios locales del modo overlay. Nil significa que el snapshot no
+	// los tiene, p. ej. el del upstream, y Import deja los que hay.
+	Overlay []OverlayChange `json:"overlay,omitempty"`
+}
+
+// OverlayChange es un cambio local del modo overlay como se guarda: el elemento, o
+// Deleted si es un borrado
+type OverlayChange struct {
+	Resource  string          `json:"resource"`
+	ID        int             `json:"id"`
+	Item      json.RawMessage `json:"item,omitempty"`
+	Deleted   bool            `json:"deleted,omitempty"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// Export lee todos los recursos dentro de una misma transacción, así la foto es consistente
+func Export(ctx context.Context, db *sql.DB, dialect Dialect) (*Snapshot, error) {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: dialect != SQLite})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	snapshot := &Snapshot{}
+	if snapshot.Users, err = selectAll(ctx, tx, usersTable); err != nil {
+		return nil, err
+	}
+	if snapshot.Posts, err = selectAll(ctx, tx, postsTable); err != nil {
+		return nil, err
+	}
+	if snapshot.Albums, err = selectAll(ctx, tx, albumsTable); err != nil {
+		return nil, err
+	}
+	if snapshot.Photos, err = selectAll(ctx, tx, photosTable); err != nil {
+		return nil, err
+	}
+	if snapshot.Comments, err = selectAll(ctx, tx, commentsTable); err != nil {
+		return nil, err
+	}
+	if snapshot.Todos, err = selectAll(ctx, tx, todosTable); err != nil {
+		return nil, err
+	}
+	if snapshot.Overlay, err = selectOverlay(ctx, tx); err != nil {
+		return nil, err
+	}
+	return snapshot, tx.Commit()
+}
+
+// Import reemplaza todos los recursos por los de snapshot, conservando sus ids, y los
+// cambios del overlay si snapshot los tiene. Si algo falla no se cambia nada.
+func Import(ctx context.Context, db *sql.DB, dialect Dialect, snapshot *Snapshot) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, name := range []string{todosTable.name, commentsTable.name, photosTable.name, albumsTable.name, postsTable.name, usersTable.name} {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+name); err != nil {
+			return err
+		}
+	}
+	if err := insertAll(ctx, tx, dialect, usersTable, snapshot.Users); err != nil {
+		return err
+	}
+	if err := insertAll(ctx, tx, dialect, postsTable, snapshot.Posts); err != nil {
+		return err
+	}
+	if err := insertAll(ctx, tx, dialect, albumsTable, snapshot.Albums); err != nil {
+		return err
+	}
+	if err := insertAll(ctx, tx, dialect, photosTable, snapshot.Photos); err != nil {
+		return err
+	}
+	if err := insertAll(ctx, tx, dialect, commentsTable, snapshot.Comments); err != nil {
+		return err
+	}
+	if err := insertAll(ctx, tx, dialect, todosTable, snapshot.Todos); err != nil {
+		return err
+	}
+	if snapshot.Overlay != nil {
+		if err := insertOverlay(ctx, tx, dialect, snapshot.Overlay); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func selectAll[T any](ctx context.Context, db querier, t table[T]) ([]T, error) {
+	rows, err := db.QueryContext(ctx, "SELECT id, "+strings.Join(t.columns, ", ")+" FROM "+t.name+" ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []T{}
+	for rows.Next() {
+		var item T
+		if err := rows.Scan(t.fields(&item)...); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// insertAll guarda los elementos con su id y luego mueve la secuencia del id
+// para que los próximos elementos creados no choquen
+func insertAll[T any](ctx context.Context, tx *sql.Tx, dialect Dialect, t table[T], items []T) error {
+	placeholders := make([]string, len(t.columns)+1)
+	for i := range placeholders {
+		placeholders[i] = dialect.Placeholder(i + 1)
+	}
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf("INSERT INTO %s (id, %s) VALUES (%s)",
+		t.name, strings.Join(t.columns, ", "), strings.Join(placeholders, ", ")))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, item := range items {
+		args := append([]any{ID(item)}, t.values(&item)...)
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			return fmt.Errorf("insert %s %d: %w", t.name, ID(item), err)
+		}
+	}
+	if dialect.ResetSequence != "" {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(dialect.ResetSequence, t.name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// selectOverlay lee los cambios del overlay de todos los recursos
+func selectOverlay(ctx context.Context, db querier) ([]OverlayChange, error) {
+	rows, err := db.QueryContext(ctx, "SELECT resource, id, body, deleted, updated_at FROM overlay ORDER BY resource, id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	changes := []OverlayChange{}
+	for rows.Next() {
+		var change OverlayChange
+		var body string
+		if err := rows.Scan(&change.Resource, &change.ID, &body, &change.Deleted, &change.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if !change.Deleted {
+			change.Item = json.RawMessage(body)
+		}
+		change.UpdatedAt = change.UpdatedAt.UTC()
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
+
+// insertOverlay reemplaza los cambios del overlay por changes
+func insertOverlay(ctx context.Context, tx *sql.Tx, dialect Dialect, changes []OverlayChange) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM overlay"); err != nil {
+		return err
+	}
+	p := dialect.Placeholder
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO overlay (resource, id, body, deleted, updated_at) VALUES ("+
+		p(1)+", "+p(2)+", "+p(3)+", "+p(4)+", "+p(5)+")")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, change := range changes {
+		var body bytes.Buffer
+		if !change.Deleted {
+			if err := json.Compact(&body, change.Item); err != nil {
+				return fmt.Errorf("overlay %s %d: %w", change.Resource, change.ID, err)
+			}
+		}
+		if _, err := stmt.ExecContext(ctx, change.Resource, change.ID, body.String(), change.Deleted, change.UpdatedAt.UTC()); err != nil {
+			return fmt.Errorf("insert overlay %s %d: %w", change.Resource, change.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"blog-api/data"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot_ExportImport(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	posts := NewPostRepository(db, SQLite)
+	_, err := posts.Create(ctx, data.Post{UserID: 1, Title: "stale"})
+	require.NoError(t, err)
+
+	snapshot := &Snapshot{
+		Users:    []data.User{{ID: 3, Name: "Ana", Address: data.Address{City: "Lima"}}},
+		Posts:    []data.Post{{ID: 5, UserID: 3, Title: "a", Body: "b"}, {ID: 9, UserID: 3, Title: "c"}},
+		Albums:   []data.Album{{ID: 2, UserID: 3, Title: "album"}},
+		Photos:   []data.Photo{{ID: 4, AlbumID: 2, Title: "photo", URL: "/files/photos/a.png", ThumbnailURL: "/files/photos/a_thumb.png"}},
+		Comments: []data.Comment{},
+		Todos:    []data.Todo{{ID: 7, UserID: 3, Title: "todo", Completed: true}},
+		Overlay:  []OverlayChange{},
+	}
+	require.NoError(t, Import(ctx, db, SQLite, snapshot))
+
+	exported, err := Export(ctx, db, SQLite)
+	require.NoError(t, err)
+	require.Equal(t, snapshot, exported)
+
+	// Los ids nuevos siguen a los importados
+	created, err := posts.Create(ctx, data.Post{UserID: 3, Title: "new"})
+	require.NoError(t, err)
+	require.Equal(t, 10, created.ID)
+}
+
+func TestSnapshot_ImportIsAtomic(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	require.NoError(t, Import(ctx, db, SQLite, &Snapshot{Posts: []data.Post{{ID: 1, UserID: 1, Title: "kept"}}}))
+
+	duplicated := &Snapshot{Todos: []data.Todo{{ID: 1, UserID: 1, Title: "a"}, {ID: 1, UserID: 1, Title: "b"}}}
+	require.Error(t, Import(ctx, db, SQLite, duplicated))
+
+	exported, err := Export(ctx, db, SQLite)
+	require.NoError(t, err)
+	require.Equal(t, []data.Post{{ID: 1, UserID: 1, Title: "kept"}}, exported.Posts)
+	require.Empty(t, exported.Todos)
+}
+
+func TestSnapshot_Overlay(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	posts := NewOverlay[data.Post](NewOverlayStore(db, SQLite), "posts")
+	_, err := posts.Put(ctx, 1, data.Post{UserID: 1, Title: "local"})
+	require.NoError(t, err)
+	require.NoError(t, posts.Delete(ctx, 2))
+
+	// Los cambios locales viajan en el snapshot y vuelven con el import
+	exported, err := Export(ctx, db, SQLite)
+	require.NoError(t, err)
+	require.Len(t, exported.Overlay, 2)
+	require.JSONEq(t, `{"id": 1, "userId": 1, "title": "local", "body": ""}`, string(exported.Overlay[0].Item))
+	require.True(t, exported.Overlay[1].Deleted)
+	require.NoError(t, posts.Delete(ctx, 1))
+	require.NoError(t, Import(ctx, db, SQLite, exported))
+	record, err := posts.Get(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, "local", record.Item.Title)
+	again, err := Export(ctx, db, SQLite)
+	require.NoError(t, err)
+	require.Equal(t, exported, again)
+
+	// Un snapshot sin overlay, como el del upstream, no los toca
+	require.NoError(t, Import(ctx, db, SQLite, &Snapshot{}))
+	records, err := posts.All(ctx)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	// Uno con la lista vacía los reemplaza
+	updatedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	item := json.RawMessage(`{"id":1000001,"userId":1,"title":"new","body":""}`)
+	changes := []OverlayChange{{Resource: "posts", ID: 1000001, Item: item, UpdatedAt: updatedAt}}
+	require.NoError(t, Import(ctx, db, SQLite, &Snapshot{Overlay: changes}))
+	exported, err = Export(ctx, db, SQLite)
+	require.NoError(t, err)
+	require.Equal(t, changes, exported.Overlay)
+}
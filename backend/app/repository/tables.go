@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"blog-api/data"
+	"database/sql"
+)
+
+var postsTable = table[data.Post]{
+	name:    "posts",
+	columns: []string{"user_id", "title", "body"},
+	filters: map[string]column{"id": integer("id"), "userId": integer("user_id"), "title": text("title")},
+	values:  func(p *data.Post) []any { return []any{p.UserID, p.Title, p.Body} },
+	fields:  func(p *data.Post) []any { return []any{&p.ID, &p.UserID, &p.Title, &p.Body} },
+	setID:   func(p *data.Post, id int) { p.ID = id },
+}
+
+var albumsTable = table[data.Album]{
+	name:    "albums",
+	columns: []string{"user_id", "title"},
+	filters: map[string]column{"id": integer("id"), "userId": integer("user_id"), "title": text("title")},
+	values:  func(a *data.Album) []any { return []any{a.UserID, a.Title} },
+	fields:  func(a *data.Album) []any { return []any{&a.ID, &a.UserID, &a.Title} },
+	setID:   func(a *data.Album, id int) { a.ID = id },
+}
+
+var commentsTable = table[data.Comment]{
+	name:    "comments",
+	columns: []string{"post_id", "parent_id", "name", "email", "body"},
+	filters: map[string]column{"id": integer("id"), "postId": integer("post_id"), "parentId": integer("parent_id"), "name": text("name"), "email": text("email")},
+	values:  func(c *data.Comment) []any { return []any{c.PostID, c.ParentID, c.Name, c.Email, c.Body} },
+	fields:  func(c *data.Comment) []any { return []any{&c.ID, &c.PostID, &c.ParentID, &c.Name, &c.Email, &c.Body} },
+	setID:   func(c *data.Comment, id int) { c.ID = id },
+}
+
+var todosTable = table[data.Todo]{
+	name:    "todos",
+	columns: []string{"user_id", "title", "completed"},
+	filters: map[string]column{"id": integer("id"), "userId": integer("user_id"), "title": text("title"), "completed": boolean("completed")},
+	values:  func(t *data.Todo) []any { return []any{t.UserID, t.Title, t.Completed} },
+	fields:  func(t *data.Todo) []any { return []any{&t.ID, &t.UserID, &t.Title, &t.Completed} },
+	setID:   func(t *data.Todo, id int) { t.ID = id },
+}
+
+var photosTable = table[data.Photo]{
+	name:    "photos",
+	columns: []string{"album_id", "title", "url", "thumbnail_url"},
+	filters: map[string]column{"id": integer("id"), "albumId": integer("album_id"), "title": text("title")},
+	values:  func(p *data.Photo) []any { return []any{p.AlbumID, p.Title, p.URL, p.ThumbnailURL} },
+	fields:  func(p *data.Photo) []any { return []any{&p.ID, &p.AlbumID, &p.Title, &p.URL, &p.ThumbnailURL} },
+	setID:   func(p *data.Photo, id int) { p.ID = id },
+}
+
+// usersTable guarda la dirección y la compañía en columnas planas
+var usersTable = table[data.User]{
+	name: "users",
+	columns: []string{"name", "username", "email", "phone", "website",
+		"address_street", "address_suite", "address_city", "address_zipcode", "address_geo_lat", "address_geo_lng",
+		"company_name", "company_catch_phrase", "company_bs"},
+	filters: map[string]column{"id": integer("id"), "username": text("username"), "email": text("email")},
+	values: func(u *data.User) []any {
+		return []any{u.Name, u.Username, u.Email, u.Phone, u.Website,
+			u.Address.Street, u.Address.Suite, u.Address.City, u.Address.Zipcode, u.Address.Geo.Lat, u.Address.Geo.Lng,
+			u.Company.Name, u.Company.CatchPhrase, u.Company.Bs}
+	},
+	fields: func(u *data.User) []any {
+		return []any{&u.ID, &u.Name, &u.Username, &u.Email, &u.Phone, &u.Website,
+			&u.Address.Street, &u.Address.Suite, &u.Address.City, &u.Address.Zipcode, &u.Address.Geo.Lat, &u.Address.Geo.Lng,
+			&u.Company.Name, &u.Company.CatchPhrase, &u.Company.Bs}
+	},
+	setID: func(u *data.User, id int) { u.ID = id },
+}
+
+// NewPostRepository crea el repositorio de posts
+func NewPostRepository(db *sql.DB, dialect Dialect) PostRepository {
+	return &SQLRepository[data.Post]{db: db, dialect: dialect, table: postsTable}
+}
+
+// NewAlbumRepository crea el repositorio de albums
+func NewAlbumRepository(db *sql.DB, dialect Dialect) AlbumRepository {
+	return &SQLRepository[data.Album]{db: db, dialect: dialect, table: albumsTable}
+}
+
+// NewPhotoRepository crea el repositorio de photos
+func NewPhotoRepository(db *sql.DB, dialect Dialect) PhotoRepository {
+	return &SQLRepository[data.Photo]{db: db, dialect: dialect, table: photosTable}
+}
+
+// NewCommentRepository crea el repositorio de comments
+func NewCommentRepository(db *sql.DB, dialect Dialect) CommentRepository {
+	return &SQLRepository[data.Comment]{db: db, dialect: dialect, table: commentsTable}
+}
+
+// NewTodoRepository crea el repositorio de todos
+func NewTodoRepository(db *sql.DB, dialect Dialect) TodoRepository {
+	return &SQLRepository[data.Todo]{db: db, dialect: dialect, table: todosTable}
+}
+
+// NewUserRepository crea el repositorio de users
+func NewUserRepository(db *sql.DB, dialect Dialect) UserRepository {
+	return &SQLRepository[data.User]{db: db, dialect: dialect, table: usersTable}
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"blog-api/app/pagination"
+	"blog-api/data"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransaction(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	todos := NewTodoRepository(db, SQLite)
+	overlay := NewOverlay[data.Post](NewOverlayStore(db, SQLite), "posts")
+
+	// Si fn falla no queda ninguna de sus escrituras
+	failed := errors.New("failed")
+	err := Transaction(ctx, db, func(ctx context.Context) error {
+		_, err := todos.Create(ctx, data.Todo{UserID: 1, Title: "a"})
+		require.NoError(t, err)
+		_, err = overlay.Create(ctx, data.Post{UserID: 1, Title: "b"})
+		require.NoError(t, err)
+		// Dentro de la transacción se leen sus propias escrituras
+		_, err = todos.Get(ctx, 1)
+		require.NoError(t, err)
+		return failed
+	})
+	require.ErrorIs(t, err, failed)
+	items, total, err := todos.List(ctx, nil, pagination.Pagination{})
+	require.NoError(t, err)
+	require.Empty(t, items)
+	require.Zero(t, total)
+	records, err := overlay.All(ctx)
+	require.NoError(t, err)
+	require.Empty(t, records)
+
+	// Si fn termina bien se confirman todas, también las de una transacción anidada
+	err = Transaction(ctx, db, func(ctx context.Context) error {
+		if _, err := todos.Create(ctx, data.Todo{UserID: 1, Title: "a"}); err != nil {
+			return err
+		}
+		return Transaction(ctx, db, func(ctx context.Context) error {
+			_, err := todos.Create(ctx, data.Todo{UserID: 1, Title: "b"})
+			return err
+		})
+	})
+	require.NoError(t, err)
+	_, total, err = todos.List(ctx, nil, pagination.Pagination{})
+	require.NoError(t, err)
+	require.Equal(t, 2, total)
+}
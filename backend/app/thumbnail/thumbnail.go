@@ -0,0 +1,108 @@
+package thumbnail
+
+import (
+	apperrors "blog-api/app/errors"
+	"blog-api/app/i18n"
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+// Tipos de imagen aceptados
+const (
+	JPEG = "image/jpeg"
+	PNG  = "image/png"
+)
+
+// MaxPixels es la cantidad máxima de píxeles de una imagen que se acepta decodificar,
+// para que un archivo chico no pueda ocupar gigas de memoria al descomprimirse
+const MaxPixels = 50_000_000
+
+// ErrUnsupported indica que el contenido no es una imagen JPEG o PNG
+var ErrUnsupported = apperrors.New(apperrors.ErrUnsupportedMediaType, "unsupported image type")
+
+// ErrTooLarge indica que la imagen supera MaxPixels
+var ErrTooLarge = apperrors.New(apperrors.ErrPayloadTooLarge, "image dimensions too large")
+
+// Sniff devuelve el tipo de la imagen según su contenido, sin confiar en el
+// Content-Type declarado por el cliente, o ErrUnsupported si no es JPEG ni PNG
+func Sniff(content []byte) (string, error) {
+	switch contentType := http.DetectContentType(content); contentType {
+	case JPEG, PNG:
+		return contentType, nil
+	default:
+		return "", i18n.Errorf("%w: %s", ErrUnsupported, contentType)
+	}
+}
+
+// Generate devuelve una miniatura de la imagen que entra en un cuadrado de size
+// píxeles de lado, en el mismo formato. Las imágenes más chicas no se agrandan.
+func Generate(content []byte, size int) ([]byte, string, error) {
+	contentType, err := Sniff(content)
+	if err != nil {
+		return nil, "", err
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, "", i18n.Errorf("%w: %w", ErrUnsupported, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxPixels {
+		return nil, "", i18n.Errorf("%w: %dx%d", ErrTooLarge, config.Width, config.Height)
+	}
+	src, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, "", i18n.Errorf("%w: %w", ErrUnsupported, err)
+	}
+	width, height := fit(config.Width, config.Height, size)
+	thumb := resize(src, width, height)
+	var out bytes.Buffer
+	if contentType == PNG {
+		err = png.Encode(&out, thumb)
+	} else {
+		err = jpeg.Encode(&out, thumb, &jpeg.Options{Quality: 85})
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return out.Bytes(), contentType, nil
+}
+
+// fit devuelve las dimensiones que entran en size x size manteniendo la proporción
+func fit(width, height, size int) (int, int) {
+	if width <= size && height <= size {
+		return width, height
+	}
+	if width >= height {
+		return size, max(1, height*size/width)
+	}
+	return max(1, width*size/height), size
+}
+
+// resize reduce src a width x height promediando los píxeles de origen que cubre
+// cada píxel de destino, que da mejores miniaturas que tomar el vecino más cercano
+func resize(src image.Image, width, height int) *image.NRGBA {
+	bounds := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*bounds.Dy()/height)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*bounds.Dx()/width)
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					// RGBA devuelve valores premultiplicados, así los píxeles transparentes no tiñen el promedio
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,97 @@
+package thumbnail
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newImage devuelve una imagen de width x height, roja a la izquierda y azul a la derecha
+func newImage(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if x < width/2 {
+				img.Set(x, y, color.NRGBA{R: 255, A: 255})
+			} else {
+				img.Set(x, y, color.NRGBA{B: 255, A: 255})
+			}
+		}
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestGenerate(t *testing.T) {
+	t.Run("png", func(t *testing.T) {
+		thumb, contentType, err := Generate(encodePNG(t, newImage(400, 200)), 100)
+		require.NoError(t, err)
+		require.Equal(t, PNG, contentType)
+		img, err := png.Decode(bytes.NewReader(thumb))
+		require.NoError(t, err)
+		require.Equal(t, image.Rect(0, 0, 100, 50), img.Bounds())
+		r, _, b, _ := img.At(10, 10).RGBA()
+		require.Equal(t, [2]uint32{0xffff, 0}, [2]uint32{r, b})
+		r, _, b, _ = img.At(90, 40).RGBA()
+		require.Equal(t, [2]uint32{0, 0xffff}, [2]uint32{r, b})
+	})
+	t.Run("jpeg", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, jpeg.Encode(&buf, newImage(300, 600), nil))
+		thumb, contentType, err := Generate(buf.Bytes(), 150)
+		require.NoError(t, err)
+		require.Equal(t, JPEG, contentType)
+		config, err := jpeg.DecodeConfig(bytes.NewReader(thumb))
+		require.NoError(t, err)
+		require.Equal(t, [2]int{75, 150}, [2]int{config.Width, config.Height})
+	})
+	t.Run("small images keep their size", func(t *testing.T) {
+		thumb, _, err := Generate(encodePNG(t, newImage(20, 10)), 150)
+		require.NoError(t, err)
+		config, err := png.DecodeConfig(bytes.NewReader(thumb))
+		require.NoError(t, err)
+		require.Equal(t, [2]int{20, 10}, [2]int{config.Width, config.Height})
+	})
+	t.Run("unsupported", func(t *testing.T) {
+		_, _, err := Generate([]byte("GIF89a not really"), 150)
+		require.ErrorIs(t, err, ErrUnsupported)
+		_, _, err = Generate([]byte("<html><body>hi</body></html>"), 150)
+		require.ErrorIs(t, err, ErrUnsupported)
+		// Parece un PNG pero está cortado
+		_, _, err = Generate(encodePNG(t, newImage(20, 10))[:40], 150)
+		require.ErrorIs(t, err, ErrUnsupported)
+	})
+	t.Run("too many pixels", func(t *testing.T) {
+		content := encodePNG(t, newImage(1, 1))
+		// Agranda las dimensiones declaradas en el encabezado IHDR y recalcula su CRC
+		binary.BigEndian.PutUint32(content[16:], 100_000)
+		binary.BigEndian.PutUint32(content[20:], 100_000)
+		binary.BigEndian.PutUint32(content[29:], crc32.ChecksumIEEE(content[12:29]))
+		_, _, err := Generate(content, 150)
+		require.ErrorIs(t, err, ErrTooLarge)
+	})
+}
+
+func TestFit(t *testing.T) {
+	for _, tc := range []struct{ width, height, want1, want2 int }{
+		{300, 150, 150, 75},
+		{150, 300, 75, 150},
+		{1000, 1, 150, 1},
+		{100, 100, 100, 100},
+	} {
+		width, height := fit(tc.width, tc.height, 150)
+		require.Equal(t, [2]int{tc.want1, tc.want2}, [2]int{width, height})
+	}
+}
@@ -2,22 +2,41 @@ package albums
 
 import (
 	albums "blog-api/app/v1/albums/service"
+	resource "blog-api/app/v1/resource/handler"
 	"blog-api/data"
-	"encoding/json"
-	"github.com/go-chi/chi/v5"
 	"net/http"
-	"strconv"
 )
 
 // AlbumHandler maneja las solicitudes relacionadas con albums
 type AlbumHandler struct {
-	postService albums.IAlbumService
+	*resource.ResourceHandler[data.Album]
 }
 
 // NewAlbumHandler crea una nueva instancia del manejador de albums
-func NewAlbumHandler(postService albums.IAlbumService) *AlbumHandler {
+func NewAlbumHandler(albumService albums.IAlbumService) *AlbumHandler {
 	return &AlbumHandler{
-		postService: postService,
+		ResourceHandler: resource.NewResourceHandler(resource.Operations[data.Album]{
+			List:   albumService.GetAlbums,
+			Get:    albumService.GetAlbum,
+			Create: albumService.CreateAlbum,
+			Update: albumService.UpdateAlbum,
+			Patch:  albumService.PatchAlbum,
+			Delete: albumService.DeleteAlbum,
+		}, "albumID", albums.Filters...),
+	}
+}
+
+// Routes devuelve las rutas de albums para MountResource
+func (ph *AlbumHandler) Routes() resource.Routes {
+	return resource.Routes{
+		IDParam: ph.IDParam(),
+		List:    ph.GetAlbums,
+		Create:  ph.CreateAlbum,
+		Get:     ph.GetAlbum,
+		Update:  ph.UpdateAlbum,
+		Patch:   ph.PatchAlbum,
+		Delete:  ph.DeleteAlbum,
+		Bulk:    ph.BulkAlbums,
 	}
 }
 
@@ -29,18 +48,12 @@ func NewAlbumHandler(postService albums.IAlbumService) *AlbumHandler {
 // @Produce      json
 // @Success      200
 // @Failure      500
+// @Param        page   query int    false "Page number"
+// @Param        limit  query int    false "Page size"
+// @Param        cursor query string false "Opaque cursor from X-Next-Cursor"
 // @Router       ///v1/albums [get] .
 func (ph *AlbumHandler) GetAlbums(w http.ResponseWriter, r *http.Request) {
-	title := r.URL.Query().Get("title")
-	userID, _ := strconv.Atoi(r.URL.Query().Get("userId"))
-	id, _ := strconv.Atoi(r.URL.Query().Get("id"))
-	albums, err := ph.postService.GetAlbums(title, userID, id)
-	if err != nil {
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(albums)
+	ph.List(w, r)
 }
 
 // GetAlbum godoc
@@ -50,25 +63,12 @@ func (ph *AlbumHandler) GetAlbums(w http.ResponseWriter, r *http.Request) {
 // @Accept		 json
 // @Produce      json
 // @Success      200
+// @Success      304
 // @Failure      400
 // @Failure      404
-// @Router       ///v1/post/{postId} [get] .
+// @Router       ///v1/post/{albumID} [get] .
 func (ph *AlbumHandler) GetAlbum(w http.ResponseWriter, r *http.Request) {
-	postID := chi.URLParam(r, "postID")
-	id, err := strconv.Atoi(postID)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	post, err := ph.postService.GetAlbum(id)
-	if err != nil {
-		http.Error(w, "Not Found", http.StatusNotFound)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(post)
+	ph.Get(w, r)
 }
 
 // CreateAlbum GetAlbum godoc
@@ -82,26 +82,11 @@ func (ph *AlbumHandler) GetAlbum(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Success      201
 // @Failure      400
+// @Failure      422
 // @Failure      500
-// @Router       ///v1/post/{postId} [post] .
+// @Router       ///v1/post/{albumID} [post] .
 func (ph *AlbumHandler) CreateAlbum(w http.ResponseWriter, r *http.Request) {
-	var post data.Album
-	err := json.NewDecoder(r.Body).Decode(&post)
-	if err != nil {
-
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	createdAlbum, err := ph.postService.CreateAlbum(post)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(createdAlbum)
+	ph.Create(w, r)
 }
 
 // UpdateAlbum godoc
@@ -116,38 +101,19 @@ func (ph *AlbumHandler) CreateAlbum(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Success      200
 // @Failure      400
+// @Failure      412
+// @Failure      422
 // @Failure      500
-// @Router       ///v1/post/{postId} [post] .
+// @Router       ///v1/post/{albumID} [post] .
 func (ph *AlbumHandler) UpdateAlbum(w http.ResponseWriter, r *http.Request) {
-	postID := chi.URLParam(r, "postID")
-	id, err := strconv.Atoi(postID)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	var post data.Album
-	err = json.NewDecoder(r.Body).Decode(&post)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	updatedAlbum, err := ph.postService.UpdateAlbum(id, post)
-	if err != nil {
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(updatedAlbum)
+	ph.Update(w, r)
 }
 
 // PatchAlbum godoc
 // @Description Handler to patch post
 // @Tags Albums
 // @Description.markdown patch post
-// @Accept		 json
+// @Accept		 json,application/merge-patch+json,application/json-patch+json
 // @UrlParam  		AlbumID path string true "AlbumID"
 // @Param  		UserID path string true "UserID"
 // @Param  		Title path string true "Title"
@@ -155,31 +121,13 @@ func (ph *AlbumHandler) UpdateAlbum(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Success      200
 // @Failure      400
+// @Failure      412
+// @Failure      415
+// @Failure      422
 // @Failure      500
-// @Router       ///v1/post/{postId} [patch] .
+// @Router       ///v1/post/{albumID} [patch] .
 func (ph *AlbumHandler) PatchAlbum(w http.ResponseWriter, r *http.Request) {
-	postID := chi.URLParam(r, "postID")
-	id, err := strconv.Atoi(postID)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	var post data.Album
-	err = json.NewDecoder(r.Body).Decode(&post)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	patchedAlbum, err := ph.postService.UpdateAlbum(id, post)
-	if err != nil {
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(patchedAlbum)
+	ph.Patch(w, r)
 }
 
 // DeleteAlbum godoc
@@ -191,21 +139,27 @@ func (ph *AlbumHandler) PatchAlbum(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Success      204
 // @Failure      400
+// @Failure      412
+// @Failure      422
 // @Failure      500
-// @Router       ///v1/post/{postId} [patch] .
+// @Router       ///v1/post/{albumID} [patch] .
 func (ph *AlbumHandler) DeleteAlbum(w http.ResponseWriter, r *http.Request) {
-	postID := chi.URLParam(r, "postID")
-	id, err := strconv.Atoi(postID)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	err = ph.postService.DeleteAlbum(id)
-	if err != nil {
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
+	ph.Delete(w, r)
+}
 
-	w.WriteHeader(http.StatusNoContent)
+// BulkAlbums godoc
+// @Description Handler to create, update and delete albums in one request
+// @Tags Albums
+// @Description.markdown bulk albums
+// @Accept		 json,application/x-ndjson
+// @Produce      json
+// @Param        atomic query bool false "Apply all operations or none"
+// @Success      207
+// @Failure      400
+// @Failure      413
+// @Failure      415
+// @Failure      500
+// @Router       ///v1/albums/bulk [post] .
+func (ph *AlbumHandler) BulkAlbums(w http.ResponseWriter, r *http.Request) {
+	ph.Bulk(w, r)
 }
@@ -2,11 +2,14 @@ package albums
 
 import (
 	"blog-api/app/mocks"
+	"blog-api/app/pagination"
+	resource "blog-api/app/v1/resource/service"
 	"blog-api/data"
 	"bytes"
 	"context"
 	"encoding/json"
 	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"net/http"
 	"net/http/httptest"
@@ -20,9 +23,9 @@ func TestGetAlbums_Success(t *testing.T) {
 	// Mock expected albums
 	mockAlbums := []data.Album{{ID: 1, Title: "My Album", UserID: 1}}
 	// Set mock expectations
-	mockAlbumService.On("GetAlbums", "", 0, 0).Return(&mockAlbums, nil)
+	mockAlbumService.On("GetAlbums", mock.Anything, resource.Filter{}, pagination.Pagination{}).Return(&mockAlbums, len(mockAlbums), nil)
 	// Create handler and request
-	handler := AlbumHandler{postService: mockAlbumService}
+	handler := NewAlbumHandler(mockAlbumService)
 	req, _ := http.NewRequest("GET", "/albums", nil)
 	// Create mock response recorder
 	mockRecorder := httptest.NewRecorder()
@@ -43,11 +46,11 @@ func TestGetAlbum_Success(t *testing.T) {
 	// Mock expected post
 	mockAlbum := data.Album{ID: 1, Title: "My Album", UserID: 1}
 	// Set mock expectations
-	mockAlbumService.On("GetAlbum", 1).Return(&mockAlbum, nil)
+	mockAlbumService.On("GetAlbum", mock.Anything, 1).Return(&mockAlbum, nil)
 	// Create handler and request
-	handler := &AlbumHandler{postService: mockAlbumService}
+	handler := NewAlbumHandler(mockAlbumService)
 	req, _ := http.NewRequest("GET", "/albums/1", nil)
-	ctx.URLParams.Add("postID", "1")
+	ctx.URLParams.Add("albumID", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
 	// Mock response recorder
 	mockRecorder := httptest.NewRecorder()
@@ -68,9 +71,9 @@ func TestCreateAlbum_Success(t *testing.T) {
 	mockAlbum := data.Album{Title: "My Album", UserID: 1}
 	mockCreatedAlbum := data.Album{ID: 1, Title: "My Album", UserID: 1}
 	// Set mock expectations
-	mockAlbumService.On("CreateAlbum", mockAlbum).Return(&mockCreatedAlbum, nil)
+	mockAlbumService.On("CreateAlbum", mock.Anything, mockAlbum).Return(&mockCreatedAlbum, nil)
 	// Create handler and request
-	handler := &AlbumHandler{postService: mockAlbumService}
+	handler := NewAlbumHandler(mockAlbumService)
 	reqBody, err := json.Marshal(mockAlbum)
 	require.NoError(t, err)
 	req, _ := http.NewRequest(http.MethodPost, "/albums", bytes.NewReader(reqBody))
@@ -95,15 +98,15 @@ func TestUpdateAlbum_Success(t *testing.T) {
 	mockUpdatedAlbum := data.Album{ID: 1, Title: "Updated Album", UserID: 1}
 
 	// Set mock expectations
-	mockAlbumService.On("UpdateAlbum", 1, mockAlbum).Return(&mockUpdatedAlbum, nil)
+	mockAlbumService.On("UpdateAlbum", mock.Anything, 1, mockAlbum).Return(&mockUpdatedAlbum, nil)
 
 	// Create handler and request
-	handler := &AlbumHandler{postService: mockAlbumService}
+	handler := NewAlbumHandler(mockAlbumService)
 	reqBody, err := json.Marshal(mockAlbum)
 	require.NoError(t, err)
 	req, _ := http.NewRequest(http.MethodPut, "/albums/1", bytes.NewReader(reqBody))
 	ctx := chi.NewRouteContext()
-	ctx.URLParams.Add("postID", "1")
+	ctx.URLParams.Add("albumID", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
 
 	// Mock response recorder
@@ -124,20 +127,24 @@ func TestPatchAlbum_Success(t *testing.T) {
 	mockAlbumService := mocks.NewIAlbumService(t)
 	defer mockAlbumService.AssertExpectations(t)
 
-	// Mock expected post with only updated title
-	mockAlbum := data.Album{Title: "Updated Title"}
+	// Mock current album and the expected one after applying the patch
+	mockCurrentAlbum := data.Album{ID: 1, Title: "Old Title", UserID: 1}
 	mockPatchedAlbum := data.Album{ID: 1, Title: "Updated Title", UserID: 1}
 
-	// Set mock expectations
-	mockAlbumService.On("UpdateAlbum", 1, mockAlbum).Return(&mockPatchedAlbum, nil)
+	// Set mock expectations: the service applies the patch to the current album
+	mockAlbumService.On("PatchAlbum", mock.Anything, 1, mock.Anything).Return(
+		func(ctx context.Context, id int, apply func(data.Album) (data.Album, error)) (*data.Album, error) {
+			patched, err := apply(mockCurrentAlbum)
+			return &patched, err
+		})
 
-	// Create handler and request with only title in the body
-	handler := &AlbumHandler{postService: mockAlbumService}
-	reqBody, err := json.Marshal(mockAlbum)
-	require.NoError(t, err)
+	// Create handler and request with a merge patch of the title
+	handler := NewAlbumHandler(mockAlbumService)
+	reqBody := []byte(`{"title": "Updated Title"}`)
 	req, _ := http.NewRequest(http.MethodPatch, "/albums/1", bytes.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
 	ctx := chi.NewRouteContext()
-	ctx.URLParams.Add("postID", "1")
+	ctx.URLParams.Add("albumID", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
 
 	// Mock response recorder
@@ -159,13 +166,13 @@ func TestDeleteAlbum_Success(t *testing.T) {
 	defer mockAlbumService.AssertExpectations(t)
 
 	// Set mock expectations
-	mockAlbumService.On("DeleteAlbum", 1).Return(nil)
+	mockAlbumService.On("DeleteAlbum", mock.Anything, 1).Return(nil)
 
 	// Create handler and request
-	handler := &AlbumHandler{postService: mockAlbumService}
+	handler := NewAlbumHandler(mockAlbumService)
 	req, _ := http.NewRequest(http.MethodDelete, "/albums/1", nil)
 	ctx := chi.NewRouteContext()
-	ctx.URLParams.Add("postID", "1")
+	ctx.URLParams.Add("albumID", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
 
 	// Mock response recorder
@@ -0,0 +1,65 @@
+package albums
+
+import (
+	albums "blog-api/app/v1/albums/service"
+	resource "blog-api/app/v1/resource/handler"
+	"blog-api/data"
+	"net/http"
+)
+
+// UserAlbumsHandler maneja los albums de un user: /users/{userID}/albums
+type UserAlbumsHandler struct {
+	*resource.NestedHandler[data.Album]
+}
+
+// NewUserAlbumsHandler crea el manejador; user comprueba que exista el user
+func NewUserAlbumsHandler(albumService albums.IAlbumService, user resource.Parent) *UserAlbumsHandler {
+	handler := resource.NewResourceHandler(resource.Operations[data.Album]{
+		List:   albumService.GetAlbums,
+		Create: albumService.CreateAlbum,
+	}, "albumID", albums.Filters...)
+	return &UserAlbumsHandler{
+		NestedHandler: resource.NewNestedHandler(handler, user, "userID", "userId", func(item *data.Album, id int) {
+			item.UserID = id
+		}),
+	}
+}
+
+// Mount devuelve la opción de MountResource que registra las rutas bajo /users/{userID}
+func (h *UserAlbumsHandler) Mount() resource.MountOption {
+	return resource.MountNested("/albums", h.GetUserAlbums, h.CreateUserAlbum)
+}
+
+// GetUserAlbums godoc
+// @Description  Handler to get the albums of a user
+// @Tags Albums
+// @Accept		 json
+// @Produce      json
+// @Param        userID path  int    true  "User ID"
+// @Param        page   query int    false "Page number"
+// @Param        limit  query int    false "Page size"
+// @Param        cursor query string false "Opaque cursor from X-Next-Cursor"
+// @Success      200
+// @Failure      400
+// @Failure      404
+// @Failure      500
+// @Router       ///v1/users/{userID}/albums [get] .
+func (h *UserAlbumsHandler) GetUserAlbums(w http.ResponseWriter, r *http.Request) {
+	h.List(w, r)
+}
+
+// CreateUserAlbum godoc
+// @Description  Handler to create a album of a user; userId is taken from the path
+// @Tags Albums
+// @Accept		 json
+// @Produce      json
+// @Param        userID path  int    true  "User ID"
+// @Success      201
+// @Failure      400
+// @Failure      422
+// @Failure      404
+// @Failure      500
+// @Router       ///v1/users/{userID}/albums [post] .
+func (h *UserAlbumsHandler) CreateUserAlbum(w http.ResponseWriter, r *http.Request) {
+	h.Create(w, r)
+}
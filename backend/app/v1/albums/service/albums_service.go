@@ -2,171 +2,78 @@ package albums
 
 import (
 	"blog-api/app/clients/restclient"
+	"blog-api/app/config"
+	"blog-api/app/pagination"
+	"blog-api/app/repository"
+	resource "blog-api/app/v1/resource/service"
 	data "blog-api/data"
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"github.com/go-playground/validator/v10"
-	"net/http"
-	"net/url"
-	"os"
-	"strconv"
+	"context"
 )
 
-var baseUrl = os.Getenv("baseURL")
+// Filters son los query params que acepta GetAlbums
+var Filters = []string{"id", "userId", "title"}
 
-// AlbumAlbumService define un servicio para obtener albums
+// IAlbumService define un servicio para obtener albums
 type IAlbumService interface {
-	GetAlbums(title string, userID int, id int) (*[]data.Album, error)
-	GetAlbum(id int) (*data.Album, error)
-	CreateAlbum(album data.Album) (*data.Album, error)
-	UpdateAlbum(id int, album data.Album) (*data.Album, error)
-	DeleteAlbum(id int) error
+	GetAlbums(ctx context.Context, filter resource.Filter, page pagination.Pagination) (*[]data.Album, int, error)
+	GetAlbum(ctx context.Context, id int) (*data.Album, error)
+	CreateAlbum(ctx context.Context, album data.Album) (*data.Album, error)
+	UpdateAlbum(ctx context.Context, id int, album data.Album) (*data.Album, error)
+	PatchAlbum(ctx context.Context, id int, apply func(current data.Album) (data.Album, error)) (*data.Album, error)
+	DeleteAlbum(ctx context.Context, id int) error
 }
 
-// AlbumService implementa el servicio utilizando JSONPlaceholder
+// AlbumService implementa el servicio utilizando JSONPlaceholder, el almacenamiento local o ambos
 type AlbumService struct {
-	restClient restclient.IRestClient
+	resource resource.IResourceService[data.Album]
 }
 
 // GetAlbums obtiene albums desde JSONPlaceholder
-func (s *AlbumService) GetAlbums(title string, userID int, id int) (*[]data.Album, error) {
-	u, err := url.Parse(baseUrl)
-	if err != nil {
-		return nil, err
-	}
-	q := u.Query()
-	if title != "" {
-		q.Set("title", title)
-	}
-	if userID != 0 {
-		q.Set("userId", strconv.Itoa(userID))
-	}
-	if id != 0 {
-		q.Set("id", strconv.Itoa(id))
-	}
-	u.RawQuery = q.Encode()
-	url := u.String()
-	payload := bytes.NewBuffer(nil)
-	method := "GET"
-	resp, err := s.restClient.NewRequest(method, url, payload, nil)
-	var albums []data.Album
-	err = json.NewDecoder(resp.Body).Decode(&albums)
-	if err != nil {
-		return nil, err
-	}
-	return &albums, nil
+func (s *AlbumService) GetAlbums(ctx context.Context, filter resource.Filter, page pagination.Pagination) (*[]data.Album, int, error) {
+	return s.resource.List(ctx, filter, page)
 }
 
-func (s *AlbumService) GetAlbum(id int) (*data.Album, error) {
-	url := fmt.Sprintf("%s/%d", baseUrl, id)
-	method := "GET"
-	resp, err := s.restClient.NewRequest(method, url, bytes.NewBuffer(nil), nil)
-	if err != nil {
-		return nil, err
-	}
-	var album data.Album
-	err = json.NewDecoder(resp.Body).Decode(&album)
-	if err != nil {
-		return nil, err
-	}
-	return &album, nil
+func (s *AlbumService) GetAlbum(ctx context.Context, id int) (*data.Album, error) {
+	return s.resource.Get(ctx, id)
 }
 
-func (s *AlbumService) CreateAlbum(album data.Album) (*data.Album, error) {
-	validate := validator.New()
-	if err := validate.Struct(album); err != nil {
-		return nil, fmt.Errorf("Album can´t be created. Status Code: %s", err)
-	}
+func (s *AlbumService) CreateAlbum(ctx context.Context, album data.Album) (*data.Album, error) {
+	return s.resource.Create(ctx, album)
+}
 
-	url := baseUrl
-	method := "POST"
-	body, err := json.Marshal(album)
-	if err != nil {
-		return nil, err
-	}
-	payload := bytes.NewBuffer(body)
-	headers := map[string]string{"Content-Type": " application/json"}
-	resp, err := s.restClient.NewRequest(method, url, payload, headers)
-	if err != nil {
-		return nil, fmt.Errorf("Album can´t be created. Status Code: %d", resp.StatusCode)
-	}
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("Album can´t be created. Status Code: %d", resp.StatusCode)
-	}
-	var createdAlbum data.Album
-	err = json.NewDecoder(resp.Body).Decode(&createdAlbum)
-	if err != nil {
-		return nil, err
-	}
-	return &createdAlbum, nil
+func (s *AlbumService) UpdateAlbum(ctx context.Context, id int, album data.Album) (*data.Album, error) {
+	return s.resource.Update(ctx, id, album)
 }
 
-func (s *AlbumService) UpdateAlbum(id int, album data.Album) (*data.Album, error) {
-	url := fmt.Sprintf("%s/%d", baseUrl, id)
-	method := "PUT"
-	body, err := json.Marshal(album)
-	if err != nil {
-		return nil, err
-	}
-	payload := bytes.NewBuffer(body)
-	headers := map[string]string{"Content-Type": " application/json"}
-	resp, err := s.restClient.NewRequest(method, url, payload, headers)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Album can´t be updated. Status Code: %d", resp.StatusCode)
-	}
-	var updatedAlbum data.Album
-	err = json.NewDecoder(resp.Body).Decode(&updatedAlbum)
-	if err != nil {
-		return nil, err
-	}
-	return &updatedAlbum, nil
+func (s *AlbumService) PatchAlbum(ctx context.Context, id int, apply func(current data.Album) (data.Album, error)) (*data.Album, error) {
+	return s.resource.Patch(ctx, id, apply)
 }
 
-func (s *AlbumService) PatchAlbum(id int, album data.Album) (*data.Album, error) {
-	url := fmt.Sprintf("%s/%d", baseUrl, id)
-	method := "PATCH"
-	body, err := json.Marshal(album)
-	if err != nil {
-		return nil, err
-	}
-	headers := map[string]string{"Content-Type": " application/json"}
-	payload := bytes.NewBuffer(body)
-	resp, err := s.restClient.NewRequest(method, url, payload, headers)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Album can't be updated. Status Code: %d", resp.StatusCode)
-	}
-	var updatedAlbum data.Album
-	err = json.NewDecoder(resp.Body).Decode(&updatedAlbum)
-	if err != nil {
-		return nil, err
-	}
-	return &updatedAlbum, nil
+func (s *AlbumService) DeleteAlbum(ctx context.Context, id int) error {
+	return s.resource.Delete(ctx, id)
 }
 
-func (s *AlbumService) DeleteAlbum(id int) error {
-	url := fmt.Sprintf("%s/%d", baseUrl, id)
-	method := "DELETE"
-	payload := bytes.NewBuffer(nil)
-	resp, err := s.restClient.NewRequest(method, url, payload, nil)
-	if err != nil {
-		return err
+// NewAlbumService crea una nueva instancia del servicio de albums contra la colección upstream dada
+func NewAlbumService(client restclient.IRestClient, upstream config.Upstream, opts ...resource.Option) IAlbumService {
+	return &AlbumService{
+		resource: resource.NewResourceService[data.Album](client, "Album", upstream.URL(), opts...),
 	}
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("Album cannnt be created. Status Code: %d", resp.StatusCode)
+}
+
+// NewAlbumLocalService crea el servicio de albums sobre el almacenamiento local
+func NewAlbumLocalService(repo repository.AlbumRepository) IAlbumService {
+	return &AlbumService{
+		resource: resource.NewRepositoryService[data.Album](repo, "Album"),
 	}
-	return nil
 }
 
-// NewAlbumAlbumService crea una nueva instancia del servicio de albums
-func NewAlbumService(client restclient.IRestClient) IAlbumService {
+// NewAlbumOverlayService crea el servicio de albums que lee del upstream y guarda las escrituras localmente
+func NewAlbumOverlayService(client restclient.IRestClient, upstream config.Upstream, store *repository.OverlayStore, opts ...resource.Option) IAlbumService {
 	return &AlbumService{
-		restClient: client,
+		resource: resource.NewOverlayService[data.Album](
+			resource.NewResourceService[data.Album](client, "Album", upstream.URL(), opts...),
+			repository.NewOverlay[data.Album](store, "albums"),
+			"Album",
+		),
 	}
 }
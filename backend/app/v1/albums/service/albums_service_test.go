@@ -1,9 +1,13 @@
 package albums
 
 import (
+	"blog-api/app/config"
 	"blog-api/app/mocks"
+	"blog-api/app/pagination"
+	resource "blog-api/app/v1/resource/service"
 	data "blog-api/data"
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -16,6 +20,10 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+var upstream = config.Upstream{Root: "https://jsonplaceholder.typicode.com", Path: "albums"}
+
+var baseUrl = upstream.URL()
+
 type MockReader struct {
 	*bytes.Reader
 }
@@ -31,10 +39,10 @@ func TestAlbumService_GetAlbums_Success(t *testing.T) {
 	}
 	//mockResp.Body.Read([]byte(`[{"id": 1, "title": "Test Title", "userId": 10}]`))
 	mockClient := mocks.NewIRestClient(t)
-	mockClient.On("NewRequest", "GET", "https://jsonplaceholder.typicode.com/albums?id=1&title=Test+Title&userId=10", mock.Anything, mock.Anything).Return(mockResp, nil)
+	mockClient.On("NewRequest", mock.Anything, "GET", "https://jsonplaceholder.typicode.com/albums?id=1&title=Test+Title&userId=10", mock.Anything, mock.Anything).Return(mockResp, nil)
 	//
-	service := &AlbumService{restClient: mockClient}
-	albums, err := service.GetAlbums("Test Title", 10, 1)
+	service := NewAlbumService(mockClient, upstream)
+	albums, _, err := service.GetAlbums(context.Background(), resource.Filter{"title": "Test Title", "userId": "10", "id": "1"}, pagination.Pagination{})
 	require.NoError(t, err)
 	require.NotNil(t, albums)
 	require.Equal(t, 1, len(*albums))
@@ -42,10 +50,10 @@ func TestAlbumService_GetAlbums_Success(t *testing.T) {
 
 func TestAlbumService_GetAlbums_ErrorInRequest(t *testing.T) {
 	mockClient := mocks.NewIRestClient(t)
-	mockClient.On("NewRequest", "GET", "https://jsonplaceholder.typicode.com/albums?id=1&title=Test+Title&userId=10", mock.Anything, mock.Anything).Return(nil, errors.New("request error"))
+	mockClient.On("NewRequest", mock.Anything, "GET", "https://jsonplaceholder.typicode.com/albums?id=1&title=Test+Title&userId=10", mock.Anything, mock.Anything).Return(nil, errors.New("request error"))
 
-	service := &AlbumService{restClient: mockClient}
-	_, err := service.GetAlbums("Test Title", 10, 1)
+	service := NewAlbumService(mockClient, upstream)
+	_, _, err := service.GetAlbums(context.Background(), resource.Filter{"title": "Test Title", "userId": "10", "id": "1"}, pagination.Pagination{})
 	require.Error(t, err)
 }
 
@@ -62,10 +70,10 @@ func TestAlbumService_GetAlbum_Success(t *testing.T) {
 		Body:       io.NopCloser(bytes.NewReader([]byte(`{"id": 1, "title": "Test Title", "userId": 10}`))),
 	}
 	mockClient := mocks.NewIRestClient(t)
-	mockClient.On("NewRequest", "GET", fmt.Sprintf("%s/%d", baseUrl, mockAlbum.ID), mock.Anything, mock.Anything).Return(mockResp, nil)
+	mockClient.On("NewRequest", mock.Anything, "GET", fmt.Sprintf("%s/%d", baseUrl, mockAlbum.ID), mock.Anything, mock.Anything).Return(mockResp, nil)
 	// Create service and call GetAlbum
-	service := &AlbumService{restClient: mockClient}
-	album, err := service.GetAlbum(mockAlbum.ID)
+	service := NewAlbumService(mockClient, upstream)
+	album, err := service.GetAlbum(context.Background(), mockAlbum.ID)
 	require.NoError(t, err)
 	require.NotNil(t, album)
 	require.Equal(t, *mockAlbum, *album)
@@ -73,19 +81,19 @@ func TestAlbumService_GetAlbum_Success(t *testing.T) {
 
 func TestCreateAlbum(t *testing.T) {
 	mockClient := mocks.NewIRestClient(t)
-	service := &AlbumService{restClient: mockClient}
+	service := NewAlbumService(mockClient, upstream)
 	album := data.Album{
 		ID:     1,
 		Title:  "Test Title",
 		UserID: 10,
 	}
 	t.Run("success", func(t *testing.T) {
-		mockClient.On("NewRequest", "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: http.StatusCreated,
 			Body:       io.NopCloser(bytes.NewReader([]byte(`{"id": 1, "title": "Test Title", "userId": 10}`))),
-		}, nil)
+		}, nil).Once()
 
-		createdAlbum, err := service.CreateAlbum(album)
+		createdAlbum, err := service.CreateAlbum(context.Background(), album)
 		assert.NoError(t, err)
 		assert.Equal(t, album, *createdAlbum)
 	})
@@ -95,37 +103,37 @@ func TestCreateAlbum(t *testing.T) {
 			Title:  "", // Title is required
 			UserID: 10,
 		}
-		_, err := service.CreateAlbum(invalidAlbum)
+		_, err := service.CreateAlbum(context.Background(), invalidAlbum)
 		assert.Error(t, err)
 	})
 	t.Run("error in request", func(t *testing.T) {
-		mockClient.On("NewRequest", "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(nil, errors.New("request error"))
+		mockClient.On("NewRequest", mock.Anything, "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(nil, errors.New("request error")).Once()
 
-		_, err := service.CreateAlbum(album)
+		_, err := service.CreateAlbum(context.Background(), album)
 		assert.Error(t, err)
 	})
 	t.Run("error in response", func(t *testing.T) {
-		mockClient.On("NewRequest", "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: 500,
 			Body:       ioutil.NopCloser(bytes.NewReader([]byte(``))),
-		}, nil)
+		}, nil).Once()
 
-		_, err := service.CreateAlbum(album)
+		_, err := service.CreateAlbum(context.Background(), album)
 		assert.Error(t, err)
 	})
 	t.Run("error in decoding", func(t *testing.T) {
-		mockClient.On("NewRequest", "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: http.StatusCreated,
 			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`not a valid json`))),
-		}, nil)
-		_, err := service.CreateAlbum(album)
+		}, nil).Once()
+		_, err := service.CreateAlbum(context.Background(), album)
 		assert.Error(t, err)
 	})
 }
 
 func TestUpdateAlbum(t *testing.T) {
 	mockClient := mocks.NewIRestClient(t)
-	service := &AlbumService{restClient: mockClient}
+	service := NewAlbumService(mockClient, upstream)
 
 	album := data.Album{
 		ID:     1,
@@ -134,53 +142,53 @@ func TestUpdateAlbum(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mockClient.On("NewRequest", "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: http.StatusOK,
 			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"id": 1, "title": "Updated Title", "userId": 10}`))),
-		}, nil)
+		}, nil).Once()
 
-		updatedAlbum, err := service.UpdateAlbum(album.ID, album)
+		updatedAlbum, err := service.UpdateAlbum(context.Background(), album.ID, album)
 		assert.NoError(t, err)
 		assert.Equal(t, album, *updatedAlbum)
 	})
 
 	t.Run("error in request", func(t *testing.T) {
-		mockClient.On("NewRequest", "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(nil, errors.New("request error"))
+		mockClient.On("NewRequest", mock.Anything, "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(nil, errors.New("request error")).Once()
 
-		_, err := service.UpdateAlbum(album.ID, album)
+		_, err := service.UpdateAlbum(context.Background(), album.ID, album)
 		assert.Error(t, err)
 	})
 
 	t.Run("error in response", func(t *testing.T) {
-		mockClient.On("NewRequest", "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: 500,
 			Body:       ioutil.NopCloser(bytes.NewReader([]byte(``))),
-		}, nil)
+		}, nil).Once()
 
-		_, err := service.UpdateAlbum(album.ID, album)
+		_, err := service.UpdateAlbum(context.Background(), album.ID, album)
 		assert.Error(t, err)
 	})
 
 	t.Run("error in decoding", func(t *testing.T) {
-		mockClient.On("NewRequest", "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: http.StatusOK,
 			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`not a valid json`))),
-		}, nil)
+		}, nil).Once()
 
-		_, err := service.UpdateAlbum(album.ID, album)
+		_, err := service.UpdateAlbum(context.Background(), album.ID, album)
 		assert.Error(t, err)
 	})
 }
 
 func TestDeleteAlbum(t *testing.T) {
 	mockClient := mocks.NewIRestClient(t)
-	service := &AlbumService{restClient: mockClient}
+	service := NewAlbumService(mockClient, upstream)
 	albumID := 1
 	t.Run("success", func(t *testing.T) {
-		mockClient.On("NewRequest", "DELETE", fmt.Sprintf("%s/%d", baseUrl, albumID), mock.Anything, mock.Anything).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "DELETE", fmt.Sprintf("%s/%d", baseUrl, albumID), mock.Anything, mock.Anything).Return(&http.Response{
 			StatusCode: http.StatusOK,
-		}, nil)
-		err := service.DeleteAlbum(albumID)
+		}, nil).Once()
+		err := service.DeleteAlbum(context.Background(), albumID)
 		assert.NoError(t, err)
 	})
 }
@@ -0,0 +1,413 @@
+package batch
+
+import (
+	apperrors "blog-api/app/errors"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Valores por defecto de Options
+const (
+	DefaultMaxRequests = 20
+	DefaultMaxBody     = 1 << 20
+	DefaultConcurrency = 4
+)
+
+// Options configura BatchHandler. Los valores en cero usan los valores por defecto.
+type Options struct {
+	// MaxRequests es la cantidad máxima de subsolicitudes de un batch
+	MaxRequests int
+	// MaxBody es el tamaño máximo del cuerpo del batch en bytes
+	MaxBody int64
+	// Concurrency es la cantidad de subsolicitudes que se atienden a la vez con parallel
+	Concurrency int
+}
+
+func (o Options) withDefaults() Options {
+	if o.MaxRequests <= 0 {
+		o.MaxRequests = DefaultMaxRequests
+	}
+	if o.MaxBody <= 0 {
+		o.MaxBody = DefaultMaxBody
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = DefaultConcurrency
+	}
+	return o
+}
+
+// Request es una subsolicitud. Path y Body pueden tener referencias {{id.body.campo}} a la
+// respuesta de otra subsolicitud, que pasa a ser una dependencia como las de DependsOn.
+type Request struct {
+	ID        string            `json:"id,omitempty"`
+	Method    string            `json:"method,omitempty"`
+	Path      string            `json:"path"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Body      json.RawMessage   `json:"body,omitempty"`
+	DependsOn []string          `json:"dependsOn,omitempty"`
+}
+
+// Batch es el cuerpo de POST /v1/batch. Sin Parallel las subsolicitudes se atienden de a
+// una, en orden; con Parallel cada una empieza cuando terminaron sus dependencias.
+type Batch struct {
+	Parallel bool      `json:"parallel"`
+	Requests []Request `json:"requests"`
+}
+
+// Response es la respuesta de una subsolicitud. Body es el JSON de la respuesta, o un
+// string si la respuesta no es JSON.
+type Response struct {
+	ID      string            `json:"id,omitempty"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+// Result es el cuerpo de la respuesta de POST /v1/batch: una respuesta por subsolicitud,
+// en el orden del batch
+type Result struct {
+	Responses []Response `json:"responses"`
+}
+
+// methods son los métodos que admite una subsolicitud
+var methods = map[string]bool{
+	http.MethodGet:    true,
+	http.MethodHead:   true,
+	http.MethodPost:   true,
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+}
+
+// forwarded son los headers de la solicitud del batch que reciben las subsolicitudes
+var forwarded = []string{"Accept", "Accept-Language", "Authorization"}
+
+// BatchHandler atiende POST /v1/batch despachando cada subsolicitud por router dentro del
+// proceso, con los mismos middlewares que una solicitud HTTP
+type BatchHandler struct {
+	router http.Handler
+	opts   Options
+}
+
+// NewBatchHandler crea el manejador; router es el router completo de la API
+func NewBatchHandler(router http.Handler, opts Options) *BatchHandler {
+	return &BatchHandler{router: router, opts: opts.withDefaults()}
+}
+
+// Batch godoc
+// @Description  Handler to run several API requests in one round trip, e.g. the ones of a dashboard
+// @Tags Batch
+// @Accept       json
+// @Produce      json
+// @Param        batch body Batch true "Sub-requests"
+// @Success      200 {object} Result
+// @Failure      400
+// @Failure      413
+// @Router       ///v1/batch [post] .
+func (h *BatchHandler) Batch(w http.ResponseWriter, r *http.Request) {
+	if r.Context().Value(subrequestKey{}) != nil {
+		apperrors.Write(w, r, apperrors.New(apperrors.ErrBadRequest, "batch requests cannot be nested"), nil)
+		return
+	}
+	var batch Batch
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.opts.MaxBody)).Decode(&batch); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			apperrors.Write(w, r, apperrors.Newf(apperrors.ErrPayloadTooLarge, "request body is larger than %d bytes", h.opts.MaxBody), nil)
+			return
+		}
+		apperrors.Write(w, r, apperrors.New(apperrors.ErrBadRequest, "request body must be a valid JSON object"), nil)
+		return
+	}
+	plan, err := h.plan(r, batch)
+	if err != nil {
+		apperrors.Write(w, r, err, apperrors.ErrBadRequest)
+		return
+	}
+	responses := make([]Response, len(batch.Requests))
+	if batch.Parallel {
+		h.parallel(r, plan, responses)
+	} else {
+		for _, i := range plan.order {
+			responses[i] = h.run(r, plan, i, responses)
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(Result{Responses: responses})
+}
+
+// plan son las subsolicitudes validadas con sus dependencias
+type plan struct {
+	requests []Request
+	// deps son los índices de las dependencias de cada subsolicitud
+	deps [][]int
+	// order es un orden en que cada subsolicitud va después de sus dependencias
+	order []int
+}
+
+// plan valida el batch y resuelve sus dependencias
+func (h *BatchHandler) plan(r *http.Request, batch Batch) (*plan, error) {
+	if len(batch.Requests) == 0 {
+		return nil, apperrors.New(apperrors.ErrBadRequest, "a batch needs at least one request")
+	}
+	if len(batch.Requests) > h.opts.MaxRequests {
+		return nil, apperrors.Newf(apperrors.ErrPayloadTooLarge, "a batch can have at most %d requests", h.opts.MaxRequests)
+	}
+	ids := map[string]int{}
+	for i, request := range batch.Requests {
+		if request.ID == "" {
+			continue
+		}
+		if _, used := ids[request.ID]; used {
+			return nil, apperrors.Newf(apperrors.ErrBadRequest, "request %d: id %q is already used", i, request.ID)
+		}
+		ids[request.ID] = i
+	}
+	p := &plan{requests: batch.Requests, deps: make([][]int, len(batch.Requests))}
+	for i := range p.requests {
+		request := &p.requests[i]
+		request.Method = strings.ToUpper(request.Method)
+		if request.Method == "" {
+			request.Method = http.MethodGet
+		}
+		if !methods[request.Method] {
+			return nil, apperrors.Newf(apperrors.ErrBadRequest, "request %d: method %q is not supported", i, request.Method)
+		}
+		target, err := url.Parse(request.Path)
+		if err != nil || !strings.HasPrefix(request.Path, "/") || target.Host != "" {
+			return nil, apperrors.Newf(apperrors.ErrBadRequest, "request %d: path must start with /", i)
+		}
+		if nested(r, target) {
+			return nil, apperrors.Newf(apperrors.ErrBadRequest, "request %d: batch requests cannot be nested", i)
+		}
+		names := request.DependsOn
+		for _, ref := range references(request.Path, string(request.Body)) {
+			if len(ref.path) == 0 || ref.path[0] != "body" {
+				return nil, apperrors.Newf(apperrors.ErrBadRequest, "request %d: invalid reference %s, use {{id.body.field}}", i, ref.text)
+			}
+			names = append(names, ref.request)
+		}
+		seen := map[int]bool{}
+		for _, name := range names {
+			dep, ok := ids[name]
+			if !ok {
+				return nil, apperrors.Newf(apperrors.ErrBadRequest, "request %d: unknown request %q", i, name)
+			}
+			if !seen[dep] {
+				seen[dep] = true
+				p.deps[i] = append(p.deps[i], dep)
+			}
+		}
+	}
+	if err := p.sort(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// sort ordena las subsolicitudes después de sus dependencias y, entre las que no dependen
+// entre sí, en el orden del batch. Falla si hay dependencias circulares.
+func (p *plan) sort() error {
+	done := make([]bool, len(p.requests))
+	for len(p.order) < len(p.requests) {
+		next := -1
+		for i := range p.requests {
+			if !done[i] && p.ready(i, done) {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			return apperrors.New(apperrors.ErrBadRequest, "the requests have circular dependencies")
+		}
+		done[next] = true
+		p.order = append(p.order, next)
+	}
+	return nil
+}
+
+func (p *plan) ready(i int, done []bool) bool {
+	for _, dep := range p.deps[i] {
+		if !done[dep] {
+			return false
+		}
+	}
+	return true
+}
+
+// parallel atiende cada subsolicitud cuando terminaron sus dependencias, hasta
+// Concurrency a la vez
+func (h *BatchHandler) parallel(r *http.Request, p *plan, responses []Response) {
+	finished := make([]chan struct{}, len(p.requests))
+	for i := range finished {
+		finished[i] = make(chan struct{})
+	}
+	slots := make(chan struct{}, h.opts.Concurrency)
+	var wg sync.WaitGroup
+	for _, i := range p.order {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer close(finished[i])
+			for _, dep := range p.deps[i] {
+				<-finished[dep]
+			}
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			responses[i] = h.run(r, p, i, responses)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// run atiende la subsolicitud i si sus dependencias respondieron bien; responses tiene
+// las respuestas de sus dependencias
+func (h *BatchHandler) run(r *http.Request, p *plan, i int, responses []Response) Response {
+	request := p.requests[i]
+	for _, dep := range p.deps[i] {
+		if responses[dep].Status >= http.StatusBadRequest {
+			name := p.requests[dep].ID
+			return h.fail(r, request, apperrors.Newf(apperrors.ErrFailedDependency, "not run because request %q failed", name))
+		}
+	}
+	ids := map[string]int{}
+	for _, dep := range p.deps[i] {
+		ids[p.requests[dep].ID] = dep
+	}
+	resolve := func(ref reference) (any, error) {
+		return ref.resolve(responses[ids[ref.request]].Body)
+	}
+	target, err := replacePath(request.Path, resolve)
+	if err != nil {
+		return h.fail(r, request, err)
+	}
+	// Las referencias pueden armar la ruta del batch, que plan no llega a ver
+	if resolved, err := url.Parse(target); err == nil && nested(r, resolved) {
+		return h.fail(r, request, apperrors.New(apperrors.ErrBadRequest, "batch requests cannot be nested"))
+	}
+	body, err := replaceBody(request.Body, resolve)
+	if err != nil {
+		return h.fail(r, request, err)
+	}
+	response := h.dispatch(r, i, request.Method, target, request.Headers, body)
+	response.ID = request.ID
+	return response
+}
+
+// dispatch atiende una subsolicitud con el router. Su contexto se cancela con el del
+// batch pero no hereda sus valores, así el router la procesa como una solicitud nueva.
+func (h *BatchHandler) dispatch(r *http.Request, i int, method string, target string, headers map[string]string, body []byte) Response {
+	ctx := context.WithValue(withoutValues{r.Context()}, subrequestKey{}, true)
+	sub, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return h.fail(r, Request{Path: target}, apperrors.New(apperrors.ErrBadRequest, "path must start with /"))
+	}
+	sub.RequestURI = target
+	sub.RemoteAddr = r.RemoteAddr
+	sub.Host = r.Host
+	for _, name := range forwarded {
+		if value := r.Header.Get(name); value != "" {
+			sub.Header.Set(name, value)
+		}
+	}
+	if len(body) > 0 {
+		sub.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range headers {
+		sub.Header.Set(name, value)
+	}
+	if id := middleware.GetReqID(r.Context()); id != "" {
+		sub.Header.Set(middleware.RequestIDHeader, id+"."+strconv.Itoa(i))
+	}
+	recorder := &recorder{header: http.Header{}}
+	h.router.ServeHTTP(recorder, sub)
+	return recorder.response()
+}
+
+// fail responde la subsolicitud con el problem de err
+func (h *BatchHandler) fail(r *http.Request, request Request, err error) Response {
+	problem := apperrors.NewProblem(r, err, apperrors.ErrBadRequest)
+	problem.Instance = request.Path
+	body, _ := json.Marshal(problem)
+	return Response{
+		ID:      request.ID,
+		Status:  problem.Status,
+		Headers: map[string]string{"Content-Type": apperrors.ContentType},
+		Body:    body,
+	}
+}
+
+// nested indica si target es la ruta del batch r
+func nested(r *http.Request, target *url.URL) bool {
+	return path.Clean(target.Path) == path.Clean(r.URL.Path)
+}
+
+// subrequestKey marca el contexto de las subsolicitudes, así un batch no puede llegar a
+// otro por una ruta que no se reconozca antes de despacharla
+type subrequestKey struct{}
+
+// withoutValues es un contexto que se cancela con el original pero no tiene sus valores,
+// salvo la marca de subsolicitud
+type withoutValues struct {
+	context.Context
+}
+
+func (c withoutValues) Value(key any) any {
+	if key == (subrequestKey{}) {
+		return c.Context.Value(key)
+	}
+	return nil
+}
+
+// recorder guarda la respuesta de una subsolicitud
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *recorder) Header() http.Header {
+	return w.header
+}
+
+func (w *recorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *recorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
+
+func (w *recorder) response() Response {
+	response := Response{Status: w.status, Headers: map[string]string{}}
+	if response.Status == 0 {
+		response.Status = http.StatusOK
+	}
+	for name, values := range w.header {
+		response.Headers[name] = strings.Join(values, ", ")
+	}
+	body := bytes.TrimSpace(w.body.Bytes())
+	switch {
+	case len(body) == 0:
+	case json.Valid(body):
+		response.Body = body
+	default:
+		response.Body, _ = json.Marshal(string(body))
+	}
+	return response
+}
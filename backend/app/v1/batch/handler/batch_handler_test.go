@@ -0,0 +1,206 @@
+package batch
+
+import (
+	apperrors "blog-api/app/errors"
+	"blog-api/app/i18n"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// newBatchRouter arma un router de prueba con POST /v1/batch sobre el mismo router
+func newBatchRouter(opts Options, routes func(r chi.Router)) *chi.Mux {
+	r := chi.NewRouter()
+	r.Use(i18n.Middleware)
+	routes(r)
+	r.Post("/v1/batch", NewBatchHandler(r, opts).Batch)
+	return r
+}
+
+func users(r chi.Router) {
+	r.Get("/v1/users/{userID}", func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.Atoi(chi.URLParam(r, "userID"))
+		if id != 1 {
+			apperrors.Write(w, r, apperrors.New(apperrors.ErrNotFound, "User not found"), nil)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 1, "name": "Leanne", "lang": "` + r.Header.Get("Accept-Language") + `"}`))
+	})
+	r.Get("/v1/todos", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Total-Count", "1")
+		w.Write([]byte(`[{"id": 7, "userId": ` + r.URL.Query().Get("userId") + `}]`))
+	})
+	r.Post("/v1/posts", func(w http.ResponseWriter, r *http.Request) {
+		var post map[string]any
+		json.NewDecoder(r.Body).Decode(&post)
+		post["id"] = 101
+		post["contentType"] = r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(post)
+	})
+	r.Get("/ping", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("."))
+	})
+}
+
+func serveBatch(r http.Handler, body string) (*httptest.ResponseRecorder, Result) {
+	req := httptest.NewRequest(http.MethodPost, "/v1/batch", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "es")
+	mockRecorder := httptest.NewRecorder()
+	r.ServeHTTP(mockRecorder, req)
+	var result Result
+	json.Unmarshal(mockRecorder.Body.Bytes(), &result)
+	return mockRecorder, result
+}
+
+func statuses(result Result) []int {
+	codes := make([]int, len(result.Responses))
+	for i, response := range result.Responses {
+		codes[i] = response.Status
+	}
+	return codes
+}
+
+func TestBatchHandler_Batch(t *testing.T) {
+	body := `{"requests": [
+		{"id": "todos", "path": "/v1/todos?userId={{user.body.id}}"},
+		{"id": "user", "method": "get", "path": "/v1/users/1"},
+		{"method": "POST", "path": "/v1/posts", "body": {"userId": "{{user.body.id}}", "title": "Hi {{user.body.name}}", "todo": "{{todos.body.0.id}}"}},
+		{"id": "missing", "path": "/v1/users/2"},
+		{"path": "/v1/users/{{missing.body.id}}"},
+		{"path": "/v1/posts", "method": "POST", "body": {"userId": "{{user.body.address.city}}"}},
+		{"path": "/ping"}
+	]}`
+	for _, parallel := range []bool{false, true} {
+		t.Run("parallel "+strconv.FormatBool(parallel), func(t *testing.T) {
+			r := newBatchRouter(Options{}, users)
+			if parallel {
+				body = strings.Replace(body, `{"requests"`, `{"parallel": true, "requests"`, 1)
+			}
+			mockRecorder, result := serveBatch(r, body)
+			require.Equal(t, http.StatusOK, mockRecorder.Code, mockRecorder.Body.String())
+			require.Equal(t, []int{200, 200, 201, 404, 424, 400, 200}, statuses(result))
+
+			todos := result.Responses[0]
+			require.Equal(t, "todos", todos.ID)
+			require.JSONEq(t, `[{"id": 7, "userId": 1}]`, string(todos.Body))
+			require.Equal(t, "1", todos.Headers["X-Total-Count"])
+			require.JSONEq(t, `{"id": 1, "name": "Leanne", "lang": "es"}`, string(result.Responses[1].Body), "the sub-requests get Accept-Language")
+			require.JSONEq(t, `{"id": 101, "userId": 1, "title": "Hi Leanne", "todo": 7, "contentType": "application/json"}`, string(result.Responses[2].Body))
+
+			var problem apperrors.Problem
+			require.NoError(t, json.Unmarshal(result.Responses[4].Body, &problem))
+			require.Equal(t, "failed_dependency", string(problem.Code))
+			require.Equal(t, `no se atendió porque falló la solicitud "missing"`, problem.Detail)
+			require.NoError(t, json.Unmarshal(result.Responses[5].Body, &problem))
+			require.Equal(t, "la referencia {{user.body.address.city}} no existe", problem.Detail)
+			require.Equal(t, `"."`, string(result.Responses[6].Body), "bodies that are not JSON are strings")
+		})
+	}
+}
+
+func TestBatchHandler_Batch_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"not an object", `[]`, http.StatusBadRequest},
+		{"empty", `{"requests": []}`, http.StatusBadRequest},
+		{"too many requests", `{"requests": [{"path": "/ping"}, {"path": "/ping"}, {"path": "/ping"}, {"path": "/ping"}]}`, http.StatusRequestEntityTooLarge},
+		{"body too large", `{"requests": [{"path": "/ping?` + strings.Repeat("x", 200) + `"}]}`, http.StatusRequestEntityTooLarge},
+		{"repeated id", `{"requests": [{"id": "a", "path": "/ping"}, {"id": "a", "path": "/ping"}]}`, http.StatusBadRequest},
+		{"unknown method", `{"requests": [{"method": "TRACE", "path": "/ping"}]}`, http.StatusBadRequest},
+		{"absolute url", `{"requests": [{"path": "http://example.com/ping"}]}`, http.StatusBadRequest},
+		{"nested batch", `{"requests": [{"method": "POST", "path": "/v1/batch"}]}`, http.StatusBadRequest},
+		{"unknown dependency", `{"requests": [{"path": "/ping", "dependsOn": ["a"]}]}`, http.StatusBadRequest},
+		{"invalid reference", `{"requests": [{"id": "a", "path": "/ping"}, {"path": "/v1/users/{{a.id}}"}]}`, http.StatusBadRequest},
+		{"circular dependencies", `{"requests": [{"id": "a", "path": "/ping", "dependsOn": ["b"]}, {"id": "b", "path": "/ping?{{a.body.x}}"}]}`, http.StatusBadRequest},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var served atomic.Int32
+			r := newBatchRouter(Options{MaxRequests: 3, MaxBody: 200}, func(r chi.Router) {
+				r.Get("/ping", func(w http.ResponseWriter, r *http.Request) {
+					served.Add(1)
+				})
+			})
+			mockRecorder, _ := serveBatch(r, tc.body)
+			require.Equal(t, tc.status, mockRecorder.Code, mockRecorder.Body.String())
+			require.Equal(t, apperrors.ContentType, mockRecorder.Header().Get("Content-Type"))
+			require.Zero(t, served.Load(), "no sub-request is served")
+		})
+	}
+}
+
+func TestBatchHandler_Batch_Parallel(t *testing.T) {
+	var running, peak atomic.Int32
+	var mu sync.Mutex
+	var finished []string
+	r := newBatchRouter(Options{Concurrency: 2}, func(r chi.Router) {
+		r.Get("/slow/{name}", func(w http.ResponseWriter, r *http.Request) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				current := peak.Load()
+				if n <= current || peak.CompareAndSwap(current, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			mu.Lock()
+			defer mu.Unlock()
+			finished = append(finished, chi.URLParam(r, "name"))
+		})
+	})
+
+	requests := []string{`{"id": "last", "path": "/slow/last", "dependsOn": ["first"]}`}
+	for i := 0; i < 6; i++ {
+		requests = append(requests, `{"path": "/slow/`+strconv.Itoa(i)+`"}`)
+	}
+	requests = append(requests, `{"id": "first", "path": "/slow/first"}`)
+	_, result := serveBatch(r, `{"parallel": true, "requests": [`+strings.Join(requests, ",")+`]}`)
+	require.Len(t, result.Responses, 8)
+	require.Equal(t, "last", result.Responses[0].ID, "responses keep the order of the requests")
+	require.LessOrEqual(t, peak.Load(), int32(2))
+	require.Greater(t, peak.Load(), int32(1))
+	require.Less(t, slices.Index(finished, "first"), slices.Index(finished, "last"), "a request waits for its dependencies")
+}
+
+func TestBatchHandler_Batch_Nested(t *testing.T) {
+	var served atomic.Int32
+	r := newBatchRouter(Options{}, func(r chi.Router) {
+		r.Get("/v1/pages/{name}", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"title": "batch"}`))
+		})
+		r.Get("/ping", func(w http.ResponseWriter, r *http.Request) {
+			served.Add(1)
+		})
+		// Otra ruta al mismo handler, que plan no reconoce como el batch
+		r.Post("/v1/batches", NewBatchHandler(r, Options{}).Batch)
+	})
+	_, result := serveBatch(r, `{"requests": [
+		{"id": "page", "path": "/v1/pages/1"},
+		{"method": "POST", "path": "/v1/{{page.body.title}}", "body": {"requests": [{"path": "/ping"}]}},
+		{"method": "POST", "path": "/v1/batches", "body": {"requests": [{"path": "/ping"}]}}
+	]}`)
+	require.Equal(t, []int{200, 400, 400}, statuses(result))
+	var problem apperrors.Problem
+	for _, response := range result.Responses[1:] {
+		require.NoError(t, json.Unmarshal(response.Body, &problem))
+		require.Equal(t, "los batch no se pueden anidar", problem.Detail)
+	}
+	require.Zero(t, served.Load(), "no nested sub-request is served")
+}
@@ -0,0 +1,156 @@
+package batch
+
+import (
+	apperrors "blog-api/app/errors"
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pattern encuentra las referencias {{id.body.campo}}; los campos de objetos anidados y
+// los índices de arrays se separan con puntos, p. ej. {{posts.body.0.id}}
+var pattern = regexp.MustCompile(`\{\{\s*([^{}\s]+)\s*\}\}`)
+
+// reference es una referencia a un valor de la respuesta de otra subsolicitud
+type reference struct {
+	text    string
+	request string
+	path    []string
+}
+
+// references devuelve las referencias de los textos
+func references(texts ...string) []reference {
+	var result []reference
+	for _, text := range texts {
+		for _, match := range pattern.FindAllStringSubmatch(text, -1) {
+			result = append(result, parseReference(match[0], match[1]))
+		}
+	}
+	return result
+}
+
+func parseReference(text string, expr string) reference {
+	parts := strings.Split(expr, ".")
+	return reference{text: text, request: parts[0], path: parts[1:]}
+}
+
+// resolve busca el valor en el cuerpo de la respuesta referida; path empieza con body
+func (ref reference) resolve(body json.RawMessage) (any, error) {
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, apperrors.Newf(apperrors.ErrBadRequest, "reference %s does not exist", ref.text)
+	}
+	for _, key := range ref.path[1:] {
+		var ok bool
+		switch current := value.(type) {
+		case map[string]any:
+			value, ok = current[key]
+		case []any:
+			var index int
+			index, ok = arrayIndex(key, len(current))
+			if ok {
+				value = current[index]
+			}
+		}
+		if !ok {
+			return nil, apperrors.Newf(apperrors.ErrBadRequest, "reference %s does not exist", ref.text)
+		}
+	}
+	return value, nil
+}
+
+func arrayIndex(key string, length int) (int, bool) {
+	index, err := strconv.Atoi(key)
+	return index, err == nil && index >= 0 && index < length
+}
+
+// lookup resuelve una referencia de la subsolicitud
+type lookup func(ref reference) (any, error)
+
+// replacePath reemplaza las referencias del path por sus valores escapados
+func replacePath(target string, resolve lookup) (string, error) {
+	var failed error
+	target = pattern.ReplaceAllStringFunc(target, func(text string) string {
+		value, err := resolve(parseReference(text, pattern.FindStringSubmatch(text)[1]))
+		if err != nil {
+			failed = err
+			return text
+		}
+		return url.PathEscape(stringify(value))
+	})
+	return target, failed
+}
+
+// replaceBody reemplaza las referencias de los strings del cuerpo. Un string que es solo
+// una referencia toma el valor con su tipo, p. ej. un número para {{user.body.id}}.
+func replaceBody(body json.RawMessage, resolve lookup) ([]byte, error) {
+	if len(body) == 0 || !pattern.Match(body) {
+		return body, nil
+	}
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, apperrors.New(apperrors.ErrBadRequest, "request body must be a valid JSON object")
+	}
+	value, err := replaceValue(value, resolve)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+func replaceValue(value any, resolve lookup) (any, error) {
+	switch current := value.(type) {
+	case map[string]any:
+		for key, item := range current {
+			replaced, err := replaceValue(item, resolve)
+			if err != nil {
+				return nil, err
+			}
+			current[key] = replaced
+		}
+	case []any:
+		for i, item := range current {
+			replaced, err := replaceValue(item, resolve)
+			if err != nil {
+				return nil, err
+			}
+			current[i] = replaced
+		}
+	case string:
+		if match := pattern.FindStringSubmatch(current); match != nil && match[0] == current {
+			return resolve(parseReference(match[0], match[1]))
+		}
+		var failed error
+		replaced := pattern.ReplaceAllStringFunc(current, func(text string) string {
+			value, err := resolve(parseReference(text, pattern.FindStringSubmatch(text)[1]))
+			if err != nil {
+				failed = err
+				return text
+			}
+			return stringify(value)
+		})
+		return replaced, failed
+	}
+	return value, nil
+}
+
+// stringify es el texto de un valor dentro de un string: los strings tal cual y los
+// demás valores como JSON
+func stringify(value any) string {
+	switch current := value.(type) {
+	case string:
+		return current
+	case json.Number:
+		return current.String()
+	default:
+		text, _ := json.Marshal(current)
+		return string(text)
+	}
+}
@@ -1,23 +1,55 @@
 package comments
 
 import (
+	apperrors "blog-api/app/errors"
 	comments "blog-api/app/v1/comments/service"
+	resource "blog-api/app/v1/resource/handler"
 	"blog-api/data"
-	"encoding/json"
-	"github.com/go-chi/chi/v5"
 	"net/http"
 	"strconv"
+
+	"github.com/go-chi/chi/v5"
 )
 
 // CommentHandler maneja las solicitudes relacionadas con comments
 type CommentHandler struct {
-	postService comments.ICommentService
+	*resource.ResourceHandler[data.Comment]
+	commentService comments.ICommentService
 }
 
 // NewCommentHandler crea una nueva instancia del manejador de comments
-func NewCommentHandler(postService comments.ICommentService) *CommentHandler {
+func NewCommentHandler(commentService comments.ICommentService) *CommentHandler {
 	return &CommentHandler{
-		postService: postService,
+		ResourceHandler: resource.NewResourceHandler(resource.Operations[data.Comment]{
+			List:   commentService.GetComments,
+			Get:    commentService.GetComment,
+			Create: commentService.CreateComment,
+			Update: commentService.UpdateComment,
+			Patch:  commentService.PatchComment,
+			Delete: commentService.DeleteComment,
+		}, "commentID", comments.Filters...),
+		commentService: commentService,
+	}
+}
+
+// Replies devuelve la opción de MountResource que registra POST /comments/{commentID}/replies
+func (ph *CommentHandler) Replies() resource.MountOption {
+	return resource.WithSubroutes(func(r chi.Router) {
+		r.Post("/replies", ph.ReplyComment)
+	})
+}
+
+// Routes devuelve las rutas de comments para MountResource
+func (ph *CommentHandler) Routes() resource.Routes {
+	return resource.Routes{
+		IDParam: ph.IDParam(),
+		List:    ph.GetComments,
+		Create:  ph.CreateComment,
+		Get:     ph.GetComment,
+		Update:  ph.UpdateComment,
+		Patch:   ph.PatchComment,
+		Delete:  ph.DeleteComment,
+		Bulk:    ph.BulkComments,
 	}
 }
 
@@ -29,18 +61,12 @@ func NewCommentHandler(postService comments.ICommentService) *CommentHandler {
 // @Produce      json
 // @Success      200
 // @Failure      500
+// @Param        page   query int    false "Page number"
+// @Param        limit  query int    false "Page size"
+// @Param        cursor query string false "Opaque cursor from X-Next-Cursor"
 // @Router       ///v1/comments [get] .
 func (ph *CommentHandler) GetComments(w http.ResponseWriter, r *http.Request) {
-	title := r.URL.Query().Get("title")
-	userID, _ := strconv.Atoi(r.URL.Query().Get("userId"))
-	id, _ := strconv.Atoi(r.URL.Query().Get("id"))
-	comments, err := ph.postService.GetComments(title, userID, id)
-	if err != nil {
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(comments)
+	ph.List(w, r)
 }
 
 // GetComment godoc
@@ -50,58 +76,32 @@ func (ph *CommentHandler) GetComments(w http.ResponseWriter, r *http.Request) {
 // @Accept		 json
 // @Produce      json
 // @Success      200
+// @Success      304
 // @Failure      400
 // @Failure      404
-// @Router       ///v1/post/{postId} [get] .
+// @Router       ///v1/post/{commentID} [get] .
 func (ph *CommentHandler) GetComment(w http.ResponseWriter, r *http.Request) {
-	postID := chi.URLParam(r, "postID")
-	id, err := strconv.Atoi(postID)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	post, err := ph.postService.GetComment(id)
-	if err != nil {
-		http.Error(w, "Not Found", http.StatusNotFound)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(post)
+	ph.Get(w, r)
 }
 
 // CreateComment GetComment godoc
 // @Description Handler to get post
 // @Tags Comments
 // @Description.markdown creat post
-// @Param  		UserID path string true "UserID"
-// @Param  		Title path string true "Title"
+// @Param  		PostID path string true "PostID"
+// @Param  		Name path string true "Name"
+// @Param  		Email path string true "Email"
 // @Param  		Body path string true "Body"
+// @Param  		ParentID path string false "ParentID"
 // @Accept		 json
 // @Produce      json
 // @Success      201
 // @Failure      400
+// @Failure      422
 // @Failure      500
-// @Router       ///v1/post/{postId} [post] .
+// @Router       ///v1/post/{commentID} [post] .
 func (ph *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
-	var post data.Comment
-	err := json.NewDecoder(r.Body).Decode(&post)
-	if err != nil {
-
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	createdComment, err := ph.postService.CreateComment(post)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(createdComment)
+	ph.Create(w, r)
 }
 
 // UpdateComment godoc
@@ -110,76 +110,43 @@ func (ph *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request)
 // @Description.markdown update post
 // @Accept		 json
 // @UrlParam  		CommentID path string true "CommentID"
-// @Param  		UserID path string true "UserID"
-// @Param  		Title path string true "Title"
+// @Param  		PostID path string true "PostID"
+// @Param  		Name path string true "Name"
+// @Param  		Email path string true "Email"
 // @Param  		Body path string true "Body"
+// @Param  		ParentID path string false "ParentID"
 // @Produce      json
 // @Success      200
 // @Failure      400
+// @Failure      412
+// @Failure      422
 // @Failure      500
-// @Router       ///v1/post/{postId} [post] .
+// @Router       ///v1/post/{commentID} [post] .
 func (ph *CommentHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
-	postID := chi.URLParam(r, "postID")
-	id, err := strconv.Atoi(postID)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	var post data.Comment
-	err = json.NewDecoder(r.Body).Decode(&post)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	updatedComment, err := ph.postService.UpdateComment(id, post)
-	if err != nil {
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(updatedComment)
+	ph.Update(w, r)
 }
 
 // PatchComment godoc
 // @Description Handler to patch post
 // @Tags Comments
 // @Description.markdown patch post
-// @Accept		 json
+// @Accept		 json,application/merge-patch+json,application/json-patch+json
 // @UrlParam  		CommentID path string true "CommentID"
-// @Param  		UserID path string true "UserID"
-// @Param  		Title path string true "Title"
+// @Param  		PostID path string true "PostID"
+// @Param  		Name path string true "Name"
+// @Param  		Email path string true "Email"
 // @Param  		Body path string true "Body"
+// @Param  		ParentID path string false "ParentID"
 // @Produce      json
 // @Success      200
 // @Failure      400
+// @Failure      412
+// @Failure      415
+// @Failure      422
 // @Failure      500
-// @Router       ///v1/post/{postId} [patch] .
+// @Router       ///v1/post/{commentID} [patch] .
 func (ph *CommentHandler) PatchComment(w http.ResponseWriter, r *http.Request) {
-	postID := chi.URLParam(r, "postID")
-	id, err := strconv.Atoi(postID)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	var post data.Comment
-	err = json.NewDecoder(r.Body).Decode(&post)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	patchedComment, err := ph.postService.UpdateComment(id, post)
-	if err != nil {
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(patchedComment)
+	ph.Patch(w, r)
 }
 
 // DeleteComment godoc
@@ -191,21 +158,57 @@ func (ph *CommentHandler) PatchComment(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Success      204
 // @Failure      400
+// @Failure      412
+// @Failure      422
 // @Failure      500
-// @Router       ///v1/post/{postId} [patch] .
+// @Router       ///v1/post/{commentID} [patch] .
 func (ph *CommentHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
-	postID := chi.URLParam(r, "postID")
-	id, err := strconv.Atoi(postID)
+	ph.Delete(w, r)
+}
+
+// BulkComments godoc
+// @Description Handler to create, update and delete comments in one request
+// @Tags Comments
+// @Description.markdown bulk comments
+// @Accept		 json,application/x-ndjson
+// @Produce      json
+// @Param        atomic query bool false "Apply all operations or none"
+// @Success      207
+// @Failure      400
+// @Failure      413
+// @Failure      415
+// @Failure      500
+// @Router       ///v1/comments/bulk [post] .
+func (ph *CommentHandler) BulkComments(w http.ResponseWriter, r *http.Request) {
+	ph.Bulk(w, r)
+}
+
+// ReplyComment godoc
+// @Description Handler to reply to a comment; the reply belongs to the post of its parent
+// @Tags Comments
+// @Accept		 json
+// @Produce      json
+// @Param  		commentID path int true "Parent comment ID"
+// @Success      201
+// @Failure      400
+// @Failure      404
+// @Failure      422
+// @Failure      500
+// @Router       ///v1/comments/{commentID}/replies [post] .
+func (ph *CommentHandler) ReplyComment(w http.ResponseWriter, r *http.Request) {
+	parentID, err := strconv.Atoi(chi.URLParam(r, ph.IDParam()))
 	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
+		apperrors.Write(w, r, apperrors.Newf(apperrors.ErrBadRequest, "%s must be an integer", ph.IDParam()), nil)
 		return
 	}
-
-	err = ph.postService.DeleteComment(id)
+	var reply data.Comment
+	if !resource.DecodeJSON(w, r, &reply) {
+		return
+	}
+	created, err := ph.commentService.ReplyComment(r.Context(), parentID, reply)
 	if err != nil {
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		resource.Fail(w, r, err, apperrors.ErrInternal)
 		return
 	}
-
-	w.WriteHeader(http.StatusNoContent)
+	resource.WriteTagged(w, r, http.StatusCreated, created)
 }
@@ -2,11 +2,17 @@ package comments
 
 import (
 	"blog-api/app/mocks"
+	"blog-api/app/pagination"
+	comments "blog-api/app/v1/comments/service"
+	resource "blog-api/app/v1/resource/service"
 	"blog-api/data"
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"net/http"
 	"net/http/httptest"
@@ -18,11 +24,11 @@ func TestGetComments_Success(t *testing.T) {
 	mockCommentService := mocks.NewICommentService(t)
 	defer mockCommentService.AssertExpectations(t)
 	// Mock expected comments
-	mockComments := []data.Comment{{ID: 1, Title: "My Comment", UserID: 1}}
+	mockComments := []data.Comment{{ID: 1, PostID: 1, Name: "My Comment", Email: "ana@example.com"}}
 	// Set mock expectations
-	mockCommentService.On("GetComments", "", 0, 0).Return(&mockComments, nil)
+	mockCommentService.On("GetComments", mock.Anything, resource.Filter{}, pagination.Pagination{}).Return(&mockComments, len(mockComments), nil)
 	// Create handler and request
-	handler := CommentHandler{postService: mockCommentService}
+	handler := NewCommentHandler(mockCommentService)
 	req, _ := http.NewRequest("GET", "/comments", nil)
 	// Create mock response recorder
 	mockRecorder := httptest.NewRecorder()
@@ -41,13 +47,13 @@ func TestGetComment_Success(t *testing.T) {
 	mockCommentService := mocks.NewICommentService(t)
 	defer mockCommentService.AssertExpectations(t)
 	// Mock expected post
-	mockComment := data.Comment{ID: 1, Title: "My Comment", UserID: 1}
+	mockComment := data.Comment{ID: 1, PostID: 1, Name: "My Comment", Email: "ana@example.com"}
 	// Set mock expectations
-	mockCommentService.On("GetComment", 1).Return(&mockComment, nil)
+	mockCommentService.On("GetComment", mock.Anything, 1).Return(&mockComment, nil)
 	// Create handler and request
-	handler := &CommentHandler{postService: mockCommentService}
+	handler := NewCommentHandler(mockCommentService)
 	req, _ := http.NewRequest("GET", "/comments/1", nil)
-	ctx.URLParams.Add("postID", "1")
+	ctx.URLParams.Add("commentID", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
 	// Mock response recorder
 	mockRecorder := httptest.NewRecorder()
@@ -65,12 +71,12 @@ func TestCreateComment_Success(t *testing.T) {
 	mockCommentService := mocks.NewICommentService(t)
 	defer mockCommentService.AssertExpectations(t)
 	// Mock expected post
-	mockComment := data.Comment{Title: "My Comment", UserID: 1}
-	mockCreatedComment := data.Comment{ID: 1, Title: "My Comment", UserID: 1}
+	mockComment := data.Comment{PostID: 1, Name: "My Comment", Email: "ana@example.com"}
+	mockCreatedComment := data.Comment{ID: 1, PostID: 1, Name: "My Comment", Email: "ana@example.com"}
 	// Set mock expectations
-	mockCommentService.On("CreateComment", mockComment).Return(&mockCreatedComment, nil)
+	mockCommentService.On("CreateComment", mock.Anything, mockComment).Return(&mockCreatedComment, nil)
 	// Create handler and request
-	handler := &CommentHandler{postService: mockCommentService}
+	handler := NewCommentHandler(mockCommentService)
 	reqBody, err := json.Marshal(mockComment)
 	require.NoError(t, err)
 	req, _ := http.NewRequest(http.MethodPost, "/comments", bytes.NewReader(reqBody))
@@ -91,19 +97,19 @@ func TestUpdateComment_Success(t *testing.T) {
 	defer mockCommentService.AssertExpectations(t)
 
 	// Mock expected post
-	mockComment := data.Comment{ID: 1, Title: "Updated Comment", UserID: 1}
-	mockUpdatedComment := data.Comment{ID: 1, Title: "Updated Comment", UserID: 1}
+	mockComment := data.Comment{ID: 1, PostID: 1, Name: "Updated Comment", Email: "ana@example.com"}
+	mockUpdatedComment := data.Comment{ID: 1, PostID: 1, Name: "Updated Comment", Email: "ana@example.com"}
 
 	// Set mock expectations
-	mockCommentService.On("UpdateComment", 1, mockComment).Return(&mockUpdatedComment, nil)
+	mockCommentService.On("UpdateComment", mock.Anything, 1, mockComment).Return(&mockUpdatedComment, nil)
 
 	// Create handler and request
-	handler := &CommentHandler{postService: mockCommentService}
+	handler := NewCommentHandler(mockCommentService)
 	reqBody, err := json.Marshal(mockComment)
 	require.NoError(t, err)
 	req, _ := http.NewRequest(http.MethodPut, "/comments/1", bytes.NewReader(reqBody))
 	ctx := chi.NewRouteContext()
-	ctx.URLParams.Add("postID", "1")
+	ctx.URLParams.Add("commentID", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
 
 	// Mock response recorder
@@ -124,20 +130,24 @@ func TestPatchComment_Success(t *testing.T) {
 	mockCommentService := mocks.NewICommentService(t)
 	defer mockCommentService.AssertExpectations(t)
 
-	// Mock expected post with only updated title
-	mockComment := data.Comment{Title: "Updated Title"}
-	mockPatchedComment := data.Comment{ID: 1, Title: "Updated Title", UserID: 1}
+	// Mock current comment and the expected one after applying the patch
+	mockCurrentComment := data.Comment{ID: 1, PostID: 1, Name: "Old Name", Email: "ana@example.com"}
+	mockPatchedComment := data.Comment{ID: 1, PostID: 1, Name: "Updated Title", Email: "ana@example.com"}
 
-	// Set mock expectations
-	mockCommentService.On("UpdateComment", 1, mockComment).Return(&mockPatchedComment, nil)
+	// Set mock expectations: the service applies the patch to the current comment
+	mockCommentService.On("PatchComment", mock.Anything, 1, mock.Anything).Return(
+		func(ctx context.Context, id int, apply func(data.Comment) (data.Comment, error)) (*data.Comment, error) {
+			patched, err := apply(mockCurrentComment)
+			return &patched, err
+		})
 
-	// Create handler and request with only title in the body
-	handler := &CommentHandler{postService: mockCommentService}
-	reqBody, err := json.Marshal(mockComment)
-	require.NoError(t, err)
+	// Create handler and request with a merge patch of the name
+	handler := NewCommentHandler(mockCommentService)
+	reqBody := []byte(`{"name": "Updated Title"}`)
 	req, _ := http.NewRequest(http.MethodPatch, "/comments/1", bytes.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
 	ctx := chi.NewRouteContext()
-	ctx.URLParams.Add("postID", "1")
+	ctx.URLParams.Add("commentID", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
 
 	// Mock response recorder
@@ -159,13 +169,13 @@ func TestDeleteComment_Success(t *testing.T) {
 	defer mockCommentService.AssertExpectations(t)
 
 	// Set mock expectations
-	mockCommentService.On("DeleteComment", 1).Return(nil)
+	mockCommentService.On("DeleteComment", mock.Anything, 1).Return(nil)
 
 	// Create handler and request
-	handler := &CommentHandler{postService: mockCommentService}
+	handler := NewCommentHandler(mockCommentService)
 	req, _ := http.NewRequest(http.MethodDelete, "/comments/1", nil)
 	ctx := chi.NewRouteContext()
-	ctx.URLParams.Add("postID", "1")
+	ctx.URLParams.Add("commentID", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
 
 	// Mock response recorder
@@ -177,3 +187,90 @@ func TestDeleteComment_Success(t *testing.T) {
 	// Assertions
 	require.Equal(t, http.StatusNoContent, mockRecorder.Code)
 }
+
+func TestPostComments(t *testing.T) {
+	// Mock comment service
+	mockCommentService := mocks.NewICommentService(t)
+	post := func(ctx context.Context, id int) error { return nil }
+	handler := NewPostCommentsHandler(mockCommentService, post)
+	r := chi.NewRouter()
+	r.Route("/posts/{postID}", func(r chi.Router) {
+		r.Get("/comments", handler.GetPostComments)
+		r.Post("/comments", handler.CreatePostComment)
+	})
+	t.Run("list", func(t *testing.T) {
+		mockComments := []data.Comment{{ID: 1, PostID: 2, Name: "My Comment"}}
+		mockCommentService.On("GetComments", mock.Anything, resource.Filter{"postId": "2"}, pagination.Pagination{}).Return(&mockComments, 1, nil).Once()
+		req, _ := http.NewRequest(http.MethodGet, "/posts/2/comments", nil)
+		mockRecorder := httptest.NewRecorder()
+		r.ServeHTTP(mockRecorder, req)
+		require.Equal(t, http.StatusOK, mockRecorder.Code)
+	})
+	t.Run("create", func(t *testing.T) {
+		mockCreatedComment := data.Comment{ID: 3, PostID: 2, Name: "My Comment"}
+		mockCommentService.On("CreateComment", mock.Anything, data.Comment{PostID: 2, Name: "My Comment"}).Return(&mockCreatedComment, nil).Once()
+		reqBody, err := json.Marshal(data.Comment{Name: "My Comment"})
+		require.NoError(t, err)
+		req, _ := http.NewRequest(http.MethodPost, "/posts/2/comments", bytes.NewReader(reqBody))
+		mockRecorder := httptest.NewRecorder()
+		r.ServeHTTP(mockRecorder, req)
+		require.Equal(t, http.StatusCreated, mockRecorder.Code)
+	})
+}
+
+func TestGetPostCommentTree(t *testing.T) {
+	mockCommentService := mocks.NewICommentService(t)
+	post := func(ctx context.Context, id int) error {
+		if id != 1 {
+			return errors.New("Post can´t be found. Status Code: 404")
+		}
+		return nil
+	}
+	handler := NewPostCommentsHandler(mockCommentService, post)
+	r := chi.NewRouter()
+	r.Route("/posts/{postID}", func(r chi.Router) {
+		r.Get("/comments/tree", handler.GetPostCommentTree)
+	})
+	serve := func(target string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, target, nil)
+		mockRecorder := httptest.NewRecorder()
+		r.ServeHTTP(mockRecorder, req)
+		return mockRecorder
+	}
+	threads := []*data.CommentThread{{Comment: data.Comment{ID: 1, PostID: 1}, ReplyCount: 1}}
+	mockCommentService.On("GetCommentThread", mock.Anything, 1, 2, true).Return(threads, nil).Once()
+	mockRecorder := serve("/posts/1/comments/tree?depth=2&order=desc")
+	require.Equal(t, http.StatusOK, mockRecorder.Code)
+	require.JSONEq(t, `[{"id": 1, "postId": 1, "name": "", "email": "", "body": "", "replyCount": 1}]`, mockRecorder.Body.String())
+
+	require.Equal(t, http.StatusBadRequest, serve("/posts/1/comments/tree?depth=0").Code)
+	require.Equal(t, http.StatusBadRequest, serve("/posts/1/comments/tree?order=random").Code)
+	require.Equal(t, http.StatusNotFound, serve("/posts/2/comments/tree").Code)
+}
+
+func TestReplyComment(t *testing.T) {
+	mockCommentService := mocks.NewICommentService(t)
+	handler := NewCommentHandler(mockCommentService)
+	r := chi.NewRouter()
+	r.Post("/comments/{commentID}/replies", handler.ReplyComment)
+	reply := data.Comment{Name: "reply", Email: "ana@example.com"}
+	serve := func(target string) *httptest.ResponseRecorder {
+		reqBody, err := json.Marshal(reply)
+		require.NoError(t, err)
+		req, _ := http.NewRequest(http.MethodPost, target, bytes.NewReader(reqBody))
+		mockRecorder := httptest.NewRecorder()
+		r.ServeHTTP(mockRecorder, req)
+		return mockRecorder
+	}
+	created := data.Comment{ID: 7, PostID: 1, ParentID: 3, Name: "reply", Email: "ana@example.com"}
+	mockCommentService.On("ReplyComment", mock.Anything, 3, reply).Return(&created, nil).Once()
+	mockRecorder := serve("/comments/3/replies")
+	require.Equal(t, http.StatusCreated, mockRecorder.Code)
+	require.JSONEq(t, `{"id": 7, "postId": 1, "parentId": 3, "name": "reply", "email": "ana@example.com", "body": ""}`, mockRecorder.Body.String())
+
+	mockCommentService.On("ReplyComment", mock.Anything, 4, reply).Return(nil, comments.ErrDifferentPost).Once()
+	require.Equal(t, http.StatusUnprocessableEntity, serve("/comments/4/replies").Code)
+
+	mockCommentService.On("ReplyComment", mock.Anything, 9, reply).Return(nil, fmt.Errorf("%w. Comment can´t be found", comments.ErrParentNotFound)).Once()
+	require.Equal(t, http.StatusNotFound, serve("/comments/9/replies").Code)
+}
@@ -0,0 +1,126 @@
+package comments
+
+import (
+	apperrors "blog-api/app/errors"
+	comments "blog-api/app/v1/comments/service"
+	resource "blog-api/app/v1/resource/handler"
+	"blog-api/data"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// PostCommentsHandler maneja los comments de un post: /posts/{postID}/comments
+type PostCommentsHandler struct {
+	*resource.NestedHandler[data.Comment]
+	commentService comments.ICommentService
+	post           resource.Parent
+}
+
+// NewPostCommentsHandler crea el manejador; post comprueba que exista el post
+func NewPostCommentsHandler(commentService comments.ICommentService, post resource.Parent) *PostCommentsHandler {
+	handler := resource.NewResourceHandler(resource.Operations[data.Comment]{
+		List:   commentService.GetComments,
+		Create: commentService.CreateComment,
+	}, "commentID", comments.Filters...)
+	return &PostCommentsHandler{
+		NestedHandler: resource.NewNestedHandler(handler, post, "postID", "postId", func(item *data.Comment, id int) {
+			item.PostID = id
+		}),
+		commentService: commentService,
+		post:           post,
+	}
+}
+
+// Mount devuelve la opción de MountResource que registra las rutas bajo /posts/{postID}
+func (h *PostCommentsHandler) Mount() resource.MountOption {
+	return resource.Combine(
+		resource.MountNested("/comments", h.GetPostComments, h.CreatePostComment),
+		resource.WithSubroutes(func(r chi.Router) {
+			r.Get("/comments/tree", h.GetPostCommentTree)
+		}),
+	)
+}
+
+// GetPostComments godoc
+// @Description  Handler to get the comments of a post
+// @Tags Comments
+// @Accept		 json
+// @Produce      json
+// @Param        postID path  int    true  "Post ID"
+// @Param        page   query int    false "Page number"
+// @Param        limit  query int    false "Page size"
+// @Param        cursor query string false "Opaque cursor from X-Next-Cursor"
+// @Success      200
+// @Failure      400
+// @Failure      404
+// @Failure      500
+// @Router       ///v1/posts/{postID}/comments [get] .
+func (h *PostCommentsHandler) GetPostComments(w http.ResponseWriter, r *http.Request) {
+	h.List(w, r)
+}
+
+// CreatePostComment godoc
+// @Description  Handler to create a comment of a post; postId is taken from the path
+// @Tags Comments
+// @Accept		 json
+// @Produce      json
+// @Param        postID path  int    true  "Post ID"
+// @Success      201
+// @Failure      400
+// @Failure      422
+// @Failure      404
+// @Failure      500
+// @Router       ///v1/posts/{postID}/comments [post] .
+func (h *PostCommentsHandler) CreatePostComment(w http.ResponseWriter, r *http.Request) {
+	h.Create(w, r)
+}
+
+// GetPostCommentTree godoc
+// @Description  Handler to get the comments of a post as a tree of replies
+// @Tags Comments
+// @Accept		 json
+// @Produce      json
+// @Param        postID path  int    true  "Post ID"
+// @Param        depth  query int    false "Levels to include, 1 to 10 (default 10)"
+// @Param        order  query string false "asc (oldest first, default) or desc"
+// @Success      200
+// @Failure      400
+// @Failure      404
+// @Failure      500
+// @Router       ///v1/posts/{postID}/comments/tree [get] .
+func (h *PostCommentsHandler) GetPostCommentTree(w http.ResponseWriter, r *http.Request) {
+	postID, err := strconv.Atoi(chi.URLParam(r, "postID"))
+	if err != nil {
+		apperrors.Write(w, r, apperrors.Newf(apperrors.ErrBadRequest, "%s must be an integer", "postID"), nil)
+		return
+	}
+	depth := comments.MaxThreadDepth
+	if value := r.URL.Query().Get("depth"); value != "" {
+		depth, err = strconv.Atoi(value)
+		if err != nil || depth < 1 || depth > comments.MaxThreadDepth {
+			apperrors.Write(w, r, apperrors.Newf(apperrors.ErrBadRequest, "depth must be between 1 and %d", comments.MaxThreadDepth), nil)
+			return
+		}
+	}
+	var newest bool
+	switch r.URL.Query().Get("order") {
+	case "", "asc":
+	case "desc":
+		newest = true
+	default:
+		apperrors.Write(w, r, apperrors.New(apperrors.ErrBadRequest, "order must be asc or desc"), nil)
+		return
+	}
+	if err := h.post(r.Context(), postID); err != nil {
+		resource.Fail(w, r, err, apperrors.ErrNotFound)
+		return
+	}
+	threads, err := h.commentService.GetCommentThread(r.Context(), postID, depth, newest)
+	if err != nil {
+		resource.Fail(w, r, err, apperrors.ErrInternal)
+		return
+	}
+	resource.WriteTagged(w, r, http.StatusOK, threads)
+}
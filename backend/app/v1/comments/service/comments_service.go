@@ -2,171 +2,94 @@ package comments
 
 import (
 	"blog-api/app/clients/restclient"
+	"blog-api/app/config"
+	"blog-api/app/pagination"
+	"blog-api/app/repository"
+	resource "blog-api/app/v1/resource/service"
 	data "blog-api/data"
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"github.com/go-playground/validator/v10"
-	"net/http"
-	"net/url"
-	"os"
-	"strconv"
+	"context"
 )
 
-var baseUrl = os.Getenv("baseURL")
+// Filters son los query params que acepta GetComments
+var Filters = []string{"id", "postId", "parentId", "name", "email"}
 
 // ICommentService define un servicio para obtener comments
 type ICommentService interface {
-	GetComments(title string, userID int, id int) (*[]data.Comment, error)
-	GetComment(id int) (*data.Comment, error)
-	CreateComment(album data.Comment) (*data.Comment, error)
-	UpdateComment(id int, album data.Comment) (*data.Comment, error)
-	DeleteComment(id int) error
+	GetComments(ctx context.Context, filter resource.Filter, page pagination.Pagination) (*[]data.Comment, int, error)
+	GetComment(ctx context.Context, id int) (*data.Comment, error)
+	CreateComment(ctx context.Context, comment data.Comment) (*data.Comment, error)
+	UpdateComment(ctx context.Context, id int, comment data.Comment) (*data.Comment, error)
+	PatchComment(ctx context.Context, id int, apply func(current data.Comment) (data.Comment, error)) (*data.Comment, error)
+	DeleteComment(ctx context.Context, id int) error
+	GetCommentThread(ctx context.Context, postID int, depth int, newest bool) ([]*data.CommentThread, error)
+	ReplyComment(ctx context.Context, parentID int, reply data.Comment) (*data.Comment, error)
 }
 
-// CommentService implementa el servicio utilizando JSONPlaceholder
+// CommentService implementa el servicio utilizando JSONPlaceholder, el almacenamiento local o ambos
 type CommentService struct {
-	restClient restclient.IRestClient
+	resource resource.IResourceService[data.Comment]
 }
 
 // GetComments obtiene comments desde JSONPlaceholder
-func (s *CommentService) GetComments(title string, userID int, id int) (*[]data.Comment, error) {
-	u, err := url.Parse(baseUrl)
-	if err != nil {
-		return nil, err
-	}
-	q := u.Query()
-	if title != "" {
-		q.Set("title", title)
-	}
-	if userID != 0 {
-		q.Set("userId", strconv.Itoa(userID))
-	}
-	if id != 0 {
-		q.Set("id", strconv.Itoa(id))
-	}
-	u.RawQuery = q.Encode()
-	url := u.String()
-	payload := bytes.NewBuffer(nil)
-	method := "GET"
-	resp, err := s.restClient.NewRequest(method, url, payload, nil)
-	var comments []data.Comment
-	err = json.NewDecoder(resp.Body).Decode(&comments)
-	if err != nil {
-		return nil, err
-	}
-	return &comments, nil
+func (s *CommentService) GetComments(ctx context.Context, filter resource.Filter, page pagination.Pagination) (*[]data.Comment, int, error) {
+	return s.resource.List(ctx, filter, page)
 }
 
-func (s *CommentService) GetComment(id int) (*data.Comment, error) {
-	url := fmt.Sprintf("%s/%d", baseUrl, id)
-	method := "GET"
-	resp, err := s.restClient.NewRequest(method, url, bytes.NewBuffer(nil), nil)
-	if err != nil {
-		return nil, err
-	}
-	var album data.Comment
-	err = json.NewDecoder(resp.Body).Decode(&album)
-	if err != nil {
-		return nil, err
-	}
-	return &album, nil
+func (s *CommentService) GetComment(ctx context.Context, id int) (*data.Comment, error) {
+	return s.resource.Get(ctx, id)
 }
 
-func (s *CommentService) CreateComment(album data.Comment) (*data.Comment, error) {
-	validate := validator.New()
-	if err := validate.Struct(album); err != nil {
-		return nil, fmt.Errorf("Comment can´t be created. Status Code: %s", err)
-	}
-
-	url := baseUrl
-	method := "POST"
-	body, err := json.Marshal(album)
-	if err != nil {
+func (s *CommentService) CreateComment(ctx context.Context, comment data.Comment) (*data.Comment, error) {
+	if err := s.checkParent(ctx, 0, comment); err != nil {
 		return nil, err
 	}
-	payload := bytes.NewBuffer(body)
-	headers := map[string]string{"Content-Type": " application/json"}
-	resp, err := s.restClient.NewRequest(method, url, payload, headers)
-	if err != nil {
-		return nil, fmt.Errorf("Comment can´t be created. Status Code: %d", resp.StatusCode)
-	}
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("Comment can´t be created. Status Code: %d", resp.StatusCode)
-	}
-	var createdComment data.Comment
-	err = json.NewDecoder(resp.Body).Decode(&createdComment)
-	if err != nil {
-		return nil, err
-	}
-	return &createdComment, nil
+	return s.resource.Create(ctx, comment)
 }
 
-func (s *CommentService) UpdateComment(id int, album data.Comment) (*data.Comment, error) {
-	url := fmt.Sprintf("%s/%d", baseUrl, id)
-	method := "PUT"
-	body, err := json.Marshal(album)
-	if err != nil {
+func (s *CommentService) UpdateComment(ctx context.Context, id int, comment data.Comment) (*data.Comment, error) {
+	if err := s.checkParent(ctx, id, comment); err != nil {
 		return nil, err
 	}
-	payload := bytes.NewBuffer(body)
-	headers := map[string]string{"Content-Type": " application/json"}
-	resp, err := s.restClient.NewRequest(method, url, payload, headers)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Comment can´t be updated. Status Code: %d", resp.StatusCode)
-	}
-	var updatedComment data.Comment
-	err = json.NewDecoder(resp.Body).Decode(&updatedComment)
-	if err != nil {
-		return nil, err
-	}
-	return &updatedComment, nil
+	return s.resource.Update(ctx, id, comment)
 }
 
-func (s *CommentService) PatchComment(id int, album data.Comment) (*data.Comment, error) {
-	url := fmt.Sprintf("%s/%d", baseUrl, id)
-	method := "PATCH"
-	body, err := json.Marshal(album)
-	if err != nil {
-		return nil, err
-	}
-	headers := map[string]string{"Content-Type": " application/json"}
-	payload := bytes.NewBuffer(body)
-	resp, err := s.restClient.NewRequest(method, url, payload, headers)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Comment can't be updated. Status Code: %d", resp.StatusCode)
-	}
-	var updatedComment data.Comment
-	err = json.NewDecoder(resp.Body).Decode(&updatedComment)
-	if err != nil {
-		return nil, err
-	}
-	return &updatedComment, nil
+// PatchComment aplica apply al comment con el Patch del recurso, que no pisa escrituras
+// concurrentes, y comprueba el padre del resultado antes de guardarlo
+func (s *CommentService) PatchComment(ctx context.Context, id int, apply func(current data.Comment) (data.Comment, error)) (*data.Comment, error) {
+	return s.resource.Patch(ctx, id, func(current data.Comment) (data.Comment, error) {
+		patched, err := apply(current)
+		if err != nil {
+			return patched, err
+		}
+		return patched, s.checkParent(ctx, id, patched)
+	})
+}
+
+func (s *CommentService) DeleteComment(ctx context.Context, id int) error {
+	return s.resource.Delete(ctx, id)
 }
 
-func (s *CommentService) DeleteComment(id int) error {
-	url := fmt.Sprintf("%s/%d", baseUrl, id)
-	method := "DELETE"
-	payload := bytes.NewBuffer(nil)
-	resp, err := s.restClient.NewRequest(method, url, payload, nil)
-	if err != nil {
-		return err
+// NewCommentService crea una nueva instancia del servicio de comments contra la colección upstream dada
+func NewCommentService(client restclient.IRestClient, upstream config.Upstream, opts ...resource.Option) ICommentService {
+	return &CommentService{
+		resource: resource.NewResourceService[data.Comment](client, "Comment", upstream.URL(), opts...),
 	}
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("Comment cannnt be created. Status Code: %d", resp.StatusCode)
+}
+
+// NewCommentLocalService crea el servicio de comments sobre el almacenamiento local
+func NewCommentLocalService(repo repository.CommentRepository) ICommentService {
+	return &CommentService{
+		resource: resource.NewRepositoryService[data.Comment](repo, "Comment"),
 	}
-	return nil
 }
 
-// NewCommentCommentService crea una nueva instancia del servicio de comments
-func NewCommentService(client restclient.IRestClient) ICommentService {
+// NewCommentOverlayService crea el servicio de comments que lee del upstream y guarda las escrituras localmente
+func NewCommentOverlayService(client restclient.IRestClient, upstream config.Upstream, store *repository.OverlayStore, opts ...resource.Option) ICommentService {
 	return &CommentService{
-		restClient: client,
+		resource: resource.NewOverlayService[data.Comment](
+			resource.NewResourceService[data.Comment](client, "Comment", upstream.URL(), opts...),
+			repository.NewOverlay[data.Comment](store, "comments"),
+			"Comment",
+		),
 	}
 }
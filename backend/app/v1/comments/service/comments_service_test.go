@@ -1,9 +1,13 @@
 package comments
 
 import (
+	"blog-api/app/config"
 	"blog-api/app/mocks"
+	"blog-api/app/pagination"
+	resource "blog-api/app/v1/resource/service"
 	data "blog-api/data"
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -16,6 +20,10 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+var upstream = config.Upstream{Root: "https://jsonplaceholder.typicode.com", Path: "comments"}
+
+var baseUrl = upstream.URL()
+
 type MockReader struct {
 	*bytes.Reader
 }
@@ -27,14 +35,14 @@ func (r *MockReader) Close() error {
 func TestCommentService_GetComments_Success(t *testing.T) {
 	mockResp := &http.Response{
 		StatusCode: http.StatusOK,
-		Body:       io.NopCloser(&MockReader{bytes.NewReader([]byte(`[{"id": 1, "title": "Test Title", "userId": 10}]`))}),
+		Body:       io.NopCloser(&MockReader{bytes.NewReader([]byte(`[{"id": 1, "name": "Test Title", "email": "ana@example.com", "postId": 10}]`))}),
 	}
-	//mockResp.Body.Read([]byte(`[{"id": 1, "title": "Test Title", "userId": 10}]`))
+	//mockResp.Body.Read([]byte(`[{"id": 1, "name": "Test Title", "email": "ana@example.com", "postId": 10}]`))
 	mockClient := mocks.NewIRestClient(t)
-	mockClient.On("NewRequest", "GET", "https://jsonplaceholder.typicode.com/comments?id=1&title=Test+Title&userId=10", mock.Anything, mock.Anything).Return(mockResp, nil)
+	mockClient.On("NewRequest", mock.Anything, "GET", "https://jsonplaceholder.typicode.com/comments?id=1&name=Test+Title&postId=10", mock.Anything, mock.Anything).Return(mockResp, nil)
 	//
-	service := &CommentService{restClient: mockClient}
-	comments, err := service.GetComments("Test Title", 10, 1)
+	service := NewCommentService(mockClient, upstream)
+	comments, _, err := service.GetComments(context.Background(), resource.Filter{"name": "Test Title", "postId": "10", "id": "1"}, pagination.Pagination{})
 	require.NoError(t, err)
 	require.NotNil(t, comments)
 	require.Equal(t, 1, len(*comments))
@@ -42,10 +50,10 @@ func TestCommentService_GetComments_Success(t *testing.T) {
 
 func TestCommentService_GetComments_ErrorInRequest(t *testing.T) {
 	mockClient := mocks.NewIRestClient(t)
-	mockClient.On("NewRequest", "GET", "https://jsonplaceholder.typicode.com/comments?id=1&title=Test+Title&userId=10", mock.Anything, mock.Anything).Return(nil, errors.New("request error"))
+	mockClient.On("NewRequest", mock.Anything, "GET", "https://jsonplaceholder.typicode.com/comments?id=1&name=Test+Title&postId=10", mock.Anything, mock.Anything).Return(nil, errors.New("request error"))
 
-	service := &CommentService{restClient: mockClient}
-	_, err := service.GetComments("Test Title", 10, 1)
+	service := NewCommentService(mockClient, upstream)
+	_, _, err := service.GetComments(context.Background(), resource.Filter{"name": "Test Title", "postId": "10", "id": "1"}, pagination.Pagination{})
 	require.Error(t, err)
 }
 
@@ -53,19 +61,20 @@ func TestCommentService_GetComment_Success(t *testing.T) {
 	// Mock expected album data
 	mockComment := &data.Comment{
 		ID:     1,
-		Title:  "Test Title",
-		UserID: 10,
+		PostID: 10,
+		Name:   "Test Title",
+		Email:  "ana@example.com",
 	}
 	// Mock client response
 	mockResp := &http.Response{
 		StatusCode: http.StatusOK,
-		Body:       io.NopCloser(bytes.NewReader([]byte(`{"id": 1, "title": "Test Title", "userId": 10}`))),
+		Body:       io.NopCloser(bytes.NewReader([]byte(`{"id": 1, "name": "Test Title", "email": "ana@example.com", "postId": 10}`))),
 	}
 	mockClient := mocks.NewIRestClient(t)
-	mockClient.On("NewRequest", "GET", fmt.Sprintf("%s/%d", baseUrl, mockComment.ID), mock.Anything, mock.Anything).Return(mockResp, nil)
+	mockClient.On("NewRequest", mock.Anything, "GET", fmt.Sprintf("%s/%d", baseUrl, mockComment.ID), mock.Anything, mock.Anything).Return(mockResp, nil)
 	// Create service and call GetComment
-	service := &CommentService{restClient: mockClient}
-	album, err := service.GetComment(mockComment.ID)
+	service := NewCommentService(mockClient, upstream)
+	album, err := service.GetComment(context.Background(), mockComment.ID)
 	require.NoError(t, err)
 	require.NotNil(t, album)
 	require.Equal(t, *mockComment, *album)
@@ -73,114 +82,117 @@ func TestCommentService_GetComment_Success(t *testing.T) {
 
 func TestCreateComment(t *testing.T) {
 	mockClient := mocks.NewIRestClient(t)
-	service := &CommentService{restClient: mockClient}
+	service := NewCommentService(mockClient, upstream)
 	album := data.Comment{
 		ID:     1,
-		Title:  "Test Title",
-		UserID: 10,
+		PostID: 10,
+		Name:   "Test Title",
+		Email:  "ana@example.com",
 	}
 	t.Run("success", func(t *testing.T) {
-		mockClient.On("NewRequest", "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: http.StatusCreated,
-			Body:       io.NopCloser(bytes.NewReader([]byte(`{"id": 1, "title": "Test Title", "userId": 10}`))),
-		}, nil)
+			Body:       io.NopCloser(bytes.NewReader([]byte(`{"id": 1, "name": "Test Title", "email": "ana@example.com", "postId": 10}`))),
+		}, nil).Once()
 
-		createdComment, err := service.CreateComment(album)
+		createdComment, err := service.CreateComment(context.Background(), album)
 		assert.NoError(t, err)
 		assert.Equal(t, album, *createdComment)
 	})
 	t.Run("error in validation", func(t *testing.T) {
 		invalidComment := data.Comment{
 			ID:     1,
-			Title:  "", // Title is required
-			UserID: 10,
+			PostID: 10,
+			Name:   "", // Name is required
+			Email:  "ana@example.com",
 		}
-		_, err := service.CreateComment(invalidComment)
+		_, err := service.CreateComment(context.Background(), invalidComment)
 		assert.Error(t, err)
 	})
 	t.Run("error in request", func(t *testing.T) {
-		mockClient.On("NewRequest", "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(nil, errors.New("request error"))
+		mockClient.On("NewRequest", mock.Anything, "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(nil, errors.New("request error")).Once()
 
-		_, err := service.CreateComment(album)
+		_, err := service.CreateComment(context.Background(), album)
 		assert.Error(t, err)
 	})
 	t.Run("error in response", func(t *testing.T) {
-		mockClient.On("NewRequest", "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: 500,
 			Body:       ioutil.NopCloser(bytes.NewReader([]byte(``))),
-		}, nil)
+		}, nil).Once()
 
-		_, err := service.CreateComment(album)
+		_, err := service.CreateComment(context.Background(), album)
 		assert.Error(t, err)
 	})
 	t.Run("error in decoding", func(t *testing.T) {
-		mockClient.On("NewRequest", "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: http.StatusCreated,
 			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`not a valid json`))),
-		}, nil)
-		_, err := service.CreateComment(album)
+		}, nil).Once()
+		_, err := service.CreateComment(context.Background(), album)
 		assert.Error(t, err)
 	})
 }
 
 func TestUpdateComment(t *testing.T) {
 	mockClient := mocks.NewIRestClient(t)
-	service := &CommentService{restClient: mockClient}
+	service := NewCommentService(mockClient, upstream)
 
 	album := data.Comment{
 		ID:     1,
-		Title:  "Updated Title",
-		UserID: 10,
+		PostID: 10,
+		Name:   "Updated Title",
+		Email:  "ana@example.com",
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mockClient.On("NewRequest", "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: http.StatusOK,
-			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"id": 1, "title": "Updated Title", "userId": 10}`))),
-		}, nil)
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"id": 1, "name": "Updated Title", "email": "ana@example.com", "postId": 10}`))),
+		}, nil).Once()
 
-		updatedComment, err := service.UpdateComment(album.ID, album)
+		updatedComment, err := service.UpdateComment(context.Background(), album.ID, album)
 		assert.NoError(t, err)
 		assert.Equal(t, album, *updatedComment)
 	})
 
 	t.Run("error in request", func(t *testing.T) {
-		mockClient.On("NewRequest", "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(nil, errors.New("request error"))
+		mockClient.On("NewRequest", mock.Anything, "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(nil, errors.New("request error")).Once()
 
-		_, err := service.UpdateComment(album.ID, album)
+		_, err := service.UpdateComment(context.Background(), album.ID, album)
 		assert.Error(t, err)
 	})
 
 	t.Run("error in response", func(t *testing.T) {
-		mockClient.On("NewRequest", "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: 500,
 			Body:       ioutil.NopCloser(bytes.NewReader([]byte(``))),
-		}, nil)
+		}, nil).Once()
 
-		_, err := service.UpdateComment(album.ID, album)
+		_, err := service.UpdateComment(context.Background(), album.ID, album)
 		assert.Error(t, err)
 	})
 
 	t.Run("error in decoding", func(t *testing.T) {
-		mockClient.On("NewRequest", "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: http.StatusOK,
 			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`not a valid json`))),
-		}, nil)
+		}, nil).Once()
 
-		_, err := service.UpdateComment(album.ID, album)
+		_, err := service.UpdateComment(context.Background(), album.ID, album)
 		assert.Error(t, err)
 	})
 }
 
 func TestDeleteComment(t *testing.T) {
 	mockClient := mocks.NewIRestClient(t)
-	service := &CommentService{restClient: mockClient}
+	service := NewCommentService(mockClient, upstream)
 	albumID := 1
 	t.Run("success", func(t *testing.T) {
-		mockClient.On("NewRequest", "DELETE", fmt.Sprintf("%s/%d", baseUrl, albumID), mock.Anything, mock.Anything).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "DELETE", fmt.Sprintf("%s/%d", baseUrl, albumID), mock.Anything, mock.Anything).Return(&http.Response{
 			StatusCode: http.StatusOK,
-		}, nil)
-		err := service.DeleteComment(albumID)
+		}, nil).Once()
+		err := service.DeleteComment(context.Background(), albumID)
 		assert.NoError(t, err)
 	})
 }
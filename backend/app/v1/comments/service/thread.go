@@ -0,0 +1,112 @@
+package comments
+
+import (
+	apperrors "blog-api/app/errors"
+	"blog-api/app/i18n"
+	"blog-api/app/pagination"
+	resource "blog-api/app/v1/resource/service"
+	"blog-api/data"
+	"context"
+	"sort"
+	"strconv"
+)
+
+// MaxThreadDepth es la profundidad máxima que se puede pedir para un hilo
+const MaxThreadDepth = 10
+
+// ErrParentNotFound indica que no se pudo cargar el comment al que se responde
+var ErrParentNotFound = apperrors.New(apperrors.ErrNotFound, "parent comment can´t be found")
+
+// ErrDifferentPost indica que una respuesta no pertenece al post de su comment padre
+var ErrDifferentPost = i18n.Errorf("%w: a reply must belong to the same post as its parent", resource.ErrUnprocessable)
+
+// GetCommentThread devuelve los comments del post como un árbol de respuestas con depth
+// niveles (los comments raíz son el nivel 1), cada nivel del más viejo al más nuevo o al
+// revés si newest. Los comments cuyo padre no está en el post se tratan como raíces.
+func (s *CommentService) GetCommentThread(ctx context.Context, postID int, depth int, newest bool) ([]*data.CommentThread, error) {
+	comments, _, err := s.resource.List(ctx, resource.Filter{"postId": strconv.Itoa(postID)}, pagination.Pagination{})
+	if err != nil {
+		return nil, err
+	}
+	threads := make(map[int]*data.CommentThread, len(*comments))
+	for _, comment := range *comments {
+		threads[comment.ID] = &data.CommentThread{Comment: comment}
+	}
+	roots := []*data.CommentThread{}
+	for _, comment := range *comments {
+		thread := threads[comment.ID]
+		parent, ok := threads[comment.ParentID]
+		if !ok || comment.ParentID == comment.ID {
+			roots = append(roots, thread)
+			continue
+		}
+		parent.Replies = append(parent.Replies, thread)
+	}
+	arrange(roots, depth, newest, 1)
+	return roots, nil
+}
+
+// arrange ordena cada nivel y corta las respuestas por debajo de la profundidad pedida
+func arrange(threads []*data.CommentThread, depth int, newest bool, level int) {
+	sort.Slice(threads, func(i, j int) bool {
+		if newest {
+			return threads[i].ID > threads[j].ID
+		}
+		return threads[i].ID < threads[j].ID
+	})
+	for _, thread := range threads {
+		thread.ReplyCount = len(thread.Replies)
+		if level >= depth {
+			thread.Replies = nil
+			continue
+		}
+		arrange(thread.Replies, depth, newest, level+1)
+	}
+}
+
+// ReplyComment crea una respuesta al comment parentID, en el mismo post
+func (s *CommentService) ReplyComment(ctx context.Context, parentID int, reply data.Comment) (*data.Comment, error) {
+	parent, err := s.resource.Get(ctx, parentID)
+	if err != nil {
+		return nil, i18n.Errorf("%w. %w", ErrParentNotFound, err)
+	}
+	if reply.PostID != 0 && reply.PostID != parent.PostID {
+		return nil, ErrDifferentPost
+	}
+	reply.PostID = parent.PostID
+	reply.ParentID = parent.ID
+	return s.resource.Create(ctx, reply)
+}
+
+// checkParent comprueba que el comment al que responde comment exista, sea del mismo post
+// y, si id no es 0, que no sea el comment id ni una de sus respuestas: recorre los ancestros
+// del padre hasta una raíz, un padre que no existe o un ciclo que ya estaba guardado
+func (s *CommentService) checkParent(ctx context.Context, id int, comment data.Comment) error {
+	if comment.ParentID == 0 {
+		return nil
+	}
+	if comment.ParentID == id {
+		return i18n.Errorf("%w: a comment can´t reply to itself", resource.ErrUnprocessable)
+	}
+	parent, err := s.resource.Get(ctx, comment.ParentID)
+	if err != nil {
+		return i18n.Errorf("%w: parent comment %d can´t be found. %w", resource.ErrUnprocessable, comment.ParentID, err)
+	}
+	if parent.PostID != comment.PostID {
+		return ErrDifferentPost
+	}
+	if id == 0 {
+		return nil
+	}
+	visited := map[int]bool{parent.ID: true}
+	for ancestor := parent; ancestor.ParentID != 0 && !visited[ancestor.ParentID]; {
+		if ancestor.ParentID == id {
+			return i18n.Errorf("%w: a comment can´t reply to one of its replies", resource.ErrUnprocessable)
+		}
+		visited[ancestor.ParentID] = true
+		if ancestor, err = s.resource.Get(ctx, ancestor.ParentID); err != nil {
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package comments
+
+import (
+	"blog-api/app/repository"
+	resource "blog-api/app/v1/resource/service"
+	data "blog-api/data"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newLocalCommentService(t *testing.T) ICommentService {
+	ctx := context.Background()
+	db, err := repository.Open(ctx, repository.SQLite, ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	migrator, err := repository.NewMigrator(db, repository.SQLite)
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	return NewCommentLocalService(repository.NewCommentRepository(db, repository.SQLite))
+}
+
+func comment(postID int, parentID int, name string) data.Comment {
+	return data.Comment{PostID: postID, ParentID: parentID, Name: name, Email: "ana@example.com"}
+}
+
+// ids devuelve los ids del árbol como {id: respuestas}
+func ids(threads []*data.CommentThread) []any {
+	result := []any{}
+	for _, thread := range threads {
+		if len(thread.Replies) == 0 {
+			result = append(result, thread.ID)
+			continue
+		}
+		result = append(result, map[int][]any{thread.ID: ids(thread.Replies)})
+	}
+	return result
+}
+
+func TestCommentService_Thread(t *testing.T) {
+	ctx := context.Background()
+	service := newLocalCommentService(t)
+	for _, c := range []data.Comment{
+		comment(1, 0, "root 1"),   // 1
+		comment(1, 0, "root 2"),   // 2
+		comment(1, 1, "reply 1"),  // 3
+		comment(1, 3, "reply 3"),  // 4
+		comment(2, 0, "other"),    // 5
+		comment(1, 1, "reply 1b"), // 6
+	} {
+		_, err := service.CreateComment(ctx, c)
+		require.NoError(t, err)
+	}
+
+	threads, err := service.GetCommentThread(ctx, 1, MaxThreadDepth, false)
+	require.NoError(t, err)
+	require.Equal(t, []any{map[int][]any{1: {map[int][]any{3: {4}}, 6}}, 2}, ids(threads))
+	require.Equal(t, 2, threads[0].ReplyCount)
+
+	threads, err = service.GetCommentThread(ctx, 1, 2, true)
+	require.NoError(t, err)
+	require.Equal(t, []any{2, map[int][]any{1: {6, 3}}}, ids(threads))
+	// El nivel cortado conserva la cantidad de respuestas
+	require.Equal(t, 1, threads[1].Replies[1].ReplyCount)
+
+	threads, err = service.GetCommentThread(ctx, 3, MaxThreadDepth, false)
+	require.NoError(t, err)
+	require.Empty(t, threads)
+}
+
+func TestCommentService_Reply(t *testing.T) {
+	ctx := context.Background()
+	service := newLocalCommentService(t)
+	parent, err := service.CreateComment(ctx, comment(1, 0, "root"))
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		reply, err := service.ReplyComment(ctx, parent.ID, data.Comment{Name: "reply", Email: "bea@example.com"})
+		require.NoError(t, err)
+		require.Equal(t, 1, reply.PostID)
+		require.Equal(t, parent.ID, reply.ParentID)
+	})
+	t.Run("different post", func(t *testing.T) {
+		_, err := service.ReplyComment(ctx, parent.ID, comment(2, 0, "reply"))
+		require.ErrorIs(t, err, ErrDifferentPost)
+		require.ErrorIs(t, err, resource.ErrUnprocessable)
+	})
+	t.Run("parent not found", func(t *testing.T) {
+		_, err := service.ReplyComment(ctx, 99, comment(1, 0, "reply"))
+		require.ErrorIs(t, err, ErrParentNotFound)
+	})
+	t.Run("create checks the parent", func(t *testing.T) {
+		_, err := service.CreateComment(ctx, comment(2, parent.ID, "reply"))
+		require.ErrorIs(t, err, ErrDifferentPost)
+		_, err = service.CreateComment(ctx, comment(1, 99, "reply"))
+		require.ErrorIs(t, err, resource.ErrUnprocessable)
+	})
+	t.Run("update can't reply to itself", func(t *testing.T) {
+		_, err := service.UpdateComment(ctx, parent.ID, comment(1, parent.ID, "root"))
+		require.ErrorIs(t, err, resource.ErrUnprocessable)
+	})
+	t.Run("update can't reply to its replies", func(t *testing.T) {
+		reply, err := service.CreateComment(ctx, comment(1, parent.ID, "reply"))
+		require.NoError(t, err)
+		nested, err := service.CreateComment(ctx, comment(1, reply.ID, "nested"))
+		require.NoError(t, err)
+		_, err = service.UpdateComment(ctx, parent.ID, comment(1, reply.ID, "root"))
+		require.ErrorIs(t, err, resource.ErrUnprocessable)
+		_, err = service.PatchComment(ctx, parent.ID, func(current data.Comment) (data.Comment, error) {
+			current.ParentID = nested.ID
+			return current, nil
+		})
+		require.ErrorIs(t, err, resource.ErrUnprocessable)
+		// Mover una respuesta dentro del mismo hilo sigue permitido
+		_, err = service.UpdateComment(ctx, nested.ID, comment(1, parent.ID, "nested"))
+		require.NoError(t, err)
+	})
+	t.Run("patch doesn't overwrite concurrent writes", func(t *testing.T) {
+		_, err := service.PatchComment(ctx, parent.ID, func(current data.Comment) (data.Comment, error) {
+			_, err := service.UpdateComment(ctx, parent.ID, comment(1, 0, "other"))
+			require.NoError(t, err)
+			current.Name = "mine"
+			return current, nil
+		})
+		require.ErrorIs(t, err, repository.ErrVersionConflict)
+		current, err := service.GetComment(ctx, parent.ID)
+		require.NoError(t, err)
+		require.Equal(t, "other", current.Name)
+	})
+}
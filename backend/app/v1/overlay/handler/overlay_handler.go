@@ -0,0 +1,45 @@
+package overlay
+
+import (
+	apperrors "blog-api/app/errors"
+	"context"
+	"net/http"
+	"slices"
+)
+
+// Resetter descarta los cambios locales del modo overlay
+type Resetter interface {
+	Reset(ctx context.Context, resource string) error
+}
+
+// OverlayHandler maneja las solicitudes de administración del overlay
+type OverlayHandler struct {
+	store     Resetter
+	resources []string
+}
+
+// NewOverlayHandler crea el manejador; resources son los recursos que se pueden reiniciar por separado
+func NewOverlayHandler(store Resetter, resources []string) *OverlayHandler {
+	return &OverlayHandler{store: store, resources: resources}
+}
+
+// ResetOverlay godoc
+// @Description  Handler to discard the local writes of the overlay, of every resource or of one
+// @Tags Overlay
+// @Param        resource query string false "Resource to reset, e.g. posts"
+// @Success      204
+// @Failure      400
+// @Failure      500
+// @Router       ///v1/overlay [delete] .
+func (oh *OverlayHandler) ResetOverlay(w http.ResponseWriter, r *http.Request) {
+	resource := r.URL.Query().Get("resource")
+	if resource != "" && !slices.Contains(oh.resources, resource) {
+		apperrors.Write(w, r, apperrors.Newf(apperrors.ErrBadRequest, "unknown resource %q", resource), nil)
+		return
+	}
+	if err := oh.store.Reset(r.Context(), resource); err != nil {
+		apperrors.Write(w, r, err, apperrors.ErrInternal)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,41 @@
+package overlay
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type resetter struct {
+	resources []string
+	err       error
+}
+
+func (r *resetter) Reset(ctx context.Context, resource string) error {
+	r.resources = append(r.resources, resource)
+	return r.err
+}
+
+func TestResetOverlay(t *testing.T) {
+	store := &resetter{}
+	handler := NewOverlayHandler(store, []string{"posts", "users"})
+	for _, target := range []string{"/v1/overlay", "/v1/overlay?resource=posts"} {
+		mockRecorder := httptest.NewRecorder()
+		handler.ResetOverlay(mockRecorder, httptest.NewRequest(http.MethodDelete, target, nil))
+		require.Equal(t, http.StatusNoContent, mockRecorder.Code)
+	}
+	require.Equal(t, []string{"", "posts"}, store.resources)
+
+	mockRecorder := httptest.NewRecorder()
+	handler.ResetOverlay(mockRecorder, httptest.NewRequest(http.MethodDelete, "/v1/overlay?resource=photos", nil))
+	require.Equal(t, http.StatusBadRequest, mockRecorder.Code)
+
+	store.err = errors.New("database is locked")
+	mockRecorder = httptest.NewRecorder()
+	handler.ResetOverlay(mockRecorder, httptest.NewRequest(http.MethodDelete, "/v1/overlay", nil))
+	require.Equal(t, http.StatusInternalServerError, mockRecorder.Code)
+}
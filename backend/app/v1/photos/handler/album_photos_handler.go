@@ -0,0 +1,151 @@
+package photos
+
+import (
+	apperrors "blog-api/app/errors"
+	photos "blog-api/app/v1/photos/service"
+	resource "blog-api/app/v1/resource/handler"
+	"blog-api/data"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// formOverhead es el espacio que se deja para los demás campos y los separadores del formulario
+const formOverhead = 1 << 20
+
+// AlbumPhotosHandler maneja las photos de un album: /albums/{albumID}/photos
+type AlbumPhotosHandler struct {
+	*resource.NestedHandler[data.Photo]
+	album    resource.Parent
+	uploader *photos.Uploader
+	maxSize  int64
+}
+
+// NewAlbumPhotosHandler crea el manejador; album comprueba que exista el album, uploader
+// guarda las imágenes subidas y maxSize es su tamaño máximo en bytes. Sin uploader las
+// subidas multipart responden 415.
+func NewAlbumPhotosHandler(photoService photos.IPhotoService, album resource.Parent, uploader *photos.Uploader, maxSize int64) *AlbumPhotosHandler {
+	handler := resource.NewResourceHandler(resource.Operations[data.Photo]{
+		List:   photoService.GetPhotos,
+		Create: photoService.CreatePhoto,
+	}, "photoID", photos.Filters...)
+	return &AlbumPhotosHandler{
+		NestedHandler: resource.NewNestedHandler(handler, album, "albumID", "albumId", func(item *data.Photo, id int) {
+			item.AlbumID = id
+		}),
+		album:    album,
+		uploader: uploader,
+		maxSize:  maxSize,
+	}
+}
+
+// Mount devuelve la opción de MountResource que registra las rutas bajo /albums/{albumID}
+func (h *AlbumPhotosHandler) Mount() resource.MountOption {
+	return resource.MountNested("/photos", h.GetAlbumPhotos, h.CreateAlbumPhoto)
+}
+
+// GetAlbumPhotos godoc
+// @Description  Handler to get the photos of an album
+// @Tags Photos
+// @Accept		 json
+// @Produce      json
+// @Param        albumID path  int    true  "Album ID"
+// @Param        page    query int    false "Page number"
+// @Param        limit   query int    false "Page size"
+// @Param        cursor  query string false "Opaque cursor from X-Next-Cursor"
+// @Success      200
+// @Failure      400
+// @Failure      404
+// @Failure      500
+// @Router       ///v1/albums/{albumID}/photos [get] .
+func (h *AlbumPhotosHandler) GetAlbumPhotos(w http.ResponseWriter, r *http.Request) {
+	h.List(w, r)
+}
+
+// CreateAlbumPhoto godoc
+// @Description  Handler to create a photo of an album; albumId is taken from the path.
+// @Description  A JSON body creates the photo with the given URLs; a multipart form with
+// @Description  title and file uploads a JPEG or PNG image and generates its thumbnail
+// @Description  (only in local and overlay modes; proxy mode answers 415).
+// @Tags Photos
+// @Accept		 json,mpfd
+// @Produce      json
+// @Param        albumID path     int    true  "Album ID"
+// @Param        title   formData string false "Title (multipart)"
+// @Param        file    formData file   false "JPEG or PNG image (multipart)"
+// @Success      201
+// @Failure      400
+// @Failure      422
+// @Failure      404
+// @Failure      413
+// @Failure      415
+// @Failure      500
+// @Router       ///v1/albums/{albumID}/photos [post] .
+func (h *AlbumPhotosHandler) CreateAlbumPhoto(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		h.Create(w, r)
+		return
+	}
+	h.upload(w, r)
+}
+
+// upload crea la photo a partir de la imagen del formulario multipart
+func (h *AlbumPhotosHandler) upload(w http.ResponseWriter, r *http.Request) {
+	if h.uploader == nil {
+		apperrors.Write(w, r, apperrors.New(apperrors.ErrUnsupportedMediaType, "image uploads need local or overlay mode, the upstream doesn´t keep new photos"), nil)
+		return
+	}
+	albumID, err := strconv.Atoi(chi.URLParam(r, "albumID"))
+	if err != nil {
+		apperrors.Write(w, r, apperrors.Newf(apperrors.ErrBadRequest, "%s must be an integer", "albumID"), nil)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxSize+formOverhead)
+	if err := r.ParseMultipartForm(formOverhead); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			apperrors.Write(w, r, h.tooLarge(), nil)
+			return
+		}
+		apperrors.Write(w, r, apperrors.Newf(apperrors.ErrBadRequest, "invalid multipart form: %w", err), nil)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+	title := r.FormValue("title")
+	file, header, err := r.FormFile("file")
+	if title == "" || err != nil {
+		apperrors.Write(w, r, apperrors.New(apperrors.ErrBadRequest, "title and file are required"), nil)
+		return
+	}
+	defer file.Close()
+	if header.Size > h.maxSize {
+		apperrors.Write(w, r, h.tooLarge(), nil)
+		return
+	}
+	content, err := io.ReadAll(file)
+	if err != nil {
+		resource.Fail(w, r, err, apperrors.ErrInternal)
+		return
+	}
+	if err := h.album(r.Context(), albumID); err != nil {
+		resource.Fail(w, r, err, apperrors.ErrNotFound)
+		return
+	}
+	created, err := h.uploader.Upload(r.Context(), data.Photo{AlbumID: albumID, Title: title}, content)
+	if err != nil {
+		// Los errores de thumbnail ya indican si el archivo es muy grande o de un tipo no soportado
+		resource.Fail(w, r, err, apperrors.ErrInternal)
+		return
+	}
+	resource.WriteTagged(w, r, http.StatusCreated, created)
+}
+
+// tooLarge es el error de un archivo que supera el tamaño máximo
+func (h *AlbumPhotosHandler) tooLarge() error {
+	return apperrors.Newf(apperrors.ErrPayloadTooLarge, "file is larger than %d bytes", h.maxSize)
+}
@@ -0,0 +1,159 @@
+package photos
+
+import (
+	"blog-api/app/blob"
+	"blog-api/app/mocks"
+	photos "blog-api/app/v1/photos/service"
+	resource "blog-api/app/v1/resource/handler"
+	"blog-api/data"
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newAlbumPhotosRouter(t *testing.T, photoService photos.IPhotoService, maxSize int64) (*chi.Mux, string) {
+	dir := t.TempDir()
+	store, err := blob.NewDisk(dir)
+	require.NoError(t, err)
+	album := func(ctx context.Context, id int) error {
+		if id != 1 {
+			return errors.New("album not found")
+		}
+		return nil
+	}
+	handler := NewAlbumPhotosHandler(photoService, album, photos.NewUploader(photoService, store, "/files", 150), maxSize)
+	r := chi.NewRouter()
+	resource.MountResource(r, "/albums", resource.Routes{IDParam: "albumID"}, handler.Mount())
+	return r, dir
+}
+
+// uploadRequest arma un formulario multipart con title y, si content no es nil, file
+func uploadRequest(t *testing.T, path string, title string, content []byte) *http.Request {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	require.NoError(t, form.WriteField("title", title))
+	if content != nil {
+		part, err := form.CreateFormFile("file", "photo.png")
+		require.NoError(t, err)
+		_, err = part.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, form.Close())
+	req := httptest.NewRequest(http.MethodPost, path, &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return req
+}
+
+func pngImage(t *testing.T, width, height int) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, width, height))))
+	return buf.Bytes()
+}
+
+func TestCreateAlbumPhoto_Upload(t *testing.T) {
+	mockPhotoService := mocks.NewIPhotoService(t)
+	defer mockPhotoService.AssertExpectations(t)
+	var created data.Photo
+	mockPhotoService.On("CreatePhoto", mock.Anything, mock.MatchedBy(func(photo data.Photo) bool {
+		return photo.AlbumID == 1 && photo.Title == "Sunset" &&
+			strings.HasPrefix(photo.URL, "/files/photos/") && strings.HasSuffix(photo.ThumbnailURL, "_thumb.png")
+	})).Return(func(ctx context.Context, photo data.Photo) (*data.Photo, error) {
+		photo.ID = 7
+		created = photo
+		return &photo, nil
+	}).Once()
+	r, dir := newAlbumPhotosRouter(t, mockPhotoService, 1<<20)
+
+	mockRecorder := httptest.NewRecorder()
+	r.ServeHTTP(mockRecorder, uploadRequest(t, "/albums/1/photos", "Sunset", pngImage(t, 600, 300)))
+	require.Equal(t, http.StatusCreated, mockRecorder.Code, mockRecorder.Body.String())
+	require.Contains(t, mockRecorder.Body.String(), `"id":7`)
+
+	thumb, err := os.ReadFile(filepath.Join(dir, strings.TrimPrefix(created.ThumbnailURL, "/files/")))
+	require.NoError(t, err)
+	config, err := png.DecodeConfig(bytes.NewReader(thumb))
+	require.NoError(t, err)
+	require.Equal(t, [2]int{150, 75}, [2]int{config.Width, config.Height})
+}
+
+func TestCreateAlbumPhoto_UploadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		title   string
+		content []byte
+		status  int
+	}{
+		{"missing file", "/albums/1/photos", "Sunset", nil, http.StatusBadRequest},
+		{"missing title", "/albums/1/photos", "", []byte("x"), http.StatusBadRequest},
+		{"album not found", "/albums/9/photos", "Sunset", []byte("\x89PNG\r\n\x1a\n"), http.StatusNotFound},
+		{"not an image", "/albums/1/photos", "Sunset", []byte("<html>hello</html>"), http.StatusUnsupportedMediaType},
+		{"too large", "/albums/1/photos", "Sunset", bytes.Repeat([]byte("x"), 2048), http.StatusRequestEntityTooLarge},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, dir := newAlbumPhotosRouter(t, mocks.NewIPhotoService(t), 1024)
+			mockRecorder := httptest.NewRecorder()
+			r.ServeHTTP(mockRecorder, uploadRequest(t, tc.path, tc.title, tc.content))
+			require.Equal(t, tc.status, mockRecorder.Code, mockRecorder.Body.String())
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			require.Empty(t, entries)
+		})
+	}
+}
+
+func TestCreateAlbumPhoto_UploadCleansUp(t *testing.T) {
+	mockPhotoService := mocks.NewIPhotoService(t)
+	defer mockPhotoService.AssertExpectations(t)
+	mockPhotoService.On("CreatePhoto", mock.Anything, mock.Anything).Return(nil, errors.New("create failed")).Once()
+	r, dir := newAlbumPhotosRouter(t, mockPhotoService, 1<<20)
+
+	mockRecorder := httptest.NewRecorder()
+	r.ServeHTTP(mockRecorder, uploadRequest(t, "/albums/1/photos", "Sunset", pngImage(t, 10, 10)))
+	require.Equal(t, http.StatusInternalServerError, mockRecorder.Code)
+	entries, err := os.ReadDir(filepath.Join(dir, "photos"))
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestCreateAlbumPhoto_UploadWithoutUploader(t *testing.T) {
+	handler := NewAlbumPhotosHandler(mocks.NewIPhotoService(t), func(ctx context.Context, id int) error { return nil }, nil, 1<<20)
+	r := chi.NewRouter()
+	resource.MountResource(r, "/albums", resource.Routes{IDParam: "albumID"}, handler.Mount())
+
+	mockRecorder := httptest.NewRecorder()
+	r.ServeHTTP(mockRecorder, uploadRequest(t, "/albums/1/photos", "Sunset", pngImage(t, 10, 10)))
+	require.Equal(t, http.StatusUnsupportedMediaType, mockRecorder.Code)
+	require.Contains(t, mockRecorder.Body.String(), "local or overlay mode")
+}
+
+func TestCreateAlbumPhoto_JSON(t *testing.T) {
+	mockPhotoService := mocks.NewIPhotoService(t)
+	defer mockPhotoService.AssertExpectations(t)
+	photo := data.Photo{AlbumID: 1, Title: "Sunset", URL: "https://example.com/a.png"}
+	mockPhotoService.On("CreatePhoto", mock.Anything, photo).Return(&data.Photo{ID: 1, AlbumID: 1, Title: "Sunset"}, nil).Once()
+	r, _ := newAlbumPhotosRouter(t, mockPhotoService, 1024)
+
+	req := httptest.NewRequest(http.MethodPost, "/albums/1/photos", strings.NewReader(`{"albumId": 2, "title": "Sunset", "url": "https://example.com/a.png"}`))
+	req.Header.Set("Content-Type", "application/json")
+	mockRecorder := httptest.NewRecorder()
+	r.ServeHTTP(mockRecorder, req)
+	require.Equal(t, http.StatusCreated, mockRecorder.Code)
+	body, _ := io.ReadAll(mockRecorder.Body)
+	require.JSONEq(t, `{"id": 1, "albumId": 1, "title": "Sunset", "url": "", "thumbnailUrl": ""}`, string(body))
+}
@@ -0,0 +1,167 @@
+package photos
+
+import (
+	photos "blog-api/app/v1/photos/service"
+	resource "blog-api/app/v1/resource/handler"
+	"blog-api/data"
+	"net/http"
+)
+
+// PhotoHandler maneja las solicitudes relacionadas con photos
+type PhotoHandler struct {
+	*resource.ResourceHandler[data.Photo]
+}
+
+// NewPhotoHandler crea una nueva instancia del manejador de photos
+func NewPhotoHandler(photoService photos.IPhotoService) *PhotoHandler {
+	return &PhotoHandler{
+		ResourceHandler: resource.NewResourceHandler(resource.Operations[data.Photo]{
+			List:   photoService.GetPhotos,
+			Get:    photoService.GetPhoto,
+			Create: photoService.CreatePhoto,
+			Update: photoService.UpdatePhoto,
+			Patch:  photoService.PatchPhoto,
+			Delete: photoService.DeletePhoto,
+		}, "photoID", photos.Filters...),
+	}
+}
+
+// Routes devuelve las rutas de photos para MountResource
+func (ph *PhotoHandler) Routes() resource.Routes {
+	return resource.Routes{
+		IDParam: ph.IDParam(),
+		List:    ph.GetPhotos,
+		Create:  ph.CreatePhoto,
+		Get:     ph.GetPhoto,
+		Update:  ph.UpdatePhoto,
+		Patch:   ph.PatchPhoto,
+		Delete:  ph.DeletePhoto,
+		Bulk:    ph.BulkPhotos,
+	}
+}
+
+// GetPhotos godoc
+// @Description  Handler to get photos
+// @Tags Photos
+// @Description.markdown get photos
+// @Accept		 json
+// @Produce      json
+// @Success      200
+// @Failure      500
+// @Param        page   query int    false "Page number"
+// @Param        limit  query int    false "Page size"
+// @Param        cursor query string false "Opaque cursor from X-Next-Cursor"
+// @Router       ///v1/photos [get] .
+func (ph *PhotoHandler) GetPhotos(w http.ResponseWriter, r *http.Request) {
+	ph.List(w, r)
+}
+
+// GetPhoto godoc
+// @Description Handler to get photo
+// @Tags Photos
+// @Description.markdown get photo
+// @Accept		 json
+// @Produce      json
+// @Success      200
+// @Success      304
+// @Failure      400
+// @Failure      404
+// @Router       ///v1/photos/{photoID} [get] .
+func (ph *PhotoHandler) GetPhoto(w http.ResponseWriter, r *http.Request) {
+	ph.Get(w, r)
+}
+
+// CreatePhoto godoc
+// @Description Handler to create photo
+// @Tags Photos
+// @Description.markdown create photo
+// @Param  		AlbumID path string true "AlbumID"
+// @Param  		Title path string true "Title"
+// @Param  		URL path string false "URL"
+// @Param  		ThumbnailURL path string false "ThumbnailURL"
+// @Accept		 json
+// @Produce      json
+// @Success      201
+// @Failure      400
+// @Failure      422
+// @Failure      500
+// @Router       ///v1/photos [post] .
+func (ph *PhotoHandler) CreatePhoto(w http.ResponseWriter, r *http.Request) {
+	ph.Create(w, r)
+}
+
+// UpdatePhoto godoc
+// @Description Handler to update photo
+// @Tags Photos
+// @Description.markdown update photo
+// @Accept		 json
+// @UrlParam  		PhotoID path string true "PhotoID"
+// @Param  		AlbumID path string true "AlbumID"
+// @Param  		Title path string true "Title"
+// @Param  		URL path string false "URL"
+// @Param  		ThumbnailURL path string false "ThumbnailURL"
+// @Produce      json
+// @Success      200
+// @Failure      400
+// @Failure      412
+// @Failure      422
+// @Failure      500
+// @Router       ///v1/photos/{photoID} [put] .
+func (ph *PhotoHandler) UpdatePhoto(w http.ResponseWriter, r *http.Request) {
+	ph.Update(w, r)
+}
+
+// PatchPhoto godoc
+// @Description Handler to patch photo
+// @Tags Photos
+// @Description.markdown patch photo
+// @Accept		 json,application/merge-patch+json,application/json-patch+json
+// @UrlParam  		PhotoID path string true "PhotoID"
+// @Param  		AlbumID path string true "AlbumID"
+// @Param  		Title path string true "Title"
+// @Param  		URL path string false "URL"
+// @Param  		ThumbnailURL path string false "ThumbnailURL"
+// @Produce      json
+// @Success      200
+// @Failure      400
+// @Failure      412
+// @Failure      415
+// @Failure      422
+// @Failure      500
+// @Router       ///v1/photos/{photoID} [patch] .
+func (ph *PhotoHandler) PatchPhoto(w http.ResponseWriter, r *http.Request) {
+	ph.Patch(w, r)
+}
+
+// DeletePhoto godoc
+// @Description Handler to delete photo
+// @Tags Photos
+// @Description.markdown delete photo
+// @Accept		 json
+// @UrlParam  		PhotoID path string true "PhotoID"
+// @Produce      json
+// @Success      204
+// @Failure      400
+// @Failure      412
+// @Failure      500
+// @Router       ///v1/photos/{photoID} [delete] .
+func (ph *PhotoHandler) DeletePhoto(w http.ResponseWriter, r *http.Request) {
+	ph.Delete(w, r)
+}
+
+// BulkPhotos godoc
+// @Description Handler to create, update and delete photos in one request
+// @Tags Photos
+// @Description.markdown bulk photos
+// @Accept		 json,application/x-ndjson
+// @Produce      json
+// @Param        atomic query bool false "Apply all operations or none"
+// @Success      207
+// @Failure      400
+// @Failure      413
+// @Failure      415
+// @Failure      500
+// @Router       ///v1/photos/bulk [post] .
+func (ph *PhotoHandler) BulkPhotos(w http.ResponseWriter, r *http.Request) {
+	ph.Bulk(w, r)
+}
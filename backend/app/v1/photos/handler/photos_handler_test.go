@@ -0,0 +1,186 @@
+package photos
+
+import (
+	"blog-api/app/mocks"
+	"blog-api/app/pagination"
+	resource "blog-api/app/v1/resource/service"
+	"blog-api/data"
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetPhotos_Success(t *testing.T) {
+	// Mock post service
+	mockPhotoService := mocks.NewIPhotoService(t)
+	defer mockPhotoService.AssertExpectations(t)
+	// Mock expected photos
+	mockPhotos := []data.Photo{{ID: 1, Title: "My Photo", AlbumID: 1}}
+	// Set mock expectations
+	mockPhotoService.On("GetPhotos", mock.Anything, resource.Filter{}, pagination.Pagination{}).Return(&mockPhotos, len(mockPhotos), nil)
+	// Create handler and request
+	handler := NewPhotoHandler(mockPhotoService)
+	req, _ := http.NewRequest("GET", "/photos", nil)
+	// Create mock response recorder
+	mockRecorder := httptest.NewRecorder()
+	// Handle request
+	handler.GetPhotos(mockRecorder, req)
+	jsonBytes, _ := json.Marshal(mockPhotos)
+	// Assertions
+	require.Equal(t, http.StatusOK, mockRecorder.Code)
+	require.Equal(t, "application/json", mockRecorder.HeaderMap["Content-Type"][0])
+	require.Equal(t, string(jsonBytes)+"\n", mockRecorder.Body.String())
+}
+
+func TestGetPhoto_Success(t *testing.T) {
+	// Mock post service
+	ctx := chi.NewRouteContext()
+	mockPhotoService := mocks.NewIPhotoService(t)
+	defer mockPhotoService.AssertExpectations(t)
+	// Mock expected post
+	mockPhoto := data.Photo{ID: 1, Title: "My Photo", AlbumID: 1}
+	// Set mock expectations
+	mockPhotoService.On("GetPhoto", mock.Anything, 1).Return(&mockPhoto, nil)
+	// Create handler and request
+	handler := NewPhotoHandler(mockPhotoService)
+	req, _ := http.NewRequest("GET", "/photos/1", nil)
+	ctx.URLParams.Add("photoID", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+	// Mock response recorder
+	mockRecorder := httptest.NewRecorder()
+	// Handle request
+	handler.GetPhoto(mockRecorder, req)
+	jsonBytes, _ := json.Marshal(mockPhoto)
+
+	// Assertions
+	require.Equal(t, http.StatusOK, mockRecorder.Code)
+	require.Equal(t, string(jsonBytes)+"\n", mockRecorder.Body.String())
+}
+
+func TestCreatePhoto_Success(t *testing.T) {
+	// Mock post service
+	mockPhotoService := mocks.NewIPhotoService(t)
+	defer mockPhotoService.AssertExpectations(t)
+	// Mock expected post
+	mockPhoto := data.Photo{Title: "My Photo", AlbumID: 1}
+	mockCreatedPhoto := data.Photo{ID: 1, Title: "My Photo", AlbumID: 1}
+	// Set mock expectations
+	mockPhotoService.On("CreatePhoto", mock.Anything, mockPhoto).Return(&mockCreatedPhoto, nil)
+	// Create handler and request
+	handler := NewPhotoHandler(mockPhotoService)
+	reqBody, err := json.Marshal(mockPhoto)
+	require.NoError(t, err)
+	req, _ := http.NewRequest(http.MethodPost, "/photos", bytes.NewReader(reqBody))
+	// Mock response recorder
+	mockRecorder := httptest.NewRecorder()
+	// Handle request
+	handler.CreatePhoto(mockRecorder, req)
+	// Assertions
+	jsonBytes, _ := json.Marshal(mockCreatedPhoto)
+	require.Equal(t, http.StatusCreated, mockRecorder.Code)
+	require.Equal(t, "application/json", mockRecorder.HeaderMap["Content-Type"][0])
+	require.Equal(t, string(jsonBytes)+"\n", mockRecorder.Body.String())
+}
+
+func TestUpdatePhoto_Success(t *testing.T) {
+	// Mock post service
+	mockPhotoService := mocks.NewIPhotoService(t)
+	defer mockPhotoService.AssertExpectations(t)
+
+	// Mock expected post
+	mockPhoto := data.Photo{ID: 1, Title: "Updated Photo", AlbumID: 1}
+	mockUpdatedPhoto := data.Photo{ID: 1, Title: "Updated Photo", AlbumID: 1}
+
+	// Set mock expectations
+	mockPhotoService.On("UpdatePhoto", mock.Anything, 1, mockPhoto).Return(&mockUpdatedPhoto, nil)
+
+	// Create handler and request
+	handler := NewPhotoHandler(mockPhotoService)
+	reqBody, err := json.Marshal(mockPhoto)
+	require.NoError(t, err)
+	req, _ := http.NewRequest(http.MethodPut, "/photos/1", bytes.NewReader(reqBody))
+	ctx := chi.NewRouteContext()
+	ctx.URLParams.Add("photoID", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+
+	// Mock response recorder
+	mockRecorder := httptest.NewRecorder()
+
+	// Handle request
+	handler.UpdatePhoto(mockRecorder, req)
+
+	// Assertions
+	jsonBytes, _ := json.Marshal(mockUpdatedPhoto)
+	require.Equal(t, http.StatusOK, mockRecorder.Code)
+	require.Equal(t, "application/json", mockRecorder.HeaderMap["Content-Type"][0])
+	require.Equal(t, string(jsonBytes)+"\n", mockRecorder.Body.String())
+}
+
+func TestPatchPhoto_Success(t *testing.T) {
+	// Mock post service
+	mockPhotoService := mocks.NewIPhotoService(t)
+	defer mockPhotoService.AssertExpectations(t)
+
+	// Mock current photo and the expected one after applying the patch
+	mockCurrentPhoto := data.Photo{ID: 1, Title: "Old Title", AlbumID: 1}
+	mockPatchedPhoto := data.Photo{ID: 1, Title: "Updated Title", AlbumID: 1}
+
+	// Set mock expectations: the service applies the patch to the current photo
+	mockPhotoService.On("PatchPhoto", mock.Anything, 1, mock.Anything).Return(
+		func(ctx context.Context, id int, apply func(data.Photo) (data.Photo, error)) (*data.Photo, error) {
+			patched, err := apply(mockCurrentPhoto)
+			return &patched, err
+		})
+
+	// Create handler and request with a merge patch of the title
+	handler := NewPhotoHandler(mockPhotoService)
+	reqBody := []byte(`{"title": "Updated Title"}`)
+	req, _ := http.NewRequest(http.MethodPatch, "/photos/1", bytes.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	ctx := chi.NewRouteContext()
+	ctx.URLParams.Add("photoID", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+
+	// Mock response recorder
+	mockRecorder := httptest.NewRecorder()
+
+	// Handle request
+	handler.PatchPhoto(mockRecorder, req)
+
+	// Assertions
+	jsonBytes, _ := json.Marshal(mockPatchedPhoto)
+	require.Equal(t, http.StatusOK, mockRecorder.Code)
+	require.Equal(t, "application/json", mockRecorder.HeaderMap["Content-Type"][0])
+	require.Equal(t, string(jsonBytes)+"\n", mockRecorder.Body.String())
+}
+
+func TestDeletePhoto_Success(t *testing.T) {
+	// Mock post service
+	mockPhotoService := mocks.NewIPhotoService(t)
+	defer mockPhotoService.AssertExpectations(t)
+
+	// Set mock expectations
+	mockPhotoService.On("DeletePhoto", mock.Anything, 1).Return(nil)
+
+	// Create handler and request
+	handler := NewPhotoHandler(mockPhotoService)
+	req, _ := http.NewRequest(http.MethodDelete, "/photos/1", nil)
+	ctx := chi.NewRouteContext()
+	ctx.URLParams.Add("photoID", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+
+	// Mock response recorder
+	mockRecorder := httptest.NewRecorder()
+
+	// Handle request
+	handler.DeletePhoto(mockRecorder, req)
+
+	// Assertions
+	require.Equal(t, http.StatusNoContent, mockRecorder.Code)
+}
@@ -0,0 +1,79 @@
+package photos
+
+import (
+	"blog-api/app/clients/restclient"
+	"blog-api/app/config"
+	"blog-api/app/pagination"
+	"blog-api/app/repository"
+	resource "blog-api/app/v1/resource/service"
+	data "blog-api/data"
+	"context"
+)
+
+// Filters son los query params que acepta GetPhotos
+var Filters = []string{"id", "albumId", "title"}
+
+// IPhotoService define un servicio para obtener photos
+type IPhotoService interface {
+	GetPhotos(ctx context.Context, filter resource.Filter, page pagination.Pagination) (*[]data.Photo, int, error)
+	GetPhoto(ctx context.Context, id int) (*data.Photo, error)
+	CreatePhoto(ctx context.Context, photo data.Photo) (*data.Photo, error)
+	UpdatePhoto(ctx context.Context, id int, photo data.Photo) (*data.Photo, error)
+	PatchPhoto(ctx context.Context, id int, apply func(current data.Photo) (data.Photo, error)) (*data.Photo, error)
+	DeletePhoto(ctx context.Context, id int) error
+}
+
+// PhotoService implementa el servicio utilizando JSONPlaceholder, el almacenamiento local o ambos
+type PhotoService struct {
+	resource resource.IResourceService[data.Photo]
+}
+
+// GetPhotos obtiene photos desde JSONPlaceholder
+func (s *PhotoService) GetPhotos(ctx context.Context, filter resource.Filter, page pagination.Pagination) (*[]data.Photo, int, error) {
+	return s.resource.List(ctx, filter, page)
+}
+
+func (s *PhotoService) GetPhoto(ctx context.Context, id int) (*data.Photo, error) {
+	return s.resource.Get(ctx, id)
+}
+
+func (s *PhotoService) CreatePhoto(ctx context.Context, photo data.Photo) (*data.Photo, error) {
+	return s.resource.Create(ctx, photo)
+}
+
+func (s *PhotoService) UpdatePhoto(ctx context.Context, id int, photo data.Photo) (*data.Photo, error) {
+	return s.resource.Update(ctx, id, photo)
+}
+
+func (s *PhotoService) PatchPhoto(ctx context.Context, id int, apply func(current data.Photo) (data.Photo, error)) (*data.Photo, error) {
+	return s.resource.Patch(ctx, id, apply)
+}
+
+func (s *PhotoService) DeletePhoto(ctx context.Context, id int) error {
+	return s.resource.Delete(ctx, id)
+}
+
+// NewPhotoService crea una nueva instancia del servicio de photos contra la colección upstream dada
+func NewPhotoService(client restclient.IRestClient, upstream config.Upstream, opts ...resource.Option) IPhotoService {
+	return &PhotoService{
+		resource: resource.NewResourceService[data.Photo](client, "Photo", upstream.URL(), opts...),
+	}
+}
+
+// NewPhotoLocalService crea el servicio de photos sobre el almacenamiento local
+func NewPhotoLocalService(repo repository.PhotoRepository) IPhotoService {
+	return &PhotoService{
+		resource: resource.NewRepositoryService[data.Photo](repo, "Photo"),
+	}
+}
+
+// NewPhotoOverlayService crea el servicio de photos que lee del upstream y guarda las escrituras localmente
+func NewPhotoOverlayService(client restclient.IRestClient, upstream config.Upstream, store *repository.OverlayStore, opts ...resource.Option) IPhotoService {
+	return &PhotoService{
+		resource: resource.NewOverlayService[data.Photo](
+			resource.NewResourceService[data.Photo](client, "Photo", upstream.URL(), opts...),
+			repository.NewOverlay[data.Photo](store, "photos"),
+			"Photo",
+		),
+	}
+}
@@ -0,0 +1,194 @@
+package photos
+
+import (
+	"blog-api/app/config"
+	"blog-api/app/mocks"
+	"blog-api/app/pagination"
+	resource "blog-api/app/v1/resource/service"
+	data "blog-api/data"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var upstream = config.Upstream{Root: "https://jsonplaceholder.typicode.com", Path: "photos"}
+
+var baseUrl = upstream.URL()
+
+type MockReader struct {
+	*bytes.Reader
+}
+
+func (r *MockReader) Close() error {
+	return nil
+}
+
+func TestPhotoService_GetPhotos_Success(t *testing.T) {
+	mockResp := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(&MockReader{bytes.NewReader([]byte(`[{"id": 1, "title": "Test Title", "albumId": 10}]`))}),
+	}
+	//mockResp.Body.Read([]byte(`[{"id": 1, "title": "Test Title", "albumId": 10}]`))
+	mockClient := mocks.NewIRestClient(t)
+	mockClient.On("NewRequest", mock.Anything, "GET", "https://jsonplaceholder.typicode.com/photos?albumId=10&id=1&title=Test+Title", mock.Anything, mock.Anything).Return(mockResp, nil)
+	//
+	service := NewPhotoService(mockClient, upstream)
+	photos, _, err := service.GetPhotos(context.Background(), resource.Filter{"title": "Test Title", "albumId": "10", "id": "1"}, pagination.Pagination{})
+	require.NoError(t, err)
+	require.NotNil(t, photos)
+	require.Equal(t, 1, len(*photos))
+}
+
+func TestPhotoService_GetPhotos_ErrorInRequest(t *testing.T) {
+	mockClient := mocks.NewIRestClient(t)
+	mockClient.On("NewRequest", mock.Anything, "GET", "https://jsonplaceholder.typicode.com/photos?albumId=10&id=1&title=Test+Title", mock.Anything, mock.Anything).Return(nil, errors.New("request error"))
+
+	service := NewPhotoService(mockClient, upstream)
+	_, _, err := service.GetPhotos(context.Background(), resource.Filter{"title": "Test Title", "albumId": "10", "id": "1"}, pagination.Pagination{})
+	require.Error(t, err)
+}
+
+func TestPhotoService_GetPhoto_Success(t *testing.T) {
+	// Mock expected photo data
+	mockPhoto := &data.Photo{
+		ID:      1,
+		Title:   "Test Title",
+		AlbumID: 10,
+	}
+	// Mock client response
+	mockResp := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader([]byte(`{"id": 1, "title": "Test Title", "albumId": 10}`))),
+	}
+	mockClient := mocks.NewIRestClient(t)
+	mockClient.On("NewRequest", mock.Anything, "GET", fmt.Sprintf("%s/%d", baseUrl, mockPhoto.ID), mock.Anything, mock.Anything).Return(mockResp, nil)
+	// Create service and call GetPhoto
+	service := NewPhotoService(mockClient, upstream)
+	photo, err := service.GetPhoto(context.Background(), mockPhoto.ID)
+	require.NoError(t, err)
+	require.NotNil(t, photo)
+	require.Equal(t, *mockPhoto, *photo)
+}
+
+func TestCreatePhoto(t *testing.T) {
+	mockClient := mocks.NewIRestClient(t)
+	service := NewPhotoService(mockClient, upstream)
+	photo := data.Photo{
+		ID:      1,
+		Title:   "Test Title",
+		AlbumID: 10,
+	}
+	t.Run("success", func(t *testing.T) {
+		mockClient.On("NewRequest", mock.Anything, "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`{"id": 1, "title": "Test Title", "albumId": 10}`))),
+		}, nil).Once()
+
+		createdPhoto, err := service.CreatePhoto(context.Background(), photo)
+		assert.NoError(t, err)
+		assert.Equal(t, photo, *createdPhoto)
+	})
+	t.Run("error in validation", func(t *testing.T) {
+		invalidPhoto := data.Photo{
+			ID:      1,
+			Title:   "", // Title is required
+			AlbumID: 10,
+		}
+		_, err := service.CreatePhoto(context.Background(), invalidPhoto)
+		assert.Error(t, err)
+	})
+	t.Run("error in request", func(t *testing.T) {
+		mockClient.On("NewRequest", mock.Anything, "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(nil, errors.New("request error")).Once()
+
+		_, err := service.CreatePhoto(context.Background(), photo)
+		assert.Error(t, err)
+	})
+	t.Run("error in response", func(t *testing.T) {
+		mockClient.On("NewRequest", mock.Anything, "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
+			StatusCode: 500,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(``))),
+		}, nil).Once()
+
+		_, err := service.CreatePhoto(context.Background(), photo)
+		assert.Error(t, err)
+	})
+	t.Run("error in decoding", func(t *testing.T) {
+		mockClient.On("NewRequest", mock.Anything, "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`not a valid json`))),
+		}, nil).Once()
+		_, err := service.CreatePhoto(context.Background(), photo)
+		assert.Error(t, err)
+	})
+}
+
+func TestUpdatePhoto(t *testing.T) {
+	mockClient := mocks.NewIRestClient(t)
+	service := NewPhotoService(mockClient, upstream)
+
+	photo := data.Photo{
+		ID:      1,
+		Title:   "Updated Title",
+		AlbumID: 10,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockClient.On("NewRequest", mock.Anything, "PUT", fmt.Sprintf("%s/%d", baseUrl, photo.ID), mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"id": 1, "title": "Updated Title", "albumId": 10}`))),
+		}, nil).Once()
+
+		updatedPhoto, err := service.UpdatePhoto(context.Background(), photo.ID, photo)
+		assert.NoError(t, err)
+		assert.Equal(t, photo, *updatedPhoto)
+	})
+
+	t.Run("error in request", func(t *testing.T) {
+		mockClient.On("NewRequest", mock.Anything, "PUT", fmt.Sprintf("%s/%d", baseUrl, photo.ID), mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(nil, errors.New("request error")).Once()
+
+		_, err := service.UpdatePhoto(context.Background(), photo.ID, photo)
+		assert.Error(t, err)
+	})
+
+	t.Run("error in response", func(t *testing.T) {
+		mockClient.On("NewRequest", mock.Anything, "PUT", fmt.Sprintf("%s/%d", baseUrl, photo.ID), mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
+			StatusCode: 500,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(``))),
+		}, nil).Once()
+
+		_, err := service.UpdatePhoto(context.Background(), photo.ID, photo)
+		assert.Error(t, err)
+	})
+
+	t.Run("error in decoding", func(t *testing.T) {
+		mockClient.On("NewRequest", mock.Anything, "PUT", fmt.Sprintf("%s/%d", baseUrl, photo.ID), mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`not a valid json`))),
+		}, nil).Once()
+
+		_, err := service.UpdatePhoto(context.Background(), photo.ID, photo)
+		assert.Error(t, err)
+	})
+}
+
+func TestDeletePhoto(t *testing.T) {
+	mockClient := mocks.NewIRestClient(t)
+	service := NewPhotoService(mockClient, upstream)
+	photoID := 1
+	t.Run("success", func(t *testing.T) {
+		mockClient.On("NewRequest", mock.Anything, "DELETE", fmt.Sprintf("%s/%d", baseUrl, photoID), mock.Anything, mock.Anything).Return(&http.Response{
+			StatusCode: http.StatusOK,
+		}, nil).Once()
+		err := service.DeletePhoto(context.Background(), photoID)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,76 @@
+package photos
+
+import (
+	"blog-api/app/blob"
+	"blog-api/app/thumbnail"
+	data "blog-api/data"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+)
+
+// extensions son las extensiones de los archivos guardados según su tipo
+var extensions = map[string]string{thumbnail.JPEG: ".jpg", thumbnail.PNG: ".png"}
+
+// Uploader guarda las imágenes subidas y su miniatura y crea la photo que las referencia
+type Uploader struct {
+	photos        IPhotoService
+	store         blob.Store
+	baseURL       string
+	thumbnailSize int
+}
+
+// NewUploader crea el uploader; baseURL es la URL desde la que se sirven los archivos
+// de store, p. ej. /files, y thumbnailSize el lado máximo de las miniaturas
+func NewUploader(photos IPhotoService, store blob.Store, baseURL string, thumbnailSize int) *Uploader {
+	return &Uploader{
+		photos:        photos,
+		store:         store,
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		thumbnailSize: thumbnailSize,
+	}
+}
+
+// Upload guarda content y su miniatura y crea photo con sus URLs. El tipo se detecta
+// del contenido: devuelve thumbnail.ErrUnsupported si no es JPEG ni PNG y
+// thumbnail.ErrTooLarge si la imagen tiene demasiados píxeles. Si la photo no se
+// puede crear, borra los archivos guardados.
+func (u *Uploader) Upload(ctx context.Context, photo data.Photo, content []byte) (*data.Photo, error) {
+	thumb, contentType, err := thumbnail.Generate(content, u.thumbnailSize)
+	if err != nil {
+		return nil, err
+	}
+	name, err := randomName()
+	if err != nil {
+		return nil, err
+	}
+	key := "photos/" + name + extensions[contentType]
+	thumbKey := "photos/" + name + "_thumb" + extensions[contentType]
+	if err := u.store.Put(ctx, key, bytes.NewReader(content)); err != nil {
+		return nil, err
+	}
+	if err := u.store.Put(ctx, thumbKey, bytes.NewReader(thumb)); err != nil {
+		u.store.Delete(context.WithoutCancel(ctx), key)
+		return nil, err
+	}
+	photo.URL = u.baseURL + "/" + key
+	photo.ThumbnailURL = u.baseURL + "/" + thumbKey
+	created, err := u.photos.CreatePhoto(ctx, photo)
+	if err != nil {
+		u.store.Delete(context.WithoutCancel(ctx), key)
+		u.store.Delete(context.WithoutCancel(ctx), thumbKey)
+		return nil, err
+	}
+	return created, nil
+}
+
+// randomName devuelve un nombre de archivo que no se repite ni se puede adivinar
+func randomName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -2,22 +2,41 @@ package posts
 
 import (
 	posts "blog-api/app/v1/posts/service"
+	resource "blog-api/app/v1/resource/handler"
 	"blog-api/data"
-	"encoding/json"
-	"github.com/go-chi/chi/v5"
 	"net/http"
-	"strconv"
 )
 
 // PostHandler maneja las solicitudes relacionadas con posts
 type PostHandler struct {
-	postService posts.IPostService
+	*resource.ResourceHandler[data.Post]
 }
 
 // NewPostHandler crea una nueva instancia del manejador de posts
 func NewPostHandler(postService posts.IPostService) *PostHandler {
 	return &PostHandler{
-		postService: postService,
+		ResourceHandler: resource.NewResourceHandler(resource.Operations[data.Post]{
+			List:   postService.GetPosts,
+			Get:    postService.GetPost,
+			Create: postService.CreatePost,
+			Update: postService.UpdatePost,
+			Patch:  postService.PatchPost,
+			Delete: postService.DeletePost,
+		}, "postID", posts.Filters...),
+	}
+}
+
+// Routes devuelve las rutas de posts para MountResource
+func (ph *PostHandler) Routes() resource.Routes {
+	return resource.Routes{
+		IDParam: ph.IDParam(),
+		List:    ph.GetPosts,
+		Create:  ph.CreatePost,
+		Get:     ph.GetPost,
+		Update:  ph.UpdatePost,
+		Patch:   ph.PatchPost,
+		Delete:  ph.DeletePost,
+		Bulk:    ph.BulkPosts,
 	}
 }
 
@@ -29,18 +48,12 @@ func NewPostHandler(postService posts.IPostService) *PostHandler {
 // @Produce      json
 // @Success      200
 // @Failure      500
+// @Param        page   query int    false "Page number"
+// @Param        limit  query int    false "Page size"
+// @Param        cursor query string false "Opaque cursor from X-Next-Cursor"
 // @Router       ///v1/posts [get] .
 func (ph *PostHandler) GetPosts(w http.ResponseWriter, r *http.Request) {
-	title := r.URL.Query().Get("title")
-	userID, _ := strconv.Atoi(r.URL.Query().Get("userId"))
-	id, _ := strconv.Atoi(r.URL.Query().Get("id"))
-	posts, err := ph.postService.GetPosts(title, userID, id)
-	if err != nil {
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(posts)
+	ph.List(w, r)
 }
 
 // GetPost godoc
@@ -50,25 +63,12 @@ func (ph *PostHandler) GetPosts(w http.ResponseWriter, r *http.Request) {
 // @Accept		 json
 // @Produce      json
 // @Success      200
+// @Success      304
 // @Failure      400
 // @Failure      404
-// @Router       ///v1/post/{postId} [get] .
+// @Router       ///v1/post/{postID} [get] .
 func (ph *PostHandler) GetPost(w http.ResponseWriter, r *http.Request) {
-	postID := chi.URLParam(r, "postID")
-	id, err := strconv.Atoi(postID)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	post, err := ph.postService.GetPost(id)
-	if err != nil {
-		http.Error(w, "Not Found", http.StatusNotFound)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(post)
+	ph.Get(w, r)
 }
 
 // CreatePost GetPost godoc
@@ -82,26 +82,11 @@ func (ph *PostHandler) GetPost(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Success      201
 // @Failure      400
+// @Failure      422
 // @Failure      500
-// @Router       ///v1/post/{postId} [post] .
+// @Router       ///v1/post/{postID} [post] .
 func (ph *PostHandler) CreatePost(w http.ResponseWriter, r *http.Request) {
-	var post data.Post
-	err := json.NewDecoder(r.Body).Decode(&post)
-	if err != nil {
-
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	createdPost, err := ph.postService.CreatePost(post)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(createdPost)
+	ph.Create(w, r)
 }
 
 // UpdatePost godoc
@@ -116,38 +101,19 @@ func (ph *PostHandler) CreatePost(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Success      200
 // @Failure      400
+// @Failure      412
+// @Failure      422
 // @Failure      500
-// @Router       ///v1/post/{postId} [post] .
+// @Router       ///v1/post/{postID} [post] .
 func (ph *PostHandler) UpdatePost(w http.ResponseWriter, r *http.Request) {
-	postID := chi.URLParam(r, "postID")
-	id, err := strconv.Atoi(postID)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	var post data.Post
-	err = json.NewDecoder(r.Body).Decode(&post)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	updatedPost, err := ph.postService.UpdatePost(id, post)
-	if err != nil {
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(updatedPost)
+	ph.Update(w, r)
 }
 
 // PatchPost godoc
 // @Description Handler to patch post
 // @Tags Posts
 // @Description.markdown patch post
-// @Accept		 json
+// @Accept		 json,application/merge-patch+json,application/json-patch+json
 // @UrlParam  		PostID path string true "PostID"
 // @Param  		UserID path string true "UserID"
 // @Param  		Title path string true "Title"
@@ -155,31 +121,13 @@ func (ph *PostHandler) UpdatePost(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Success      200
 // @Failure      400
+// @Failure      412
+// @Failure      415
+// @Failure      422
 // @Failure      500
-// @Router       ///v1/post/{postId} [patch] .
+// @Router       ///v1/post/{postID} [patch] .
 func (ph *PostHandler) PatchPost(w http.ResponseWriter, r *http.Request) {
-	postID := chi.URLParam(r, "postID")
-	id, err := strconv.Atoi(postID)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	var post data.Post
-	err = json.NewDecoder(r.Body).Decode(&post)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	patchedPost, err := ph.postService.UpdatePost(id, post)
-	if err != nil {
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(patchedPost)
+	ph.Patch(w, r)
 }
 
 // DeletePost godoc
@@ -191,21 +139,27 @@ func (ph *PostHandler) PatchPost(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Success      204
 // @Failure      400
+// @Failure      412
+// @Failure      422
 // @Failure      500
-// @Router       ///v1/post/{postId} [patch] .
+// @Router       ///v1/post/{postID} [patch] .
 func (ph *PostHandler) DeletePost(w http.ResponseWriter, r *http.Request) {
-	postID := chi.URLParam(r, "postID")
-	id, err := strconv.Atoi(postID)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	err = ph.postService.DeletePost(id)
-	if err != nil {
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
+	ph.Delete(w, r)
+}
 
-	w.WriteHeader(http.StatusNoContent)
+// BulkPosts godoc
+// @Description Handler to create, update and delete posts in one request
+// @Tags Posts
+// @Description.markdown bulk posts
+// @Accept		 json,application/x-ndjson
+// @Produce      json
+// @Param        atomic query bool false "Apply all operations or none"
+// @Success      207
+// @Failure      400
+// @Failure      413
+// @Failure      415
+// @Failure      500
+// @Router       ///v1/posts/bulk [post] .
+func (ph *PostHandler) BulkPosts(w http.ResponseWriter, r *http.Request) {
+	ph.Bulk(w, r)
 }
@@ -2,11 +2,15 @@ package posts
 
 import (
 	"blog-api/app/mocks"
+	"blog-api/app/pagination"
+	resource "blog-api/app/v1/resource/service"
 	"blog-api/data"
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"net/http"
 	"net/http/httptest"
@@ -20,9 +24,9 @@ func TestGetPosts_Success(t *testing.T) {
 	// Mock expected posts
 	mockPosts := []data.Post{{ID: 1, Title: "My Post", Body: "This is my post", UserID: 1}}
 	// Set mock expectations
-	mockPostService.On("GetPosts", "", 0, 0).Return(&mockPosts, nil)
+	mockPostService.On("GetPosts", mock.Anything, resource.Filter{}, pagination.Pagination{}).Return(&mockPosts, len(mockPosts), nil)
 	// Create handler and request
-	handler := PostHandler{postService: mockPostService}
+	handler := NewPostHandler(mockPostService)
 	req, _ := http.NewRequest("GET", "/posts", nil)
 	// Create mock response recorder
 	mockRecorder := httptest.NewRecorder()
@@ -35,6 +39,25 @@ func TestGetPosts_Success(t *testing.T) {
 	require.Equal(t, string(jsonBytes)+"\n", mockRecorder.Body.String())
 }
 
+func TestGetPosts_Paginated(t *testing.T) {
+	// Mock post service
+	mockPostService := mocks.NewIPostService(t)
+	// Mock expected page
+	mockPosts := []data.Post{{ID: 3, Title: "My Post", Body: "This is my post", UserID: 1}}
+	page := pagination.Pagination{Page: 2, Limit: 2}
+	mockPostService.On("GetPosts", mock.Anything, resource.Filter{}, page).Return(&mockPosts, 5, nil)
+	// Create handler and request through the pagination middleware
+	handler := NewPostHandler(mockPostService)
+	req, _ := http.NewRequest("GET", "/posts?page=2&limit=2", nil)
+	mockRecorder := httptest.NewRecorder()
+	pagination.Paginate(http.HandlerFunc(handler.GetPosts)).ServeHTTP(mockRecorder, req)
+	// Assertions
+	require.Equal(t, http.StatusOK, mockRecorder.Code)
+	require.Equal(t, "5", mockRecorder.Header().Get("X-Total-Count"))
+	require.Contains(t, mockRecorder.Header().Get("Link"), `</posts?limit=2&page=3>; rel="next"`)
+	require.Equal(t, pagination.EncodeCursor(pagination.Pagination{Page: 3, Limit: 2}), mockRecorder.Header().Get("X-Next-Cursor"))
+}
+
 func TestGetPost_Success(t *testing.T) {
 	// Mock post service
 	ctx := chi.NewRouteContext()
@@ -43,9 +66,9 @@ func TestGetPost_Success(t *testing.T) {
 	// Mock expected post
 	mockPost := data.Post{ID: 1, Title: "My Post", Body: "This is my post", UserID: 1}
 	// Set mock expectations
-	mockPostService.On("GetPost", 1).Return(&mockPost, nil)
+	mockPostService.On("GetPost", mock.Anything, 1).Return(&mockPost, nil)
 	// Create handler and request
-	handler := &PostHandler{postService: mockPostService}
+	handler := NewPostHandler(mockPostService)
 	req, _ := http.NewRequest("GET", "/posts/1", nil)
 	ctx.URLParams.Add("postID", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
@@ -68,9 +91,9 @@ func TestCreatePost_Success(t *testing.T) {
 	mockPost := data.Post{Title: "My Post", Body: "This is my post", UserID: 1}
 	mockCreatedPost := data.Post{ID: 1, Title: "My Post", Body: "This is my post", UserID: 1}
 	// Set mock expectations
-	mockPostService.On("CreatePost", mockPost).Return(&mockCreatedPost, nil)
+	mockPostService.On("CreatePost", mock.Anything, mockPost).Return(&mockCreatedPost, nil)
 	// Create handler and request
-	handler := &PostHandler{postService: mockPostService}
+	handler := NewPostHandler(mockPostService)
 	reqBody, err := json.Marshal(mockPost)
 	require.NoError(t, err)
 	req, _ := http.NewRequest(http.MethodPost, "/posts", bytes.NewReader(reqBody))
@@ -95,10 +118,10 @@ func TestUpdatePost_Success(t *testing.T) {
 	mockUpdatedPost := data.Post{ID: 1, Title: "Updated Post", Body: "This is an updated post", UserID: 1}
 
 	// Set mock expectations
-	mockPostService.On("UpdatePost", 1, mockPost).Return(&mockUpdatedPost, nil)
+	mockPostService.On("UpdatePost", mock.Anything, 1, mockPost).Return(&mockUpdatedPost, nil)
 
 	// Create handler and request
-	handler := &PostHandler{postService: mockPostService}
+	handler := NewPostHandler(mockPostService)
 	reqBody, err := json.Marshal(mockPost)
 	require.NoError(t, err)
 	req, _ := http.NewRequest(http.MethodPut, "/posts/1", bytes.NewReader(reqBody))
@@ -124,18 +147,22 @@ func TestPatchPost_Success(t *testing.T) {
 	mockPostService := mocks.NewIPostService(t)
 	defer mockPostService.AssertExpectations(t)
 
-	// Mock expected post with only updated title
-	mockPost := data.Post{Title: "Updated Title"}
+	// Mock current post and the expected one after applying the patch
+	mockCurrentPost := data.Post{ID: 1, Title: "Old Title", Body: "This is my post", UserID: 1}
 	mockPatchedPost := data.Post{ID: 1, Title: "Updated Title", Body: "This is my post", UserID: 1}
 
-	// Set mock expectations
-	mockPostService.On("UpdatePost", 1, mockPost).Return(&mockPatchedPost, nil)
+	// Set mock expectations: the service applies the patch to the current post
+	mockPostService.On("PatchPost", mock.Anything, 1, mock.Anything).Return(
+		func(ctx context.Context, id int, apply func(data.Post) (data.Post, error)) (*data.Post, error) {
+			patched, err := apply(mockCurrentPost)
+			return &patched, err
+		})
 
-	// Create handler and request with only title in the body
-	handler := &PostHandler{postService: mockPostService}
-	reqBody, err := json.Marshal(mockPost)
-	require.NoError(t, err)
+	// Create handler and request with a merge patch of the title
+	handler := NewPostHandler(mockPostService)
+	reqBody := []byte(`{"title": "Updated Title"}`)
 	req, _ := http.NewRequest(http.MethodPatch, "/posts/1", bytes.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
 	ctx := chi.NewRouteContext()
 	ctx.URLParams.Add("postID", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
@@ -159,10 +186,10 @@ func TestDeletePost_Success(t *testing.T) {
 	defer mockPostService.AssertExpectations(t)
 
 	// Set mock expectations
-	mockPostService.On("DeletePost", 1).Return(nil)
+	mockPostService.On("DeletePost", mock.Anything, 1).Return(nil)
 
 	// Create handler and request
-	handler := &PostHandler{postService: mockPostService}
+	handler := NewPostHandler(mockPostService)
 	req, _ := http.NewRequest(http.MethodDelete, "/posts/1", nil)
 	ctx := chi.NewRouteContext()
 	ctx.URLParams.Add("postID", "1")
@@ -177,3 +204,45 @@ func TestDeletePost_Success(t *testing.T) {
 	// Assertions
 	require.Equal(t, http.StatusNoContent, mockRecorder.Code)
 }
+
+func TestUserPosts(t *testing.T) {
+	// Mock post service
+	mockPostService := mocks.NewIPostService(t)
+	user := func(ctx context.Context, id int) error {
+		if id != 1 {
+			return errors.New("User can´t be found. Status Code: 404")
+		}
+		return nil
+	}
+	handler := NewUserPostsHandler(mockPostService, user)
+	r := chi.NewRouter()
+	r.Route("/users/{userID}", func(r chi.Router) {
+		r.Get("/posts", handler.GetUserPosts)
+		r.Post("/posts", handler.CreateUserPost)
+	})
+	t.Run("list", func(t *testing.T) {
+		mockPosts := []data.Post{{ID: 1, Title: "My Post", UserID: 1}}
+		mockPostService.On("GetPosts", mock.Anything, resource.Filter{"userId": "1", "title": "My Post"}, pagination.Pagination{}).Return(&mockPosts, 1, nil).Once()
+		req, _ := http.NewRequest(http.MethodGet, "/users/1/posts?title=My+Post", nil)
+		mockRecorder := httptest.NewRecorder()
+		r.ServeHTTP(mockRecorder, req)
+		require.Equal(t, http.StatusOK, mockRecorder.Code)
+		require.Equal(t, "1", mockRecorder.Header().Get("X-Total-Count"))
+	})
+	t.Run("create", func(t *testing.T) {
+		mockCreatedPost := data.Post{ID: 101, Title: "My Post", UserID: 1}
+		mockPostService.On("CreatePost", mock.Anything, data.Post{Title: "My Post", UserID: 1}).Return(&mockCreatedPost, nil).Once()
+		reqBody, err := json.Marshal(data.Post{Title: "My Post"})
+		require.NoError(t, err)
+		req, _ := http.NewRequest(http.MethodPost, "/users/1/posts", bytes.NewReader(reqBody))
+		mockRecorder := httptest.NewRecorder()
+		r.ServeHTTP(mockRecorder, req)
+		require.Equal(t, http.StatusCreated, mockRecorder.Code)
+	})
+	t.Run("user not found", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/users/2/posts", nil)
+		mockRecorder := httptest.NewRecorder()
+		r.ServeHTTP(mockRecorder, req)
+		require.Equal(t, http.StatusNotFound, mockRecorder.Code)
+	})
+}
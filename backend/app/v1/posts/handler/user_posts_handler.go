@@ -0,0 +1,65 @@
+package posts
+
+import (
+	posts "blog-api/app/v1/posts/service"
+	resource "blog-api/app/v1/resource/handler"
+	"blog-api/data"
+	"net/http"
+)
+
+// UserPostsHandler maneja los posts de un user: /users/{userID}/posts
+type UserPostsHandler struct {
+	*resource.NestedHandler[data.Post]
+}
+
+// NewUserPostsHandler crea el manejador; user comprueba que exista el user
+func NewUserPostsHandler(postService posts.IPostService, user resource.Parent) *UserPostsHandler {
+	handler := resource.NewResourceHandler(resource.Operations[data.Post]{
+		List:   postService.GetPosts,
+		Create: postService.CreatePost,
+	}, "postID", posts.Filters...)
+	return &UserPostsHandler{
+		NestedHandler: resource.NewNestedHandler(handler, user, "userID", "userId", func(item *data.Post, id int) {
+			item.UserID = id
+		}),
+	}
+}
+
+// Mount devuelve la opción de MountResource que registra las rutas bajo /users/{userID}
+func (h *UserPostsHandler) Mount() resource.MountOption {
+	return resource.MountNested("/posts", h.GetUserPosts, h.CreateUserPost)
+}
+
+// GetUserPosts godoc
+// @Description  Handler to get the posts of a user
+// @Tags Posts
+// @Accept		 json
+// @Produce      json
+// @Param        userID path  int    true  "User ID"
+// @Param        page   query int    false "Page number"
+// @Param        limit  query int    false "Page size"
+// @Param        cursor query string false "Opaque cursor from X-Next-Cursor"
+// @Success      200
+// @Failure      400
+// @Failure      404
+// @Failure      500
+// @Router       ///v1/users/{userID}/posts [get] .
+func (h *UserPostsHandler) GetUserPosts(w http.ResponseWriter, r *http.Request) {
+	h.List(w, r)
+}
+
+// CreateUserPost godoc
+// @Description  Handler to create a post of a user; userId is taken from the path
+// @Tags Posts
+// @Accept		 json
+// @Produce      json
+// @Param        userID path  int    true  "User ID"
+// @Success      201
+// @Failure      400
+// @Failure      422
+// @Failure      404
+// @Failure      500
+// @Router       ///v1/users/{userID}/posts [post] .
+func (h *UserPostsHandler) CreateUserPost(w http.ResponseWriter, r *http.Request) {
+	h.Create(w, r)
+}
@@ -1,9 +1,13 @@
 package posts
 
 import (
+	"blog-api/app/config"
 	"blog-api/app/mocks"
+	"blog-api/app/pagination"
+	resource "blog-api/app/v1/resource/service"
 	data "blog-api/data"
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -16,6 +20,10 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+var upstream = config.Upstream{Root: "https://jsonplaceholder.typicode.com", Path: "posts"}
+
+var baseUrl = upstream.URL()
+
 type MockReader struct {
 	*bytes.Reader
 }
@@ -31,10 +39,10 @@ func TestPostService_GetPosts_Success(t *testing.T) {
 	}
 	//mockResp.Body.Read([]byte(`[{"id": 1, "title": "Test Title", "userId": 10}]`))
 	mockClient := mocks.NewIRestClient(t)
-	mockClient.On("NewRequest", "GET", "https://jsonplaceholder.typicode.com/posts?id=1&title=Test+Title&userId=10", mock.Anything, mock.Anything).Return(mockResp, nil)
+	mockClient.On("NewRequest", mock.Anything, "GET", "https://jsonplaceholder.typicode.com/posts?id=1&title=Test+Title&userId=10", mock.Anything, mock.Anything).Return(mockResp, nil)
 	//
-	service := &PostService{restClient: mockClient}
-	posts, err := service.GetPosts("Test Title", 10, 1)
+	service := NewPostService(mockClient, upstream)
+	posts, _, err := service.GetPosts(context.Background(), resource.Filter{"title": "Test Title", "userId": "10", "id": "1"}, pagination.Pagination{})
 	require.NoError(t, err)
 	require.NotNil(t, posts)
 	require.Equal(t, 1, len(*posts))
@@ -42,13 +50,57 @@ func TestPostService_GetPosts_Success(t *testing.T) {
 
 func TestPostService_GetPosts_ErrorInRequest(t *testing.T) {
 	mockClient := mocks.NewIRestClient(t)
-	mockClient.On("NewRequest", "GET", "https://jsonplaceholder.typicode.com/posts?id=1&title=Test+Title&userId=10", mock.Anything, mock.Anything).Return(nil, errors.New("request error"))
+	mockClient.On("NewRequest", mock.Anything, "GET", "https://jsonplaceholder.typicode.com/posts?id=1&title=Test+Title&userId=10", mock.Anything, mock.Anything).Return(nil, errors.New("request error"))
 
-	service := &PostService{restClient: mockClient}
-	_, err := service.GetPosts("Test Title", 10, 1)
+	service := NewPostService(mockClient, upstream)
+	_, _, err := service.GetPosts(context.Background(), resource.Filter{"title": "Test Title", "userId": "10", "id": "1"}, pagination.Pagination{})
 	require.Error(t, err)
 }
 
+func TestPostService_GetPosts_Paginated(t *testing.T) {
+	page := pagination.Pagination{Page: 2, Limit: 1}
+	t.Run("upstream paginates", func(t *testing.T) {
+		mockResp := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"X-Total-Count": {"100"}},
+			Body:       io.NopCloser(bytes.NewReader([]byte(`[{"id": 2, "title": "Second", "userId": 10}]`))),
+		}
+		mockClient := mocks.NewIRestClient(t)
+		mockClient.On("NewRequest", mock.Anything, "GET", fmt.Sprintf("%s?_limit=1&_page=2", baseUrl), mock.Anything, mock.Anything).Return(mockResp, nil)
+		service := NewPostService(mockClient, upstream)
+		posts, total, err := service.GetPosts(context.Background(), resource.Filter{}, page)
+		require.NoError(t, err)
+		require.Equal(t, 100, total)
+		require.Equal(t, []data.Post{{ID: 2, Title: "Second", UserID: 10}}, *posts)
+	})
+	t.Run("paginated locally", func(t *testing.T) {
+		mockResp := &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`[{"id": 1}, {"id": 2}, {"id": 3}]`))),
+		}
+		mockClient := mocks.NewIRestClient(t)
+		mockClient.On("NewRequest", mock.Anything, "GET", fmt.Sprintf("%s?_limit=1&_page=2", baseUrl), mock.Anything, mock.Anything).Return(mockResp, nil)
+		service := NewPostService(mockClient, upstream)
+		posts, total, err := service.GetPosts(context.Background(), resource.Filter{}, page)
+		require.NoError(t, err)
+		require.Equal(t, 3, total)
+		require.Equal(t, []data.Post{{ID: 2}}, *posts)
+	})
+	t.Run("upstream paginates without total", func(t *testing.T) {
+		mockResp := &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`[{"id": 2}]`))),
+		}
+		mockClient := mocks.NewIRestClient(t)
+		mockClient.On("NewRequest", mock.Anything, "GET", fmt.Sprintf("%s?_limit=1&_page=2", baseUrl), mock.Anything, mock.Anything).Return(mockResp, nil)
+		service := NewPostService(mockClient, upstream)
+		posts, total, err := service.GetPosts(context.Background(), resource.Filter{}, page)
+		require.NoError(t, err)
+		require.Equal(t, 2, total)
+		require.Equal(t, []data.Post{{ID: 2}}, *posts)
+	})
+}
+
 func TestPostService_GetPost_Success(t *testing.T) {
 	// Mock expected post data
 	mockPost := &data.Post{
@@ -62,10 +114,10 @@ func TestPostService_GetPost_Success(t *testing.T) {
 		Body:       io.NopCloser(bytes.NewReader([]byte(`{"id": 1, "title": "Test Title", "userId": 10}`))),
 	}
 	mockClient := mocks.NewIRestClient(t)
-	mockClient.On("NewRequest", "GET", fmt.Sprintf("%s/%d", baseUrl, mockPost.ID), mock.Anything, mock.Anything).Return(mockResp, nil)
+	mockClient.On("NewRequest", mock.Anything, "GET", fmt.Sprintf("%s/%d", baseUrl, mockPost.ID), mock.Anything, mock.Anything).Return(mockResp, nil)
 	// Create service and call GetPost
-	service := &PostService{restClient: mockClient}
-	post, err := service.GetPost(mockPost.ID)
+	service := NewPostService(mockClient, upstream)
+	post, err := service.GetPost(context.Background(), mockPost.ID)
 	require.NoError(t, err)
 	require.NotNil(t, post)
 	require.Equal(t, *mockPost, *post)
@@ -73,19 +125,19 @@ func TestPostService_GetPost_Success(t *testing.T) {
 
 func TestCreatePost(t *testing.T) {
 	mockClient := mocks.NewIRestClient(t)
-	service := &PostService{restClient: mockClient}
+	service := NewPostService(mockClient, upstream)
 	post := data.Post{
 		ID:     1,
 		Title:  "Test Title",
 		UserID: 10,
 	}
 	t.Run("success", func(t *testing.T) {
-		mockClient.On("NewRequest", "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: http.StatusCreated,
 			Body:       io.NopCloser(bytes.NewReader([]byte(`{"id": 1, "title": "Test Title", "userId": 10}`))),
-		}, nil)
+		}, nil).Once()
 
-		createdPost, err := service.CreatePost(post)
+		createdPost, err := service.CreatePost(context.Background(), post)
 		assert.NoError(t, err)
 		assert.Equal(t, post, *createdPost)
 	})
@@ -95,37 +147,37 @@ func TestCreatePost(t *testing.T) {
 			Title:  "", // Title is required
 			UserID: 10,
 		}
-		_, err := service.CreatePost(invalidPost)
+		_, err := service.CreatePost(context.Background(), invalidPost)
 		assert.Error(t, err)
 	})
 	t.Run("error in request", func(t *testing.T) {
-		mockClient.On("NewRequest", "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(nil, errors.New("request error"))
+		mockClient.On("NewRequest", mock.Anything, "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(nil, errors.New("request error")).Once()
 
-		_, err := service.CreatePost(post)
+		_, err := service.CreatePost(context.Background(), post)
 		assert.Error(t, err)
 	})
 	t.Run("error in response", func(t *testing.T) {
-		mockClient.On("NewRequest", "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: 500,
 			Body:       ioutil.NopCloser(bytes.NewReader([]byte(``))),
-		}, nil)
+		}, nil).Once()
 
-		_, err := service.CreatePost(post)
+		_, err := service.CreatePost(context.Background(), post)
 		assert.Error(t, err)
 	})
 	t.Run("error in decoding", func(t *testing.T) {
-		mockClient.On("NewRequest", "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: http.StatusCreated,
 			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`not a valid json`))),
-		}, nil)
-		_, err := service.CreatePost(post)
+		}, nil).Once()
+		_, err := service.CreatePost(context.Background(), post)
 		assert.Error(t, err)
 	})
 }
 
 func TestUpdatePost(t *testing.T) {
 	mockClient := mocks.NewIRestClient(t)
-	service := &PostService{restClient: mockClient}
+	service := NewPostService(mockClient, upstream)
 
 	post := data.Post{
 		ID:     1,
@@ -134,53 +186,53 @@ func TestUpdatePost(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mockClient.On("NewRequest", "PUT", fmt.Sprintf("%s/%d", baseUrl, post.ID), mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "PUT", fmt.Sprintf("%s/%d", baseUrl, post.ID), mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: http.StatusOK,
 			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"id": 1, "title": "Updated Title", "userId": 10}`))),
-		}, nil)
+		}, nil).Once()
 
-		updatedPost, err := service.UpdatePost(post.ID, post)
+		updatedPost, err := service.UpdatePost(context.Background(), post.ID, post)
 		assert.NoError(t, err)
 		assert.Equal(t, post, *updatedPost)
 	})
 
 	t.Run("error in request", func(t *testing.T) {
-		mockClient.On("NewRequest", "PUT", fmt.Sprintf("%s/%d", baseUrl, post.ID), mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(nil, errors.New("request error"))
+		mockClient.On("NewRequest", mock.Anything, "PUT", fmt.Sprintf("%s/%d", baseUrl, post.ID), mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(nil, errors.New("request error")).Once()
 
-		_, err := service.UpdatePost(post.ID, post)
+		_, err := service.UpdatePost(context.Background(), post.ID, post)
 		assert.Error(t, err)
 	})
 
 	t.Run("error in response", func(t *testing.T) {
-		mockClient.On("NewRequest", "PUT", fmt.Sprintf("%s/%d", baseUrl, post.ID), mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "PUT", fmt.Sprintf("%s/%d", baseUrl, post.ID), mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: 500,
 			Body:       ioutil.NopCloser(bytes.NewReader([]byte(``))),
-		}, nil)
+		}, nil).Once()
 
-		_, err := service.UpdatePost(post.ID, post)
+		_, err := service.UpdatePost(context.Background(), post.ID, post)
 		assert.Error(t, err)
 	})
 
 	t.Run("error in decoding", func(t *testing.T) {
-		mockClient.On("NewRequest", "PUT", fmt.Sprintf("%s/%d", baseUrl, post.ID), mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "PUT", fmt.Sprintf("%s/%d", baseUrl, post.ID), mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: http.StatusOK,
 			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`not a valid json`))),
-		}, nil)
+		}, nil).Once()
 
-		_, err := service.UpdatePost(post.ID, post)
+		_, err := service.UpdatePost(context.Background(), post.ID, post)
 		assert.Error(t, err)
 	})
 }
 
 func TestDeletePost(t *testing.T) {
 	mockClient := mocks.NewIRestClient(t)
-	service := &PostService{restClient: mockClient}
+	service := NewPostService(mockClient, upstream)
 	postID := 1
 	t.Run("success", func(t *testing.T) {
-		mockClient.On("NewRequest", "DELETE", fmt.Sprintf("%s/%d", baseUrl, postID), mock.Anything, mock.Anything).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "DELETE", fmt.Sprintf("%s/%d", baseUrl, postID), mock.Anything, mock.Anything).Return(&http.Response{
 			StatusCode: http.StatusOK,
-		}, nil)
-		err := service.DeletePost(postID)
+		}, nil).Once()
+		err := service.DeletePost(context.Background(), postID)
 		assert.NoError(t, err)
 	})
 }
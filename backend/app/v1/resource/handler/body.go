@@ -0,0 +1,23 @@
+package resource
+
+import (
+	apperrors "blog-api/app/errors"
+	"blog-api/app/validation"
+	"encoding/json"
+	"net/http"
+)
+
+// DecodeJSON decodifica el cuerpo de la solicitud en v. Si no puede, responde 400 con
+// el detalle del error, incluido el campo cuando un valor no es del tipo esperado.
+func DecodeJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err == nil {
+		return true
+	}
+	if typeErr := validation.TypeError(apperrors.ErrBadRequest, err); typeErr != nil {
+		apperrors.Write(w, r, typeErr, nil)
+		return false
+	}
+	apperrors.Write(w, r, apperrors.New(apperrors.ErrBadRequest, "request body must be a valid JSON object"), nil)
+	return false
+}
@@ -0,0 +1,143 @@
+package resource
+
+import (
+	"blog-api/app/i18n"
+	"blog-api/app/validation"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+type account struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name" validate:"required,max=5"`
+	Email string `json:"email" validate:"omitempty,email"`
+	Age   int    `json:"age"`
+}
+
+func newAccountRouter() *chi.Mux {
+	ops := Operations[account]{
+		Create: func(ctx context.Context, item account) (*account, error) {
+			if err := validation.Struct(item); err != nil {
+				return nil, err
+			}
+			item.ID = 1
+			return &item, nil
+		},
+		Patch: func(ctx context.Context, id int, apply func(current account) (account, error)) (*account, error) {
+			item, err := apply(account{ID: id, Name: "ana"})
+			if err != nil {
+				return nil, err
+			}
+			if err := validation.Struct(item); err != nil {
+				return nil, err
+			}
+			return &item, nil
+		},
+	}
+	r := chi.NewRouter()
+	MountResource(r, "/accounts", NewResourceHandler(ops, "accountID").Routes())
+	return r
+}
+
+func TestResourceHandler_Validation(t *testing.T) {
+	r := newAccountRouter()
+	mockRecorder := serve(r, http.MethodPost, "/accounts", account{Name: "toolong", Email: "nope"})
+	require.Equal(t, http.StatusUnprocessableEntity, mockRecorder.Code)
+	require.Equal(t, "application/problem+json", mockRecorder.Header().Get("Content-Type"))
+	require.JSONEq(t, `{
+		"type": "/problems/validation_failed",
+		"title": "Unprocessable Entity",
+		"status": 422,
+		"detail": "validation failed: name must be at most 5 characters long; email must be a valid email address",
+		"instance": "/accounts",
+		"code": "validation_failed",
+		"errors": [
+			{"field": "name", "rule": "max", "param": "5", "message": "name must be at most 5 characters long"},
+			{"field": "email", "rule": "email", "message": "email must be a valid email address"}
+		]
+	}`, mockRecorder.Body.String())
+
+	// En un PATCH se valida el recurso que resulta de aplicar el patch
+	require.Equal(t, http.StatusOK, serve(r, http.MethodPatch, "/accounts/1", map[string]any{"email": "ana@example.com"}).Code)
+	require.Equal(t, http.StatusUnprocessableEntity, serve(r, http.MethodPatch, "/accounts/1", map[string]any{"email": "nope"}).Code)
+	require.Equal(t, http.StatusUnprocessableEntity, serve(r, http.MethodPatch, "/accounts/1", map[string]any{"name": nil}).Code)
+}
+
+func TestDecodeJSON(t *testing.T) {
+	r := newAccountRouter()
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"wrong type", `{"name": "ana", "age": "ten"}`, `{
+			"type": "/problems/bad_request",
+			"title": "Bad Request",
+			"status": 400,
+			"detail": "age must be a number",
+			"instance": "/accounts",
+			"code": "bad_request",
+			"errors": [{"field": "age", "rule": "type", "param": "number", "message": "age must be a number"}]
+		}`},
+		{"malformed", `{"name": `, `{
+			"type": "/problems/bad_request",
+			"title": "Bad Request",
+			"status": 400,
+			"detail": "request body must be a valid JSON object",
+			"instance": "/accounts",
+			"code": "bad_request"
+		}`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRecorder := httptest.NewRecorder()
+			r.ServeHTTP(mockRecorder, httptest.NewRequest(http.MethodPost, "/accounts", strings.NewReader(tc.body)))
+			require.Equal(t, http.StatusBadRequest, mockRecorder.Code)
+			require.JSONEq(t, tc.want, mockRecorder.Body.String())
+		})
+	}
+}
+
+func TestResourceHandler_Localized(t *testing.T) {
+	r := i18n.Middleware(newAccountRouter())
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"validation", `{"name": "toolong"}`, `{
+			"type": "/problems/validation_failed",
+			"title": "Entidad no procesable",
+			"status": 422,
+			"detail": "validación fallida: name debe tener como máximo 5 caracteres",
+			"instance": "/accounts",
+			"code": "validation_failed",
+			"errors": [{"field": "name", "rule": "max", "param": "5", "message": "name debe tener como máximo 5 caracteres"}]
+		}`},
+		{"wrong type", `{"name": "ana", "age": "ten"}`, `{
+			"type": "/problems/bad_request",
+			"title": "Solicitud incorrecta",
+			"status": 400,
+			"detail": "age debe ser de tipo number",
+			"instance": "/accounts",
+			"code": "bad_request",
+			"errors": [{"field": "age", "rule": "type", "param": "number", "message": "age debe ser de tipo number"}]
+		}`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/accounts", strings.NewReader(tc.body))
+			req.Header.Set("Accept-Language", "es-ES,es;q=0.9,en;q=0.5")
+			mockRecorder := httptest.NewRecorder()
+			r.ServeHTTP(mockRecorder, req)
+			require.Equal(t, "es", mockRecorder.Header().Get("Content-Language"))
+			require.JSONEq(t, tc.want, mockRecorder.Body.String())
+		})
+	}
+}
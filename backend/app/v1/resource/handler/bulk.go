@@ -0,0 +1,321 @@
+package resource
+
+import (
+	"blog-api/app/conditional"
+	apperrors "blog-api/app/errors"
+	"blog-api/app/validation"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// NDJSON es el tipo de un cuerpo con un valor JSON por línea, p. ej. un archivo a importar
+const NDJSON = "application/x-ndjson"
+
+// Operaciones de Bulk
+const (
+	BulkCreate = "create"
+	BulkUpdate = "update"
+	BulkDelete = "delete"
+)
+
+// Valores por defecto de BulkOptions
+const (
+	DefaultBulkMaxItems    = 1000
+	DefaultBulkMaxBody     = 16 << 20
+	DefaultBulkConcurrency = 4
+)
+
+// Atomic ejecuta fn en una transacción: si fn devuelve un error no se guarda ninguna de
+// las escrituras que hizo con su ctx
+type Atomic func(ctx context.Context, fn func(ctx context.Context) error) error
+
+// BulkOptions configura Bulk. Los valores en cero usan los valores por defecto.
+type BulkOptions struct {
+	// MaxItems es la cantidad máxima de operaciones de una solicitud
+	MaxItems int
+	// MaxBody es el tamaño máximo del cuerpo en bytes
+	MaxBody int64
+	// Concurrency es la cantidad de operaciones que se aplican a la vez cuando no son
+	// atómicas, y así la de solicitudes simultáneas al upstream
+	Concurrency int
+	// Atomic habilita ?atomic=true; es nil si el almacenamiento no tiene transacciones
+	Atomic Atomic
+}
+
+func (o BulkOptions) withDefaults() BulkOptions {
+	if o.MaxItems <= 0 {
+		o.MaxItems = DefaultBulkMaxItems
+	}
+	if o.MaxBody <= 0 {
+		o.MaxBody = DefaultBulkMaxBody
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = DefaultBulkConcurrency
+	}
+	return o
+}
+
+// BulkOperation es una operación de Bulk: create con item, update con id e item o delete
+// con id. IfMatch condiciona update y delete como el header If-Match.
+type BulkOperation struct {
+	Op      string          `json:"op"`
+	ID      *int            `json:"id,omitempty"`
+	IfMatch string          `json:"ifMatch,omitempty"`
+	Item    json.RawMessage `json:"item,omitempty"`
+}
+
+// BulkResult es el resultado de una operación: el status que tendría la solicitud
+// individual y el elemento con su ETag, o el error
+type BulkResult struct {
+	Index  int                `json:"index"`
+	Op     string             `json:"op"`
+	ID     int                `json:"id,omitempty"`
+	Status int                `json:"status"`
+	ETag   string             `json:"etag,omitempty"`
+	Item   any                `json:"item,omitempty"`
+	Error  *apperrors.Problem `json:"error,omitempty"`
+}
+
+// BulkResponse es el cuerpo de la respuesta 207 de Bulk
+type BulkResponse struct {
+	Atomic    bool         `json:"atomic"`
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+	Results   []BulkResult `json:"results"`
+}
+
+// errRollback deshace la transacción de un bulk atómico cuando falla una operación
+var errRollback = errors.New("bulk operation failed")
+
+// WithBulk configura Bulk: los límites, la concurrencia y las transacciones
+func (h *ResourceHandler[T]) WithBulk(opts BulkOptions) *ResourceHandler[T] {
+	h.bulk = opts.withDefaults()
+	return h
+}
+
+// Bulk aplica varias altas, reemplazos y bajas en una solicitud. El cuerpo es un array
+// de BulkOperation o, con Content-Type application/x-ndjson, una operación por línea.
+// Responde 207 con el resultado de cada operación en el orden recibido.
+//
+// Por defecto cada operación se aplica por separado, hasta Concurrency a la vez, y una
+// que falla no afecta a las demás. Con ?atomic=true se aplican en orden dentro de una
+// transacción: si una falla no se guarda ninguna y las demás responden 424.
+func (h *ResourceHandler[T]) Bulk(w http.ResponseWriter, r *http.Request) {
+	atomic := false
+	if value := r.URL.Query().Get("atomic"); value != "" {
+		var err error
+		if atomic, err = strconv.ParseBool(value); err != nil {
+			apperrors.Write(w, r, apperrors.Newf(apperrors.ErrBadRequest, "invalid atomic %q", value), nil)
+			return
+		}
+	}
+	if atomic && h.bulk.Atomic == nil {
+		apperrors.Write(w, r, apperrors.New(apperrors.ErrBadRequest, "atomic bulk requests need a local database"), nil)
+		return
+	}
+	operations, err := h.operations(w, r)
+	if err != nil {
+		Fail(w, r, err, apperrors.ErrBadRequest)
+		return
+	}
+	results := make([]BulkResult, len(operations))
+	if atomic {
+		if err := h.atomic(r, operations, results); err != nil {
+			Fail(w, r, err, apperrors.ErrInternal)
+			return
+		}
+	} else {
+		h.concurrent(r, operations, results)
+	}
+	response := BulkResponse{Atomic: atomic, Results: results}
+	for _, result := range results {
+		if result.Error != nil {
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+	}
+	WriteJSON(w, http.StatusMultiStatus, response)
+}
+
+// operations lee las operaciones del cuerpo según su Content-Type
+func (h *ResourceHandler[T]) operations(w http.ResponseWriter, r *http.Request) ([]BulkOperation, error) {
+	mediaType := "application/json"
+	if value := r.Header.Get("Content-Type"); value != "" {
+		var err error
+		if mediaType, _, err = mime.ParseMediaType(value); err != nil {
+			mediaType = value
+		}
+	}
+	if mediaType != "application/json" && mediaType != NDJSON {
+		return nil, apperrors.Newf(apperrors.ErrUnsupportedMediaType, "%s is not a bulk type, use application/json or %s", mediaType, NDJSON)
+	}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.bulk.MaxBody))
+	if mediaType == "application/json" {
+		if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+			return nil, h.readError(err, apperrors.New(apperrors.ErrBadRequest, "request body must be a JSON array of operations"))
+		}
+	}
+	var operations []BulkOperation
+	for decoder.More() {
+		if len(operations) == h.bulk.MaxItems {
+			return nil, apperrors.Newf(apperrors.ErrPayloadTooLarge, "a bulk request can have at most %d operations", h.bulk.MaxItems)
+		}
+		var op BulkOperation
+		if err := decoder.Decode(&op); err != nil {
+			return nil, h.readError(err, apperrors.Newf(apperrors.ErrBadRequest, "operation %d must be a valid JSON object", len(operations)))
+		}
+		operations = append(operations, op)
+	}
+	if mediaType == "application/json" {
+		if token, err := decoder.Token(); err != nil || token != json.Delim(']') {
+			return nil, h.readError(err, apperrors.New(apperrors.ErrBadRequest, "request body must be a JSON array of operations"))
+		}
+	}
+	// Después de las operaciones solo puede haber espacios
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, h.readError(err, apperrors.New(apperrors.ErrBadRequest, "request body has data after the operations"))
+	}
+	if len(operations) == 0 {
+		return nil, apperrors.New(apperrors.ErrBadRequest, "a bulk request needs at least one operation")
+	}
+	return operations, nil
+}
+
+// readError devuelve invalid, o 413 si el cuerpo superó MaxBody
+func (h *ResourceHandler[T]) readError(err error, invalid error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return apperrors.Newf(apperrors.ErrPayloadTooLarge, "request body is larger than %d bytes", h.bulk.MaxBody)
+	}
+	return invalid
+}
+
+// concurrent aplica cada operación por separado, hasta Concurrency a la vez
+func (h *ResourceHandler[T]) concurrent(r *http.Request, operations []BulkOperation, results []BulkResult) {
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, h.bulk.Concurrency)
+	for i, op := range operations {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(i int, op BulkOperation) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			results[i] = h.result(r.Context(), r, i, op)
+		}(i, op)
+	}
+	wg.Wait()
+}
+
+// atomic aplica las operaciones en orden en una transacción. Si una falla la transacción
+// se deshace y las demás quedan como no aplicadas. Devuelve un error solo si no se pudo
+// abrir o confirmar la transacción.
+func (h *ResourceHandler[T]) atomic(r *http.Request, operations []BulkOperation, results []BulkResult) error {
+	failed := -1
+	err := h.bulk.Atomic(r.Context(), func(ctx context.Context) error {
+		for i, op := range operations {
+			results[i] = h.result(ctx, r, i, op)
+			if results[i].Error != nil {
+				failed = i
+				return errRollback
+			}
+		}
+		return nil
+	})
+	if failed < 0 {
+		return err
+	}
+	for i, op := range operations {
+		if i == failed {
+			continue
+		}
+		results[i] = newBulkResult(i, op)
+		results[i].Error = apperrors.NewProblem(r, apperrors.Newf(apperrors.ErrFailedDependency, "not applied because operation %d failed", failed), nil)
+		results[i].Status = results[i].Error.Status
+	}
+	return nil
+}
+
+// result aplica una operación con ctx y arma su resultado
+func (h *ResourceHandler[T]) result(ctx context.Context, r *http.Request, i int, op BulkOperation) BulkResult {
+	result := newBulkResult(i, op)
+	status, item, err := h.apply(ctx, op)
+	if err != nil {
+		err, _ = unavailable(err)
+		result.Error = apperrors.NewProblem(r, err, apperrors.ErrInternal)
+		result.Status = result.Error.Status
+		if result.Status >= http.StatusInternalServerError {
+			slog.Error("bulk operation failed", "path", r.URL.Path, "requestId", result.Error.RequestID, "index", i, "op", op.Op, "error", err)
+		}
+		return result
+	}
+	result.Status = status
+	if item != nil {
+		result.Item = item
+		result.ETag, _ = conditional.ETag(item)
+	}
+	return result
+}
+
+func newBulkResult(i int, op BulkOperation) BulkResult {
+	result := BulkResult{Index: i, Op: op.Op}
+	if op.ID != nil {
+		result.ID = *op.ID
+	}
+	return result
+}
+
+// apply aplica una operación con las mismas funciones del servicio que las rutas
+// individuales y devuelve el status y el elemento que responderían
+func (h *ResourceHandler[T]) apply(ctx context.Context, op BulkOperation) (int, any, error) {
+	switch op.Op {
+	case BulkCreate:
+		item, err := bulkItem[T](op)
+		if err != nil {
+			return 0, nil, err
+		}
+		created, err := h.ops.Create(ctx, item)
+		return http.StatusCreated, created, err
+	case BulkUpdate:
+		if op.ID == nil {
+			return 0, nil, apperrors.Newf(apperrors.ErrBadRequest, "%s needs an id", op.Op)
+		}
+		item, err := bulkItem[T](op)
+		if err != nil {
+			return 0, nil, err
+		}
+		updated, err := h.ops.Update(conditional.NewContext(ctx, op.IfMatch), *op.ID, item)
+		return http.StatusOK, updated, err
+	case BulkDelete:
+		if op.ID == nil {
+			return 0, nil, apperrors.Newf(apperrors.ErrBadRequest, "%s needs an id", op.Op)
+		}
+		return http.StatusNoContent, nil, h.ops.Delete(conditional.NewContext(ctx, op.IfMatch), *op.ID)
+	default:
+		return 0, nil, apperrors.Newf(apperrors.ErrBadRequest, "unknown op %q, use create, update or delete", op.Op)
+	}
+}
+
+// bulkItem decodifica el elemento de una operación como DecodeJSON decodifica el cuerpo
+func bulkItem[T any](op BulkOperation) (T, error) {
+	var item T
+	if len(op.Item) == 0 {
+		return item, apperrors.Newf(apperrors.ErrBadRequest, "%s needs an item", op.Op)
+	}
+	if err := json.Unmarshal(op.Item, &item); err != nil {
+		if typeErr := validation.TypeError(apperrors.ErrBadRequest, err); typeErr != nil {
+			return item, typeErr
+		}
+		return item, apperrors.New(apperrors.ErrBadRequest, "item must be a valid JSON object")
+	}
+	return item, nil
+}
@@ -0,0 +1,216 @@
+package resource
+
+import (
+	"blog-api/app/conditional"
+	"blog-api/app/pagination"
+	"blog-api/app/repository"
+	resource "blog-api/app/v1/resource/service"
+	"blog-api/data"
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func newBulkRouter(opts BulkOptions) (*chi.Mux, map[int]widget) {
+	store := map[int]widget{1: {ID: 1, Name: "a"}, 2: {ID: 2, Name: "b"}, 3: {ID: 3, Name: "a"}}
+	// Las operaciones del store de prueba no son concurrentes
+	opts.Concurrency = 1
+	handler := NewResourceHandler(widgetOperations(store), "widgetID").WithBulk(opts)
+	r := chi.NewRouter()
+	MountResource(r, "/widgets", handler.Routes())
+	return r, store
+}
+
+func serveBulk(r http.Handler, target string, contentType string, body string) (*httptest.ResponseRecorder, BulkResponse) {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	mockRecorder := httptest.NewRecorder()
+	r.ServeHTTP(mockRecorder, req)
+	var response BulkResponse
+	json.Unmarshal(mockRecorder.Body.Bytes(), &response)
+	return mockRecorder, response
+}
+
+func statuses(response BulkResponse) []int {
+	result := make([]int, len(response.Results))
+	for i, item := range response.Results {
+		result[i] = item.Status
+	}
+	return result
+}
+
+func TestResourceHandler_Bulk(t *testing.T) {
+	stale := conditional.Tag([]byte(`{"id":2,"name":"old"}`))
+	operations := []string{
+		`{"op": "create", "item": {"name": "c"}}`,
+		`{"op": "update", "id": 1, "item": {"name": "x"}}`,
+		`{"op": "update", "id": 2, "ifMatch": ` + strconv.Quote(stale) + `, "item": {"name": "y"}}`,
+		`{"op": "delete", "id": 3}`,
+		`{"op": "create", "item": {"name": 5}}`,
+		`{"op": "delete"}`,
+		`{"op": "rename", "id": 1}`,
+	}
+	bodies := map[string]string{
+		"application/json": "[" + strings.Join(operations, ",") + "]",
+		NDJSON:             strings.Join(operations, "\n") + "\n",
+	}
+	for contentType, body := range bodies {
+		t.Run(contentType, func(t *testing.T) {
+			r, store := newBulkRouter(BulkOptions{})
+			mockRecorder, response := serveBulk(r, "/widgets/bulk", contentType, body)
+			require.Equal(t, http.StatusMultiStatus, mockRecorder.Code)
+			require.Equal(t, "no-store", mockRecorder.Header().Get("Cache-Control"))
+			require.Equal(t, []int{201, 200, 412, 204, 400, 400, 400}, statuses(response))
+			require.Equal(t, 3, response.Succeeded)
+			require.Equal(t, 4, response.Failed)
+
+			created := response.Results[0]
+			require.Equal(t, map[string]any{"id": float64(4), "name": "c"}, created.Item)
+			require.Equal(t, conditional.Tag([]byte(`{"id":4,"name":"c"}`)), created.ETag)
+			require.Equal(t, 1, response.Results[1].ID)
+			require.Equal(t, "precondition_failed", string(response.Results[2].Error.Code))
+			require.Equal(t, "name", response.Results[4].Error.Errors[0].Field)
+			require.Equal(t, "delete needs an id", response.Results[5].Error.Detail)
+			require.Equal(t, map[int]widget{1: {ID: 1, Name: "x"}, 2: {ID: 2, Name: "b"}, 4: {ID: 4, Name: "c"}}, store)
+		})
+	}
+}
+
+func TestResourceHandler_Bulk_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		contentType string
+		body        string
+		status      int
+	}{
+		{"invalid atomic", "/widgets/bulk?atomic=maybe", "application/json", `[{"op": "delete", "id": 1}]`, http.StatusBadRequest},
+		{"atomic without transactions", "/widgets/bulk?atomic=true", "application/json", `[{"op": "delete", "id": 1}]`, http.StatusBadRequest},
+		{"unsupported type", "/widgets/bulk", "text/plain", `[]`, http.StatusUnsupportedMediaType},
+		{"not an array", "/widgets/bulk", "application/json", `{"op": "delete", "id": 1}`, http.StatusBadRequest},
+		{"invalid operation", "/widgets/bulk", "application/json", `[{"op": "delete", "id": "1"}]`, http.StatusBadRequest},
+		{"unterminated array", "/widgets/bulk", "application/json", `[{"op": "delete", "id": 1}`, http.StatusBadRequest},
+		{"data after the array", "/widgets/bulk", "application/json", `[{"op": "delete", "id": 1}] []`, http.StatusBadRequest},
+		{"empty", "/widgets/bulk", NDJSON, "\n", http.StatusBadRequest},
+		{"too many operations", "/widgets/bulk", NDJSON, strings.Repeat(`{"op": "delete", "id": 1}`+"\n", 3), http.StatusRequestEntityTooLarge},
+		{"body too large", "/widgets/bulk", "application/json", `[{"op": "create", "item": {"name": "` + strings.Repeat("x", 100) + `"}}]`, http.StatusRequestEntityTooLarge},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, store := newBulkRouter(BulkOptions{MaxItems: 2, MaxBody: 64})
+			mockRecorder, _ := serveBulk(r, tc.target, tc.contentType, tc.body)
+			require.Equal(t, tc.status, mockRecorder.Code, mockRecorder.Body.String())
+			require.Len(t, store, 3, "no operation is applied")
+		})
+	}
+}
+
+func TestResourceHandler_Bulk_Atomic(t *testing.T) {
+	var store map[int]widget
+	// La transacción de prueba guarda una copia del store y la restaura si fn falla
+	transaction := func(ctx context.Context, fn func(ctx context.Context) error) error {
+		saved := maps.Clone(store)
+		if err := fn(ctx); err != nil {
+			clear(store)
+			maps.Copy(store, saved)
+			return err
+		}
+		return nil
+	}
+	r, store := newBulkRouter(BulkOptions{Atomic: transaction})
+
+	body := `[{"op": "update", "id": 1, "item": {"name": "x"}}, {"op": "create", "item": {"name": true}}, {"op": "delete", "id": 2}]`
+	mockRecorder, response := serveBulk(r, "/widgets/bulk?atomic=true", "application/json", body)
+	require.Equal(t, http.StatusMultiStatus, mockRecorder.Code)
+	require.True(t, response.Atomic)
+	require.Equal(t, []int{424, 400, 424}, statuses(response))
+	require.Equal(t, "failed_dependency", string(response.Results[0].Error.Code))
+	require.Equal(t, "not applied because operation 1 failed", response.Results[0].Error.Detail)
+	require.Nil(t, response.Results[0].Item)
+	require.Equal(t, 3, response.Failed)
+	require.Equal(t, widget{ID: 1, Name: "a"}, store[1], "the update is rolled back")
+
+	body = `[{"op": "update", "id": 1, "item": {"name": "x"}}, {"op": "delete", "id": 2}]`
+	_, response = serveBulk(r, "/widgets/bulk?atomic=true", "application/json", body)
+	require.Equal(t, []int{200, 204}, statuses(response))
+	require.Equal(t, map[int]widget{1: {ID: 1, Name: "x"}, 3: {ID: 3, Name: "a"}}, store)
+}
+
+func TestResourceHandler_Bulk_Concurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	var mu sync.Mutex
+	created := 0
+	handler := NewResourceHandler(Operations[widget]{
+		Create: func(ctx context.Context, item widget) (*widget, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				current := peak.Load()
+				if n <= current || peak.CompareAndSwap(current, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			mu.Lock()
+			defer mu.Unlock()
+			created++
+			item.ID = created
+			return &item, nil
+		},
+	}, "widgetID").WithBulk(BulkOptions{Concurrency: 3})
+	r := chi.NewRouter()
+	MountResource(r, "/widgets", handler.Routes())
+
+	_, response := serveBulk(r, "/widgets/bulk", NDJSON, strings.Repeat(`{"op": "create", "item": {"name": "a"}}`+"\n", 12))
+	require.Equal(t, 12, response.Succeeded)
+	require.LessOrEqual(t, peak.Load(), int32(3))
+	require.Greater(t, peak.Load(), int32(1))
+	for i, result := range response.Results {
+		require.Equal(t, i, result.Index, "results keep the order of the operations")
+	}
+}
+
+func TestResourceHandler_Bulk_Repository(t *testing.T) {
+	ctx := context.Background()
+	db, err := repository.Open(ctx, repository.SQLite, ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	migrator, err := repository.NewMigrator(db, repository.SQLite)
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	service := resource.NewRepositoryService[data.Todo](repository.NewTodoRepository(db, repository.SQLite), "Todo")
+	handler := NewResourceHandler(FromService[data.Todo](service), "todoID").WithBulk(BulkOptions{
+		Atomic: func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return repository.Transaction(ctx, db, fn)
+		},
+	})
+	r := chi.NewRouter()
+	MountResource(r, "/todos", handler.Routes())
+
+	// El segundo alta no cumple la validación: con atomic no se guarda ninguna
+	body := `{"op": "create", "item": {"userId": 1, "title": "a"}}` + "\n" + `{"op": "create", "item": {"userId": 1}}` + "\n"
+	_, response := serveBulk(r, "/todos/bulk?atomic=true", NDJSON, body)
+	require.Equal(t, []int{424, 422}, statuses(response))
+	_, total, err := service.List(ctx, nil, pagination.Pagination{})
+	require.NoError(t, err)
+	require.Zero(t, total)
+
+	// Sin atomic la primera se guarda aunque la segunda falle
+	_, response = serveBulk(r, "/todos/bulk", NDJSON, body)
+	require.Equal(t, []int{201, 422}, statuses(response))
+	_, total, err = service.List(ctx, nil, pagination.Pagination{})
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+}
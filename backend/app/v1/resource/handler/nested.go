@@ -0,0 +1,95 @@
+package resource
+
+import (
+	apperrors "blog-api/app/errors"
+	"blog-api/app/expand"
+	resource "blog-api/app/v1/resource/service"
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Parent comprueba que exista el recurso padre con el id dado
+type Parent func(ctx context.Context, id int) error
+
+// Exists arma un Parent a partir del Get del servicio del padre, p. ej. Exists(userService.GetUser)
+func Exists[P any](get func(ctx context.Context, id int) (*P, error)) Parent {
+	return func(ctx context.Context, id int) error {
+		_, err := get(ctx, id)
+		return err
+	}
+}
+
+// NestedHandler expone un recurso hijo bajo su padre, p. ej. /users/{userID}/posts.
+// List filtra por el id del padre y Create lo asigna al elemento creado.
+type NestedHandler[T any] struct {
+	child       *ResourceHandler[T]
+	parent      Parent
+	parentParam string
+	field       string
+	setParent   func(item *T, id int)
+}
+
+// NewNestedHandler crea el manejador; parentParam es el parámetro de la URL con el id del padre,
+// field el filtro del hijo que lo referencia y setParent lo asigna al crear
+func NewNestedHandler[T any](child *ResourceHandler[T], parent Parent, parentParam string, field string, setParent func(item *T, id int)) *NestedHandler[T] {
+	return &NestedHandler[T]{
+		child:       child,
+		parent:      parent,
+		parentParam: parentParam,
+		field:       field,
+		setParent:   setParent,
+	}
+}
+
+// WithExpander habilita ?expand= en List con las relaciones de expander
+func (h *NestedHandler[T]) WithExpander(expander *expand.Resolver[T]) *NestedHandler[T] {
+	h.child.WithExpander(expander)
+	return h
+}
+
+// List responde los hijos del padre, filtrados y paginados
+func (h *NestedHandler[T]) List(w http.ResponseWriter, r *http.Request) {
+	parentID, ok := h.parentID(w, r)
+	if !ok {
+		return
+	}
+	filter := resource.NewFilter(r.URL.Query(), h.child.filters...)
+	filter[h.field] = strconv.Itoa(parentID)
+	h.child.list(w, r, filter)
+}
+
+// Create crea un hijo del padre a partir del cuerpo de la solicitud
+func (h *NestedHandler[T]) Create(w http.ResponseWriter, r *http.Request) {
+	parentID, ok := h.parentID(w, r)
+	if !ok {
+		return
+	}
+	var item T
+	if !DecodeJSON(w, r, &item) {
+		return
+	}
+	h.setParent(&item, parentID)
+	created, err := h.child.ops.Create(r.Context(), item)
+	if err != nil {
+		Fail(w, r, err, apperrors.ErrInternal)
+		return
+	}
+	WriteTagged(w, r, http.StatusCreated, created)
+}
+
+// parentID lee el id del padre y comprueba que exista; si no, responde 400 o 404
+func (h *NestedHandler[T]) parentID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, h.parentParam))
+	if err != nil {
+		apperrors.Write(w, r, apperrors.Newf(apperrors.ErrBadRequest, "%s must be an integer", h.parentParam), nil)
+		return 0, false
+	}
+	if err := h.parent(r.Context(), id); err != nil {
+		Fail(w, r, err, apperrors.ErrNotFound)
+		return 0, false
+	}
+	return id, true
+}
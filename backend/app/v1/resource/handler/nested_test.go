@@ -0,0 +1,92 @@
+package resource
+
+import (
+	"blog-api/app/pagination"
+	resource "blog-api/app/v1/resource/service"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+type gadget struct {
+	ID       int    `json:"id"`
+	WidgetID int    `json:"widgetId"`
+	Name     string `json:"name"`
+}
+
+func newGadgetRouter() (*chi.Mux, map[int]gadget) {
+	widgets := map[int]widget{1: {ID: 1, Name: "a"}, 2: {ID: 2, Name: "b"}}
+	gadgets := map[int]gadget{1: {ID: 1, WidgetID: 1, Name: "x"}, 2: {ID: 2, WidgetID: 2, Name: "y"}, 3: {ID: 3, WidgetID: 1, Name: "z"}}
+	children := NewResourceHandler(Operations[gadget]{
+		List: func(ctx context.Context, filter resource.Filter, page pagination.Pagination) (*[]gadget, int, error) {
+			var items []gadget
+			for id := 1; id <= len(gadgets); id++ {
+				if strconv.Itoa(gadgets[id].WidgetID) != filter["widgetId"] {
+					continue
+				}
+				if name, ok := filter["name"]; ok && gadgets[id].Name != name {
+					continue
+				}
+				items = append(items, gadgets[id])
+			}
+			total := len(items)
+			items = pagination.Apply(items, page)
+			return &items, total, nil
+		},
+		Create: func(ctx context.Context, item gadget) (*gadget, error) {
+			item.ID = len(gadgets) + 1
+			gadgets[item.ID] = item
+			return &item, nil
+		},
+	}, "gadgetID", "name")
+	nested := NewNestedHandler(children, Exists(widgetOperations(widgets).Get), "widgetID", "widgetId", func(item *gadget, id int) {
+		item.WidgetID = id
+	})
+	parent := NewResourceHandler(widgetOperations(widgets), "widgetID")
+	r := chi.NewRouter()
+	MountResource(r, "/widgets", parent.Routes(), MountNested("/gadgets", nested.List, nested.Create))
+	return r, gadgets
+}
+
+func TestNestedHandler_List(t *testing.T) {
+	r, _ := newGadgetRouter()
+	mockRecorder := serve(r, http.MethodGet, "/widgets/1/gadgets?limit=1", nil)
+	require.Equal(t, http.StatusOK, mockRecorder.Code)
+	require.Equal(t, "2", mockRecorder.Header().Get("X-Total-Count"))
+	require.JSONEq(t, `[{"id": 1, "widgetId": 1, "name": "x"}]`, mockRecorder.Body.String())
+
+	// El filtro del padre no se puede pisar desde la query
+	mockRecorder = serve(r, http.MethodGet, "/widgets/1/gadgets?name=z&widgetId=2", nil)
+	require.JSONEq(t, `[{"id": 3, "widgetId": 1, "name": "z"}]`, mockRecorder.Body.String())
+
+	require.Equal(t, http.StatusNotFound, serve(r, http.MethodGet, "/widgets/9/gadgets", nil).Code)
+	require.Equal(t, http.StatusBadRequest, serve(r, http.MethodGet, "/widgets/abc/gadgets", nil).Code)
+}
+
+func TestNestedHandler_Create(t *testing.T) {
+	r, gadgets := newGadgetRouter()
+	mockRecorder := serve(r, http.MethodPost, "/widgets/2/gadgets", gadget{WidgetID: 1, Name: "w"})
+	require.Equal(t, http.StatusCreated, mockRecorder.Code)
+	require.Equal(t, gadget{ID: 4, WidgetID: 2, Name: "w"}, gadgets[4])
+
+	require.Equal(t, http.StatusNotFound, serve(r, http.MethodPost, "/widgets/9/gadgets", gadget{Name: "w"}).Code)
+	require.Equal(t, http.StatusBadRequest, serve(r, http.MethodPost, "/widgets/1/gadgets", "not a gadget").Code)
+	require.Len(t, gadgets, 4)
+}
+
+func TestExists(t *testing.T) {
+	missing := errors.New("missing")
+	exists := Exists(func(ctx context.Context, id int) (*widget, error) {
+		if id != 1 {
+			return nil, missing
+		}
+		return &widget{ID: 1}, nil
+	})
+	require.NoError(t, exists(context.Background(), 1))
+	require.ErrorIs(t, exists(context.Background(), 2), missing)
+}
@@ -0,0 +1,305 @@
+package resource
+
+import (
+	"blog-api/app/clients/restclient"
+	"blog-api/app/conditional"
+	apperrors "blog-api/app/errors"
+	"blog-api/app/expand"
+	"blog-api/app/i18n"
+	"blog-api/app/pagination"
+	"blog-api/app/patch"
+	resource "blog-api/app/v1/resource/service"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+// Operations son las funciones del servicio que usa el manejador genérico.
+// Se arman con los métodos de cualquier servicio de recurso, p. ej. List: postService.GetPosts
+type Operations[T any] struct {
+	List   func(ctx context.Context, filter resource.Filter, page pagination.Pagination) (*[]T, int, error)
+	Get    func(ctx context.Context, id int) (*T, error)
+	Create func(ctx context.Context, item T) (*T, error)
+	Update func(ctx context.Context, id int, item T) (*T, error)
+	Patch  func(ctx context.Context, id int, apply func(current T) (T, error)) (*T, error)
+	Delete func(ctx context.Context, id int) error
+}
+
+// FromService arma las operaciones a partir de un servicio genérico
+func FromService[T any](service resource.IResourceService[T]) Operations[T] {
+	return Operations[T]{
+		List:   service.List,
+		Get:    service.Get,
+		Create: service.Create,
+		Update: service.Update,
+		Patch:  service.Patch,
+		Delete: service.Delete,
+	}
+}
+
+// ResourceHandler implementa el flujo CRUD común: leer el id, decodificar el JSON,
+// llamar al servicio y codificar la respuesta
+type ResourceHandler[T any] struct {
+	ops      Operations[T]
+	idParam  string
+	filters  []string
+	expander *expand.Resolver[T]
+	bulk     BulkOptions
+}
+
+// NewResourceHandler crea el manejador; idParam es el nombre del parámetro de la URL
+// y filters los query params que se pasan al servicio en List
+func NewResourceHandler[T any](ops Operations[T], idParam string, filters ...string) *ResourceHandler[T] {
+	return &ResourceHandler[T]{
+		ops:     ops,
+		idParam: idParam,
+		filters: filters,
+		bulk:    BulkOptions{}.withDefaults(),
+	}
+}
+
+// WithExpander habilita ?expand= en List y Get con las relaciones de expander
+func (h *ResourceHandler[T]) WithExpander(expander *expand.Resolver[T]) *ResourceHandler[T] {
+	h.expander = expander
+	return h
+}
+
+// IDParam devuelve el nombre del parámetro de la URL con el id del recurso
+func (h *ResourceHandler[T]) IDParam() string {
+	return h.idParam
+}
+
+// Routes devuelve las rutas estándar para MountResource
+func (h *ResourceHandler[T]) Routes() Routes {
+	return Routes{
+		IDParam: h.idParam,
+		List:    h.List,
+		Create:  h.Create,
+		Get:     h.Get,
+		Update:  h.Update,
+		Patch:   h.Patch,
+		Delete:  h.Delete,
+		Bulk:    h.Bulk,
+	}
+}
+
+// List responde la colección filtrada y paginada
+func (h *ResourceHandler[T]) List(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, resource.NewFilter(r.URL.Query(), h.filters...))
+}
+
+func (h *ResourceHandler[T]) list(w http.ResponseWriter, r *http.Request, filter resource.Filter) {
+	tree, ok := h.expansions(w, r)
+	if !ok {
+		return
+	}
+	page := pagination.FromContext(r.Context())
+	items, total, err := h.ops.List(r.Context(), filter, page)
+	if err != nil {
+		Fail(w, r, err, apperrors.ErrInternal)
+		return
+	}
+	var body any = items
+	if len(tree) > 0 {
+		if body, err = h.expander.Expand(r.Context(), *items, tree); err != nil {
+			Fail(w, r, err, apperrors.ErrUpstream)
+			return
+		}
+	}
+	pagination.SetHeaders(w, r, page, total)
+	WriteTagged(w, r, http.StatusOK, body)
+}
+
+// Get responde un elemento por id con su ETag; si If-None-Match lo tiene responde 304
+func (h *ResourceHandler[T]) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.id(w, r)
+	if !ok {
+		return
+	}
+	tree, ok := h.expansions(w, r)
+	if !ok {
+		return
+	}
+	item, err := h.ops.Get(r.Context(), id)
+	if err != nil {
+		Fail(w, r, err, apperrors.ErrNotFound)
+		return
+	}
+	var body any = item
+	if len(tree) > 0 {
+		expanded, err := h.expander.Expand(r.Context(), []T{*item}, tree)
+		if err != nil {
+			Fail(w, r, err, apperrors.ErrUpstream)
+			return
+		}
+		body = expanded[0]
+	}
+	WriteTagged(w, r, http.StatusOK, body)
+}
+
+// Create crea un elemento a partir del cuerpo de la solicitud
+func (h *ResourceHandler[T]) Create(w http.ResponseWriter, r *http.Request) {
+	var item T
+	if !DecodeJSON(w, r, &item) {
+		return
+	}
+	created, err := h.ops.Create(r.Context(), item)
+	if err != nil {
+		Fail(w, r, err, apperrors.ErrInternal)
+		return
+	}
+	WriteTagged(w, r, http.StatusCreated, created)
+}
+
+// Update reemplaza un elemento. Con If-Match lo reemplaza solo si el elemento actual
+// tiene ese ETag; si no, responde 412.
+func (h *ResourceHandler[T]) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.id(w, r)
+	if !ok {
+		return
+	}
+	var item T
+	if !DecodeJSON(w, r, &item) {
+		return
+	}
+	result, err := h.ops.Update(conditional.NewContext(r.Context(), r.Header.Get("If-Match")), id, item)
+	if err != nil {
+		Fail(w, r, err, apperrors.ErrInternal)
+		return
+	}
+	WriteTagged(w, r, http.StatusOK, result)
+}
+
+// Patch aplica al elemento el documento del cuerpo según su Content-Type: un merge patch
+// (application/merge-patch+json, o application/json) o un JSON patch
+// (application/json-patch+json). Los tipos aceptados se informan en Accept-Patch. Con
+// If-Match el patch se aplica solo si el elemento actual tiene ese ETag.
+func (h *ResourceHandler[T]) Patch(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.id(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Accept-Patch", patch.Accept)
+	contentType := "application/json"
+	if value := r.Header.Get("Content-Type"); value != "" {
+		var err error
+		if contentType, _, err = mime.ParseMediaType(value); err != nil {
+			apperrors.Write(w, r, apperrors.Newf(apperrors.ErrUnsupportedMediaType, "%s is not a patch type, use %s", value, patch.Accept), nil)
+			return
+		}
+	}
+	document, err := io.ReadAll(r.Body)
+	if err != nil {
+		Fail(w, r, err, apperrors.ErrBadRequest)
+		return
+	}
+	apply, err := patch.Func[T](contentType, document)
+	if err != nil {
+		Fail(w, r, err, apperrors.ErrBadRequest)
+		return
+	}
+	result, err := h.ops.Patch(conditional.NewContext(r.Context(), r.Header.Get("If-Match")), id, apply)
+	if err != nil {
+		Fail(w, r, err, apperrors.ErrInternal)
+		return
+	}
+	WriteTagged(w, r, http.StatusOK, result)
+}
+
+// Delete elimina un elemento; con If-Match solo si el elemento actual tiene ese ETag
+func (h *ResourceHandler[T]) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.id(w, r)
+	if !ok {
+		return
+	}
+	err := h.ops.Delete(conditional.NewContext(r.Context(), r.Header.Get("If-Match")), id)
+	if err != nil {
+		Fail(w, r, err, apperrors.ErrInternal)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ResourceHandler[T]) id(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, h.idParam))
+	if err != nil {
+		apperrors.Write(w, r, apperrors.Newf(apperrors.ErrBadRequest, "%s must be an integer", h.idParam), nil)
+		return 0, false
+	}
+	return id, true
+}
+
+// expansions lee las relaciones pedidas en ?expand=; si no son válidas responde 400
+func (h *ResourceHandler[T]) expansions(w http.ResponseWriter, r *http.Request) (expand.Tree, bool) {
+	tree, err := expand.Parse(r.URL.Query())
+	if err == nil && len(tree) > 0 {
+		if h.expander == nil {
+			err = i18n.Errorf("%w: this resource has no relations", expand.ErrInvalid)
+		} else {
+			err = h.expander.Validate(tree)
+		}
+	}
+	if err != nil {
+		apperrors.Write(w, r, err, apperrors.ErrBadRequest)
+		return nil, false
+	}
+	return tree, true
+}
+
+// Fail responde err como problem+json. Los errores de dominio llevan su propio código;
+// el resto se informa como fallback. Si el circuito del upstream está abierto responde
+// 503 con Retry-After.
+func Fail(w http.ResponseWriter, r *http.Request, err error, fallback *apperrors.Error) {
+	err, seconds := unavailable(err)
+	if seconds > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	}
+	apperrors.Write(w, r, err, fallback)
+}
+
+// unavailable convierte el error de un circuito abierto en ErrUpstreamUnavailable y
+// devuelve los segundos que faltan para reintentar; los demás errores no cambian
+func unavailable(err error) (error, int) {
+	var open *restclient.CircuitOpenError
+	if !errors.As(err, &open) {
+		return err, 0
+	}
+	seconds := int(math.Ceil(open.RetryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return apperrors.Newf(apperrors.ErrUpstreamUnavailable, "upstream unavailable, retry in %d seconds", seconds), seconds
+}
+
+// WriteJSON responde body codificado como JSON con status
+func WriteJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// WriteTagged responde body como WriteJSON junto con su ETag, calculado sobre los bytes
+// de la respuesta. Si la solicitud es un GET y su If-None-Match tiene ese ETag responde
+// 304 sin cuerpo, así el cliente revalida su copia sin volver a descargarla.
+func WriteTagged(w http.ResponseWriter, r *http.Request, status int, body any) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		Fail(w, r, err, apperrors.ErrInternal)
+		return
+	}
+	etag := conditional.Tag(data)
+	w.Header().Set("ETag", etag)
+	if (r.Method == http.MethodGet || r.Method == http.MethodHead) && conditional.NotModified(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(data, '\n'))
+}
@@ -0,0 +1,346 @@
+package resource
+
+import (
+	"blog-api/app/clients/restclient"
+	"blog-api/app/conditional"
+	"blog-api/app/expand"
+	"blog-api/app/pagination"
+	"blog-api/app/repository"
+	resource "blog-api/app/v1/resource/service"
+	"blog-api/data"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+type widget struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func widgetOperations(store map[int]widget) Operations[widget] {
+	return Operations[widget]{
+		List: func(ctx context.Context, filter resource.Filter, page pagination.Pagination) (*[]widget, int, error) {
+			var items []widget
+			for id := 1; id <= len(store); id++ {
+				if name, ok := filter["name"]; ok && store[id].Name != name {
+					continue
+				}
+				items = append(items, store[id])
+			}
+			total := len(items)
+			items = pagination.Apply(items, page)
+			return &items, total, nil
+		},
+		Get: func(ctx context.Context, id int) (*widget, error) {
+			item, ok := store[id]
+			if !ok {
+				return nil, errors.New("not found")
+			}
+			return &item, nil
+		},
+		Create: func(ctx context.Context, item widget) (*widget, error) {
+			item.ID = len(store) + 1
+			store[item.ID] = item
+			return &item, nil
+		},
+		Update: func(ctx context.Context, id int, item widget) (*widget, error) {
+			if err := conditional.Check(ctx, store[id]); err != nil {
+				return nil, err
+			}
+			item.ID = id
+			store[id] = item
+			return &item, nil
+		},
+		Patch: func(ctx context.Context, id int, apply func(current widget) (widget, error)) (*widget, error) {
+			current, ok := store[id]
+			if !ok {
+				return nil, errors.New("not found")
+			}
+			if err := conditional.Check(ctx, current); err != nil {
+				return nil, err
+			}
+			item, err := apply(current)
+			if err != nil {
+				return nil, err
+			}
+			item.ID = id
+			store[id] = item
+			return &item, nil
+		},
+		Delete: func(ctx context.Context, id int) error {
+			if err := conditional.Check(ctx, store[id]); err != nil {
+				return err
+			}
+			delete(store, id)
+			return nil
+		},
+	}
+}
+
+func newWidgetRouter(opts ...MountOption) (*chi.Mux, map[int]widget) {
+	store := map[int]widget{1: {ID: 1, Name: "a"}, 2: {ID: 2, Name: "b"}, 3: {ID: 3, Name: "a"}}
+	handler := NewResourceHandler(widgetOperations(store), "widgetID", "name")
+	r := chi.NewRouter()
+	MountResource(r, "/widgets", handler.Routes(), opts...)
+	return r, store
+}
+
+func serve(r http.Handler, method string, target string, body any) *httptest.ResponseRecorder {
+	var payload bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&payload).Encode(body)
+	}
+	req := httptest.NewRequest(method, target, &payload)
+	mockRecorder := httptest.NewRecorder()
+	r.ServeHTTP(mockRecorder, req)
+	return mockRecorder
+}
+
+func TestMountResource_List(t *testing.T) {
+	r, _ := newWidgetRouter()
+	mockRecorder := serve(r, http.MethodGet, "/widgets?name=a&limit=1", nil)
+	require.Equal(t, http.StatusOK, mockRecorder.Code)
+	require.Equal(t, "2", mockRecorder.Header().Get("X-Total-Count"))
+	require.JSONEq(t, `[{"id": 1, "name": "a"}]`, mockRecorder.Body.String())
+
+	mockRecorder = serve(r, http.MethodGet, "/widgets?page=0", nil)
+	require.Equal(t, http.StatusBadRequest, mockRecorder.Code)
+}
+
+func TestMountResource_List_InvalidFilter(t *testing.T) {
+	ctx := context.Background()
+	db, err := repository.Open(ctx, repository.SQLite, ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	migrator, err := repository.NewMigrator(db, repository.SQLite)
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	service := resource.NewRepositoryService[data.Todo](repository.NewTodoRepository(db, repository.SQLite), "Todo")
+	r := chi.NewRouter()
+	MountResource(r, "/todos", NewResourceHandler(FromService[data.Todo](service), "todoID", "userId").Routes())
+
+	// Un filtro que no se puede convertir es un error del cliente, no del servidor
+	mockRecorder := serve(r, http.MethodGet, "/todos?userId=abc", nil)
+	require.Equal(t, http.StatusBadRequest, mockRecorder.Code, mockRecorder.Body.String())
+	require.Contains(t, mockRecorder.Body.String(), `invalid userId \"abc\"`)
+}
+
+func TestMountResource_Get(t *testing.T) {
+	r, _ := newWidgetRouter()
+	mockRecorder := serve(r, http.MethodGet, "/widgets/2", nil)
+	require.Equal(t, http.StatusOK, mockRecorder.Code)
+	require.Equal(t, "application/json", mockRecorder.Header().Get("Content-Type"))
+	require.JSONEq(t, `{"id": 2, "name": "b"}`, mockRecorder.Body.String())
+
+	require.Equal(t, http.StatusNotFound, serve(r, http.MethodGet, "/widgets/9", nil).Code)
+	require.Equal(t, http.StatusBadRequest, serve(r, http.MethodGet, "/widgets/abc", nil).Code)
+}
+
+func TestMountResource_ETag(t *testing.T) {
+	r, store := newWidgetRouter()
+	mockRecorder := serve(r, http.MethodGet, "/widgets/2", nil)
+	etag := mockRecorder.Header().Get("ETag")
+	require.Equal(t, conditional.Tag([]byte(`{"id":2,"name":"b"}`)), etag)
+	require.Equal(t, "no-cache", mockRecorder.Header().Get("Cache-Control"))
+
+	// Con el mismo ETag en If-None-Match la respuesta es 304 sin cuerpo
+	req := httptest.NewRequest(http.MethodGet, "/widgets/2", nil)
+	req.Header.Set("If-None-Match", etag)
+	mockRecorder = httptest.NewRecorder()
+	r.ServeHTTP(mockRecorder, req)
+	require.Equal(t, http.StatusNotModified, mockRecorder.Code)
+	require.Equal(t, etag, mockRecorder.Header().Get("ETag"))
+	require.Empty(t, mockRecorder.Body.String())
+
+	list := serve(r, http.MethodGet, "/widgets", nil)
+	require.NotEmpty(t, list.Header().Get("ETag"))
+	require.NotEqual(t, etag, list.Header().Get("ETag"))
+
+	// Las escrituras con un If-Match viejo responden 412 y no cambian nada
+	conditionalServe := func(method string, ifMatch string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/widgets/2", bytes.NewBufferString(body))
+		req.Header.Set("If-Match", ifMatch)
+		mockRecorder := httptest.NewRecorder()
+		r.ServeHTTP(mockRecorder, req)
+		return mockRecorder
+	}
+	for _, method := range []string{http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		mockRecorder = conditionalServe(method, `"stale"`, `{"name": "y"}`)
+		require.Equal(t, http.StatusPreconditionFailed, mockRecorder.Code, method)
+		require.Contains(t, mockRecorder.Body.String(), `"code":"precondition_failed"`, method)
+		require.Equal(t, "no-store", mockRecorder.Header().Get("Cache-Control"), method)
+	}
+	require.Equal(t, widget{ID: 2, Name: "b"}, store[2])
+
+	mockRecorder = conditionalServe(http.MethodPatch, etag, `{"name": "y"}`)
+	require.Equal(t, http.StatusOK, mockRecorder.Code)
+	require.Equal(t, conditional.Tag([]byte(`{"id":2,"name":"y"}`)), mockRecorder.Header().Get("ETag"))
+	require.Equal(t, http.StatusPreconditionFailed, conditionalServe(http.MethodPut, etag, `{"name": "z"}`).Code)
+	require.Equal(t, http.StatusOK, conditionalServe(http.MethodPut, mockRecorder.Header().Get("ETag"), `{"name": "z"}`).Code)
+	require.Equal(t, http.StatusNoContent, conditionalServe(http.MethodDelete, "*", "").Code)
+}
+
+func TestMountResource_Writes(t *testing.T) {
+	r, store := newWidgetRouter()
+
+	mockRecorder := serve(r, http.MethodPost, "/widgets", widget{Name: "c"})
+	require.Equal(t, http.StatusCreated, mockRecorder.Code)
+	require.JSONEq(t, `{"id": 4, "name": "c"}`, mockRecorder.Body.String())
+
+	mockRecorder = serve(r, http.MethodPut, "/widgets/1", widget{Name: "z"})
+	require.Equal(t, http.StatusOK, mockRecorder.Code)
+	require.Equal(t, widget{ID: 1, Name: "z"}, store[1])
+
+	mockRecorder = serve(r, http.MethodPatch, "/widgets/2", widget{Name: "y"})
+	require.Equal(t, http.StatusOK, mockRecorder.Code)
+	require.Equal(t, widget{ID: 2, Name: "y"}, store[2])
+
+	mockRecorder = serve(r, http.MethodDelete, "/widgets/3", nil)
+	require.Equal(t, http.StatusNoContent, mockRecorder.Code)
+	require.NotContains(t, store, 3)
+
+	mockRecorder = serve(r, http.MethodPut, "/widgets/1", "not a widget")
+	require.Equal(t, http.StatusBadRequest, mockRecorder.Code)
+}
+
+func TestResourceHandler_Patch(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+		code        string
+		want        widget
+	}{
+		{"merge patch", "application/merge-patch+json", `{"name": "y"}`, http.StatusOK, "", widget{ID: 2, Name: "y"}},
+		{"json as merge patch", "application/json", `{"name": "y"}`, http.StatusOK, "", widget{ID: 2, Name: "y"}},
+		{"merge patch removes a field", "application/merge-patch+json", `{"name": null}`, http.StatusOK, "", widget{ID: 2}},
+		{"json patch", "application/json-patch+json", `[{"op": "test", "path": "/name", "value": "b"}, {"op": "replace", "path": "/name", "value": "y"}]`, http.StatusOK, "", widget{ID: 2, Name: "y"}},
+		{"failed test", "application/json-patch+json", `[{"op": "replace", "path": "/name", "value": "y"}, {"op": "test", "path": "/name", "value": "b"}]`, http.StatusUnprocessableEntity, "patch_failed", widget{ID: 2, Name: "b"}},
+		{"missing path", "application/json-patch+json", `[{"op": "replace", "path": "/color", "value": "red"}]`, http.StatusUnprocessableEntity, "patch_failed", widget{ID: 2, Name: "b"}},
+		{"wrong type", "application/merge-patch+json", `{"name": 5}`, http.StatusUnprocessableEntity, "validation_failed", widget{ID: 2, Name: "b"}},
+		{"invalid document", "application/json-patch+json", `{"op": "add"}`, http.StatusBadRequest, "bad_request", widget{ID: 2, Name: "b"}},
+		{"unknown op", "application/json-patch+json", `[{"op": "rename", "path": "/name"}]`, http.StatusBadRequest, "bad_request", widget{ID: 2, Name: "b"}},
+		{"not a patch type", "text/plain", `name=y`, http.StatusUnsupportedMediaType, "unsupported_media_type", widget{ID: 2, Name: "b"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, store := newWidgetRouter()
+			req := httptest.NewRequest(http.MethodPatch, "/widgets/2", bytes.NewBufferString(tc.body))
+			req.Header.Set("Content-Type", tc.contentType)
+			mockRecorder := httptest.NewRecorder()
+			r.ServeHTTP(mockRecorder, req)
+			require.Equal(t, tc.status, mockRecorder.Code)
+			require.Equal(t, "application/merge-patch+json, application/json-patch+json", mockRecorder.Header().Get("Accept-Patch"))
+			if tc.code != "" {
+				var problem struct {
+					Code string `json:"code"`
+				}
+				require.NoError(t, json.Unmarshal(mockRecorder.Body.Bytes(), &problem))
+				require.Equal(t, tc.code, problem.Code)
+			}
+			require.Equal(t, tc.want, store[2])
+		})
+	}
+}
+
+func TestMountResource_Options(t *testing.T) {
+	deny := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		})
+	}
+	r, _ := newWidgetRouter(
+		WithMiddleware(DeleteRoute, deny),
+		WithSubroutes(func(r chi.Router) {
+			r.Get("/name", func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(chi.URLParam(r, "widgetID")))
+			})
+		}),
+	)
+	require.Equal(t, http.StatusForbidden, serve(r, http.MethodDelete, "/widgets/1", nil).Code)
+	require.Equal(t, http.StatusOK, serve(r, http.MethodGet, "/widgets/1", nil).Code)
+	require.Equal(t, "1", serve(r, http.MethodGet, "/widgets/1/name", nil).Body.String())
+}
+
+func TestResourceHandler_PropagatesContext(t *testing.T) {
+	type key struct{}
+	var got any
+	handler := NewResourceHandler(Operations[widget]{
+		Get: func(ctx context.Context, id int) (*widget, error) {
+			got = ctx.Value(key{})
+			return &widget{ID: id}, nil
+		},
+	}, "widgetID")
+	r := chi.NewRouter()
+	r.Get("/widgets/{widgetID}", handler.Get)
+	req := httptest.NewRequest(http.MethodGet, "/widgets/1", nil)
+	req = req.WithContext(context.WithValue(req.Context(), key{}, "inbound"))
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	require.Equal(t, "inbound", got)
+}
+
+func TestResourceHandler_CircuitOpen(t *testing.T) {
+	open := &restclient.CircuitOpenError{Host: "upstream", RetryAfter: 1500 * time.Millisecond}
+	handler := NewResourceHandler(Operations[widget]{
+		List: func(ctx context.Context, filter resource.Filter, page pagination.Pagination) (*[]widget, int, error) {
+			return nil, 0, open
+		},
+		Get: func(ctx context.Context, id int) (*widget, error) {
+			return nil, fmt.Errorf("widget can´t be found: %w", open)
+		},
+	}, "widgetID")
+	r := chi.NewRouter()
+	MountResource(r, "/widgets", handler.Routes())
+
+	for _, target := range []string{"/widgets", "/widgets/1"} {
+		mockRecorder := serve(r, http.MethodGet, target, nil)
+		require.Equal(t, http.StatusServiceUnavailable, mockRecorder.Code)
+		require.Equal(t, "2", mockRecorder.Header().Get("Retry-After"))
+	}
+}
+
+func TestResourceHandler_Expand(t *testing.T) {
+	owners := map[int]widget{1: {ID: 1, Name: "owner"}}
+	gadgets := map[int]gadget{1: {ID: 1, WidgetID: 1, Name: "x"}, 2: {ID: 2, WidgetID: 2, Name: "y"}}
+	handler := NewResourceHandler(Operations[gadget]{
+		List: func(ctx context.Context, filter resource.Filter, page pagination.Pagination) (*[]gadget, int, error) {
+			items := []gadget{gadgets[1]}
+			return &items, 1, nil
+		},
+		Get: func(ctx context.Context, id int) (*gadget, error) {
+			item := gadgets[id]
+			return &item, nil
+		},
+	}, "gadgetID")
+	handler.WithExpander(expand.NewResolver[gadget]().Add("widget", expand.BelongsTo(func(g gadget) int { return g.WidgetID }, widgetOperations(owners).Get, nil)))
+	r := chi.NewRouter()
+	MountResource(r, "/gadgets", handler.Routes())
+
+	mockRecorder := serve(r, http.MethodGet, "/gadgets?expand=widget", nil)
+	require.Equal(t, http.StatusOK, mockRecorder.Code)
+	require.Equal(t, "1", mockRecorder.Header().Get("X-Total-Count"))
+	require.JSONEq(t, `[{"id": 1, "widgetId": 1, "name": "x", "widget": {"id": 1, "name": "owner"}}]`, mockRecorder.Body.String())
+
+	mockRecorder = serve(r, http.MethodGet, "/gadgets/1?embed=widget", nil)
+	require.JSONEq(t, `{"id": 1, "widgetId": 1, "name": "x", "widget": {"id": 1, "name": "owner"}}`, mockRecorder.Body.String())
+
+	require.Equal(t, http.StatusBadGateway, serve(r, http.MethodGet, "/gadgets/2?expand=widget", nil).Code)
+	require.Equal(t, http.StatusBadRequest, serve(r, http.MethodGet, "/gadgets?expand=owner", nil).Code)
+	require.Equal(t, http.StatusBadRequest, serve(r, http.MethodGet, "/gadgets/1?expand=widget.gadgets", nil).Code)
+
+	// Un recurso sin relaciones rechaza expand
+	plain, _ := newWidgetRouter()
+	require.Equal(t, http.StatusBadRequest, serve(plain, http.MethodGet, "/widgets?expand=owner", nil).Code)
+}
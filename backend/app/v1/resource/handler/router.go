@@ -0,0 +1,153 @@
+package resource
+
+import (
+	"blog-api/app/cache"
+	"blog-api/app/pagination"
+	"github.com/go-chi/chi/v5"
+	"net/http"
+)
+
+// Route identifica una de las rutas estándar de un recurso
+type Route int
+
+const (
+	ListRoute Route = iota
+	CreateRoute
+	GetRoute
+	UpdateRoute
+	PatchRoute
+	DeleteRoute
+	BulkRoute
+)
+
+// Routes son los handlers que MountResource registra para un recurso
+type Routes struct {
+	IDParam string
+	List    http.HandlerFunc
+	Create  http.HandlerFunc
+	Get     http.HandlerFunc
+	Update  http.HandlerFunc
+	Patch   http.HandlerFunc
+	Delete  http.HandlerFunc
+	// Bulk es opcional; si es nil no se registra /bulk
+	Bulk http.HandlerFunc
+}
+
+// Políticas de Cache-Control de las rutas de un recurso
+const (
+	// Revalidate deja guardar la respuesta pero obliga a revalidarla con su ETag antes de usarla
+	Revalidate = "no-cache"
+	// NoStore no deja guardar la respuesta
+	NoStore = "no-store"
+)
+
+type mountOptions struct {
+	middlewares  map[Route][]func(http.Handler) http.Handler
+	subroutes    []func(r chi.Router)
+	cacheControl map[Route]string
+}
+
+// MountOption configura MountResource
+type MountOption func(*mountOptions)
+
+// WithMiddleware agrega middlewares a una sola de las rutas del recurso
+func WithMiddleware(route Route, middlewares ...func(http.Handler) http.Handler) MountOption {
+	return func(o *mountOptions) {
+		o.middlewares[route] = append(o.middlewares[route], middlewares...)
+	}
+}
+
+// WithCacheControl responde Cache-Control con value en las rutas indicadas. Por defecto
+// List y Get usan Revalidate y las escrituras NoStore.
+func WithCacheControl(value string, routes ...Route) MountOption {
+	return func(o *mountOptions) {
+		for _, route := range routes {
+			o.cacheControl[route] = value
+		}
+	}
+}
+
+// CacheControl es el middleware que responde Cache-Control con value
+func CacheControl(value string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", value)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// WithSubroutes registra rutas adicionales bajo /{id}
+func WithSubroutes(fn func(r chi.Router)) MountOption {
+	return func(o *mountOptions) {
+		o.subroutes = append(o.subroutes, fn)
+	}
+}
+
+// Combine junta varias opciones de MountResource en una
+func Combine(opts ...MountOption) MountOption {
+	return func(o *mountOptions) {
+		for _, opt := range opts {
+			opt(o)
+		}
+	}
+}
+
+// MountNested registra un recurso hijo en path bajo /{id} del padre, con List paginado
+// y Create, p. ej. MountNested("/posts", ...) para /users/{userID}/posts
+func MountNested(path string, list http.HandlerFunc, create http.HandlerFunc) MountOption {
+	return WithSubroutes(func(r chi.Router) {
+		r.With(CacheControl(Revalidate), pagination.Paginate, cache.Report).Get(path, list)
+		r.With(CacheControl(NoStore)).Post(path, create)
+	})
+}
+
+// MountResource registra en pattern las rutas estándar de un recurso:
+//
+//	GET    /       List (paginado)
+//	POST   /       Create
+//	GET    /{id}   Get
+//	PUT    /{id}   Update
+//	PATCH  /{id}   Patch
+//	DELETE /{id}   Delete
+//	POST   /bulk   Bulk
+//
+// donde {id} usa el nombre routes.IDParam. List y Get informan el uso de la caché en X-Cache
+// y cada ruta responde Cache-Control según WithCacheControl.
+func MountResource(r chi.Router, pattern string, routes Routes, opts ...MountOption) {
+	o := &mountOptions{
+		middlewares: map[Route][]func(http.Handler) http.Handler{},
+		cacheControl: map[Route]string{
+			ListRoute:   Revalidate,
+			CreateRoute: NoStore,
+			GetRoute:    Revalidate,
+			UpdateRoute: NoStore,
+			PatchRoute:  NoStore,
+			DeleteRoute: NoStore,
+			BulkRoute:   NoStore,
+		},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	// with arma los middlewares de una ruta: Cache-Control, los propios de la ruta y los de WithMiddleware
+	with := func(route Route, middlewares ...func(http.Handler) http.Handler) chi.Middlewares {
+		return append(chi.Middlewares{CacheControl(o.cacheControl[route])}, append(middlewares, o.middlewares[route]...)...)
+	}
+	r.Route(pattern, func(r chi.Router) {
+		r.With(with(ListRoute, pagination.Paginate, cache.Report)...).Get("/", routes.List)
+		r.With(with(CreateRoute)...).Post("/", routes.Create)
+		if routes.Bulk != nil {
+			r.With(with(BulkRoute)...).Post("/bulk", routes.Bulk)
+		}
+		r.Route("/{"+routes.IDParam+"}", func(r chi.Router) {
+			r.With(with(GetRoute, cache.Report)...).Get("/", routes.Get)
+			r.With(with(UpdateRoute)...).Put("/", routes.Update)
+			r.With(with(PatchRoute)...).Patch("/", routes.Patch)
+			r.With(with(DeleteRoute)...).Delete("/", routes.Delete)
+			for _, fn := range o.subroutes {
+				fn(r)
+			}
+		})
+	})
+}
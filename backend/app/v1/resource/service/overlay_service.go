@@ -0,0 +1,206 @@
+package resource
+
+import (
+	"blog-api/app/conditional"
+	"blog-api/app/i18n"
+	"blog-api/app/pagination"
+	"blog-api/app/repository"
+	"blog-api/app/validation"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// OverlayService lee del upstream y guarda las escrituras en el almacenamiento
+// local; las lecturas combinan los dos, con prioridad para los cambios locales
+type OverlayService[T any] struct {
+	upstream IResourceService[T]
+	overlay  *repository.Overlay[T]
+	name     string
+}
+
+// NewOverlayService crea el servicio sobre upstream y los cambios locales de overlay
+func NewOverlayService[T any](upstream IResourceService[T], overlay *repository.Overlay[T], name string) *OverlayService[T] {
+	return &OverlayService[T]{
+		upstream: upstream,
+		overlay:  overlay,
+		name:     name,
+	}
+}
+
+// List combina la colección del upstream con los cambios locales y pagina el resultado.
+// Sin cambios locales del recurso pide al upstream solo la página. Con cambios pide la
+// colección completa, porque las altas, bajas y cambios locales mueven los elementos de
+// página; el upstream la guarda en su caché, así el costo es de una llamada por TTL.
+func (s *OverlayService[T]) List(ctx context.Context, filter Filter, page pagination.Pagination) (*[]T, int, error) {
+	records, err := s.overlay.All(ctx)
+	if err != nil {
+		return nil, 0, i18n.Errorf("%ss can´t be listed. %w", s.name, err)
+	}
+	if len(records) == 0 {
+		return s.upstream.List(ctx, filter, page)
+	}
+	upstream, _, err := s.upstream.List(ctx, filter, pagination.Pagination{})
+	if err != nil {
+		return nil, 0, err
+	}
+	local := make(map[int]repository.OverlayRecord[T], len(records))
+	for _, record := range records {
+		local[record.ID] = record
+	}
+	items := []T{}
+	for _, item := range *upstream {
+		record, changed := local[repository.ID(item)]
+		if !changed {
+			items = append(items, item)
+			continue
+		}
+		delete(local, record.ID)
+		if !record.Deleted && matches(record.Item, filter) {
+			items = append(items, record.Item)
+		}
+	}
+	// Los que quedan son los creados localmente o modificados fuera del filtro del upstream
+	for _, record := range records {
+		if _, pending := local[record.ID]; pending && !record.Deleted && matches(record.Item, filter) {
+			items = append(items, record.Item)
+		}
+	}
+	return pointer(pagination.Apply(items, page)), len(items), nil
+}
+
+// Get obtiene el elemento local si existe, o el del upstream
+func (s *OverlayService[T]) Get(ctx context.Context, id int) (*T, error) {
+	item, _, err := s.current(ctx, id)
+	return item, err
+}
+
+// current obtiene el elemento como Get junto con la versión de su cambio local, 0 si
+// viene del upstream
+func (s *OverlayService[T]) current(ctx context.Context, id int) (*T, int, error) {
+	record, err := s.overlay.Get(ctx, id)
+	if err != nil {
+		return nil, 0, i18n.Errorf("%s can´t be found. %w", s.name, err)
+	}
+	if record == nil {
+		item, err := s.upstream.Get(ctx, id)
+		return item, 0, err
+	}
+	if record.Deleted {
+		return nil, 0, i18n.Errorf("%s can´t be found. %w", s.name, repository.ErrNotFound)
+	}
+	return &record.Item, record.Version, nil
+}
+
+// Create valida y guarda localmente un elemento nuevo
+func (s *OverlayService[T]) Create(ctx context.Context, item T) (*T, error) {
+	if err := validation.Struct(item); err != nil {
+		return nil, i18n.Errorf("%s can´t be created. %w", s.name, err)
+	}
+	created, err := s.overlay.Create(ctx, item)
+	if err != nil {
+		return nil, i18n.Errorf("%s can´t be created. %w", s.name, err)
+	}
+	return created, nil
+}
+
+// Update valida y reemplaza localmente un elemento existente. Con If-Match lo reemplaza
+// solo si el elemento actual tiene ese ETag y nadie lo cambió desde que se leyó.
+func (s *OverlayService[T]) Update(ctx context.Context, id int, item T) (*T, error) {
+	if _, ok := conditional.IfMatch(ctx); ok {
+		return s.write(ctx, id, func(current T) (T, error) { return item, nil })
+	}
+	if err := validation.Struct(item); err != nil {
+		return nil, i18n.Errorf("%s can´t be updated. %w", s.name, err)
+	}
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	updated, err := s.overlay.Put(ctx, id, item)
+	if err != nil {
+		return nil, i18n.Errorf("%s can´t be updated. %w", s.name, err)
+	}
+	return updated, nil
+}
+
+// Patch aplica apply al elemento, del overlay o del upstream, y guarda el resultado en el
+// overlay. Si otra escritura lo cambió mientras tanto responde un conflicto en lugar de pisarla.
+func (s *OverlayService[T]) Patch(ctx context.Context, id int, apply func(current T) (T, error)) (*T, error) {
+	return s.write(ctx, id, apply)
+}
+
+// Delete marca localmente un elemento existente como borrado. Con If-Match lo marca solo
+// si el elemento actual tiene ese ETag y nadie lo cambió desde que se leyó.
+func (s *OverlayService[T]) Delete(ctx context.Context, id int) error {
+	current, version, err := s.current(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, ok := conditional.IfMatch(ctx); !ok {
+		if err := s.overlay.Delete(ctx, id); err != nil {
+			return i18n.Errorf("%s can´t be deleted. %w", s.name, err)
+		}
+		return nil
+	}
+	if err := conditional.Check(ctx, *current); err != nil {
+		return i18n.Errorf("%s can´t be deleted. %w", s.name, err)
+	}
+	if err := s.overlay.DeleteVersion(ctx, id, version); err != nil {
+		return i18n.Errorf("%s can´t be deleted. %w", s.name, conflict(ctx, err))
+	}
+	return nil
+}
+
+// write lee el elemento con la versión de su cambio local, lo compara con el If-Match de
+// ctx, le aplica apply y guarda el resultado validado solo si todavía tiene esa versión
+func (s *OverlayService[T]) write(ctx context.Context, id int, apply func(current T) (T, error)) (*T, error) {
+	current, version, err := s.current(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := conditional.Check(ctx, *current); err != nil {
+		return nil, i18n.Errorf("%s can´t be updated. %w", s.name, err)
+	}
+	item, err := apply(*current)
+	if err != nil {
+		return nil, i18n.Errorf("%s can´t be updated. %w", s.name, err)
+	}
+	if err := validation.Struct(item); err != nil {
+		return nil, i18n.Errorf("%s can´t be updated. %w", s.name, err)
+	}
+	updated, err := s.overlay.PutVersion(ctx, id, item, version)
+	if err != nil {
+		return nil, i18n.Errorf("%s can´t be updated. %w", s.name, conflict(ctx, err))
+	}
+	return updated, nil
+}
+
+// matches aplica a un elemento local los mismos filtros que resuelve el upstream
+func matches[T any](item T, filter Filter) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	body, err := json.Marshal(item)
+	if err != nil {
+		return false
+	}
+	// UseNumber conserva los números como en la query: con float64 los ids desde
+	// OverlayIDBase se imprimen como 1e+06
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var fields map[string]any
+	if err := decoder.Decode(&fields); err != nil {
+		return false
+	}
+	for key, value := range filter {
+		if fmt.Sprint(fields[key]) != value {
+			return false
+		}
+	}
+	return true
+}
+
+func pointer[T any](items []T) *[]T {
+	return &items
+}
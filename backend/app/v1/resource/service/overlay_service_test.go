@@ -0,0 +1,132 @@
+package resource_test
+
+import (
+	"blog-api/app/conditional"
+	"blog-api/app/mocks"
+	"blog-api/app/pagination"
+	"blog-api/app/repository"
+	resource "blog-api/app/v1/resource/service"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newOverlayService(t *testing.T) (*resource.OverlayService[widget], *mocks.IRestClient) {
+	ctx := context.Background()
+	db, err := repository.Open(ctx, repository.SQLite, ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	migrator, err := repository.NewMigrator(db, repository.SQLite)
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+
+	mockClient := mocks.NewIRestClient(t)
+	upstream := resource.NewResourceService[widget](mockClient, "Widget", widgetsURL)
+	overlay := repository.NewOverlay[widget](repository.NewOverlayStore(db, repository.SQLite), "widgets")
+	return resource.NewOverlayService[widget](upstream, overlay, "Widget"), mockClient
+}
+
+func TestOverlayService(t *testing.T) {
+	ctx := context.Background()
+	service, mockClient := newOverlayService(t)
+	list := `[{"id": 1, "name": "a"}, {"id": 2, "name": "b"}, {"id": 3, "name": "a"}]`
+	mockClient.On("NewRequest", mock.Anything, "GET", widgetsURL, mock.Anything, mock.Anything).Return(func(context.Context, string, string, io.Reader, map[string]string) *http.Response {
+		return response(http.StatusOK, list)
+	}, nil)
+	mockClient.On("NewRequest", mock.Anything, "GET", widgetsURL+"/1", mock.Anything, mock.Anything).Return(func(context.Context, string, string, io.Reader, map[string]string) *http.Response {
+		return response(http.StatusOK, `{"id": 1, "name": "a"}`)
+	}, nil)
+	mockClient.On("NewRequest", mock.Anything, "GET", widgetsURL+"/2", mock.Anything, mock.Anything).Return(func(context.Context, string, string, io.Reader, map[string]string) *http.Response {
+		return response(http.StatusOK, `{"id": 2, "name": "b"}`)
+	}, nil)
+	mockClient.On("NewRequest", mock.Anything, "GET", widgetsURL+"/3", mock.Anything, mock.Anything).Return(func(context.Context, string, string, io.Reader, map[string]string) *http.Response {
+		return response(http.StatusOK, `{"id": 3, "name": "a"}`)
+	}, nil)
+	mockClient.On("NewRequest", mock.Anything, "GET", widgetsURL+"/9", mock.Anything, mock.Anything).Return(response(http.StatusNotFound, `{}`), nil).Once()
+
+	// Sin cambios locales se pide al upstream solo la página
+	page := response(http.StatusOK, `[{"id": 3, "name": "a"}]`)
+	page.Header.Set("X-Total-Count", "3")
+	mockClient.On("NewRequest", mock.Anything, "GET", widgetsURL+"?_limit=2&_page=2", mock.Anything, mock.Anything).Return(page, nil).Once()
+	items, total, err := service.List(ctx, resource.Filter{}, pagination.Pagination{Page: 2, Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, 3, total)
+	require.Equal(t, []widget{{ID: 3, Name: "a"}}, *items)
+
+	created, err := service.Create(ctx, widget{Name: "c"})
+	require.NoError(t, err)
+	require.Equal(t, repository.OverlayIDBase+1, created.ID)
+	_, err = service.Update(ctx, 2, widget{Name: "z"})
+	require.NoError(t, err)
+	require.NoError(t, service.Delete(ctx, 3))
+	_, err = service.Update(ctx, 9, widget{Name: "y"})
+	require.Error(t, err, "only existing items can be updated")
+
+	items, total, err = service.List(ctx, resource.Filter{}, pagination.Pagination{})
+	require.NoError(t, err)
+	require.Equal(t, 3, total)
+	require.Equal(t, []widget{{ID: 1, Name: "a"}, {ID: 2, Name: "z"}, {ID: repository.OverlayIDBase + 1, Name: "c"}}, *items)
+
+	items, total, err = service.List(ctx, resource.Filter{}, pagination.Pagination{Page: 2, Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, 3, total)
+	require.Equal(t, []widget{{ID: repository.OverlayIDBase + 1, Name: "c"}}, *items)
+
+	// Los filtros comparan los ids locales, mayores que OverlayIDBase, como en la query
+	mockClient.On("NewRequest", mock.Anything, "GET", widgetsURL+"?id=1000001", mock.Anything, mock.Anything).Return(func(context.Context, string, string, io.Reader, map[string]string) *http.Response {
+		return response(http.StatusOK, `[]`)
+	}, nil)
+	items, total, err = service.List(ctx, resource.Filter{"id": strconv.Itoa(created.ID)}, pagination.Pagination{})
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	require.Equal(t, []widget{{ID: repository.OverlayIDBase + 1, Name: "c"}}, *items)
+
+	item, err := service.Get(ctx, 2)
+	require.NoError(t, err)
+	require.Equal(t, "z", item.Name)
+	_, err = service.Get(ctx, 3)
+	require.ErrorIs(t, err, repository.ErrNotFound)
+
+	patched, err := service.Patch(ctx, created.ID, func(current widget) (widget, error) {
+		current.Name = "d"
+		return current, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, widget{ID: created.ID, Name: "d"}, *patched)
+
+	// If-Match se compara con el elemento local
+	etag, err := conditional.ETag(*patched)
+	require.NoError(t, err)
+	_, err = service.Update(conditional.NewContext(ctx, `"stale"`), created.ID, widget{Name: "e"})
+	require.ErrorIs(t, err, conditional.ErrPreconditionFailed)
+	require.ErrorIs(t, service.Delete(conditional.NewContext(ctx, `"stale"`), created.ID), conditional.ErrPreconditionFailed)
+	require.NoError(t, service.Delete(conditional.NewContext(ctx, etag), created.ID))
+
+	// Si otra escritura cambia el elemento entre la lectura y la escritura, no se pisa,
+	// tanto para un elemento del upstream como para uno con cambios locales
+	for _, id := range []int{1, 2} {
+		concurrent := func(current widget) (widget, error) {
+			_, err := service.Update(ctx, id, widget{Name: "other"})
+			require.NoError(t, err)
+			current.Name = "mine"
+			return current, nil
+		}
+		current, err := service.Get(ctx, id)
+		require.NoError(t, err)
+		etag, err := conditional.ETag(*current)
+		require.NoError(t, err)
+		_, err = service.Patch(conditional.NewContext(ctx, etag), id, concurrent)
+		require.ErrorIs(t, err, conditional.ErrPreconditionFailed)
+		_, err = service.Patch(ctx, id, concurrent)
+		require.ErrorIs(t, err, repository.ErrVersionConflict)
+		current, err = service.Get(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, widget{ID: id, Name: "other"}, *current)
+	}
+}
@@ -0,0 +1,132 @@
+package resource
+
+import (
+	"blog-api/app/conditional"
+	"blog-api/app/i18n"
+	"blog-api/app/pagination"
+	"blog-api/app/repository"
+	"blog-api/app/validation"
+	"context"
+	"errors"
+)
+
+// RepositoryService implementa el CRUD genérico sobre el almacenamiento local
+type RepositoryService[T any] struct {
+	repo repository.Repository[T]
+	name string
+}
+
+// NewRepositoryService crea el servicio sobre repo; name se usa en los mensajes de error
+func NewRepositoryService[T any](repo repository.Repository[T], name string) *RepositoryService[T] {
+	return &RepositoryService[T]{
+		repo: repo,
+		name: name,
+	}
+}
+
+// List obtiene la colección filtrada y paginada junto con el total de elementos
+func (s *RepositoryService[T]) List(ctx context.Context, filter Filter, page pagination.Pagination) (*[]T, int, error) {
+	items, total, err := s.repo.List(ctx, filter, page)
+	if err != nil {
+		return nil, 0, i18n.Errorf("%ss can´t be listed. %w", s.name, err)
+	}
+	return &items, total, nil
+}
+
+// Get obtiene un elemento por id
+func (s *RepositoryService[T]) Get(ctx context.Context, id int) (*T, error) {
+	item, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, i18n.Errorf("%s can´t be found. %w", s.name, err)
+	}
+	return item, nil
+}
+
+// Create valida y crea un elemento
+func (s *RepositoryService[T]) Create(ctx context.Context, item T) (*T, error) {
+	if err := validation.Struct(item); err != nil {
+		return nil, i18n.Errorf("%s can´t be created. %w", s.name, err)
+	}
+	created, err := s.repo.Create(ctx, item)
+	if err != nil {
+		return nil, i18n.Errorf("%s can´t be created. %w", s.name, err)
+	}
+	return created, nil
+}
+
+// Update valida y reemplaza un elemento. Con If-Match lo reemplaza solo si el elemento
+// actual tiene ese ETag y nadie lo cambió desde que se leyó.
+func (s *RepositoryService[T]) Update(ctx context.Context, id int, item T) (*T, error) {
+	if _, ok := conditional.IfMatch(ctx); ok {
+		return s.write(ctx, id, func(current T) (T, error) { return item, nil })
+	}
+	if err := validation.Struct(item); err != nil {
+		return nil, i18n.Errorf("%s can´t be updated. %w", s.name, err)
+	}
+	updated, err := s.repo.Update(ctx, id, item)
+	if err != nil {
+		return nil, i18n.Errorf("%s can´t be updated. %w", s.name, err)
+	}
+	return updated, nil
+}
+
+// Patch aplica apply al elemento y lo reemplaza con el resultado. Si otra escritura lo
+// cambió mientras tanto responde un conflicto en lugar de pisarla.
+func (s *RepositoryService[T]) Patch(ctx context.Context, id int, apply func(current T) (T, error)) (*T, error) {
+	return s.write(ctx, id, apply)
+}
+
+// Delete elimina un elemento. Con If-Match lo elimina solo si el elemento actual tiene
+// ese ETag y nadie lo cambió desde que se leyó.
+func (s *RepositoryService[T]) Delete(ctx context.Context, id int) error {
+	if _, ok := conditional.IfMatch(ctx); !ok {
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return i18n.Errorf("%s can´t be deleted. %w", s.name, err)
+		}
+		return nil
+	}
+	current, version, err := s.repo.GetVersion(ctx, id)
+	if err != nil {
+		return i18n.Errorf("%s can´t be found. %w", s.name, err)
+	}
+	if err := conditional.Check(ctx, *current); err != nil {
+		return i18n.Errorf("%s can´t be deleted. %w", s.name, err)
+	}
+	if err := s.repo.DeleteVersion(ctx, id, version); err != nil {
+		return i18n.Errorf("%s can´t be deleted. %w", s.name, conflict(ctx, err))
+	}
+	return nil
+}
+
+// write lee el elemento con su versión, lo compara con el If-Match de ctx, le aplica
+// apply y guarda el resultado validado solo si todavía tiene la versión leída
+func (s *RepositoryService[T]) write(ctx context.Context, id int, apply func(current T) (T, error)) (*T, error) {
+	current, version, err := s.repo.GetVersion(ctx, id)
+	if err != nil {
+		return nil, i18n.Errorf("%s can´t be found. %w", s.name, err)
+	}
+	if err := conditional.Check(ctx, *current); err != nil {
+		return nil, i18n.Errorf("%s can´t be updated. %w", s.name, err)
+	}
+	item, err := apply(*current)
+	if err != nil {
+		return nil, i18n.Errorf("%s can´t be updated. %w", s.name, err)
+	}
+	if err := validation.Struct(item); err != nil {
+		return nil, i18n.Errorf("%s can´t be updated. %w", s.name, err)
+	}
+	updated, err := s.repo.UpdateVersion(ctx, id, item, version)
+	if err != nil {
+		return nil, i18n.Errorf("%s can´t be updated. %w", s.name, conflict(ctx, err))
+	}
+	return updated, nil
+}
+
+// conflict convierte un cambio de versión en ErrPreconditionFailed si la escritura tenía
+// If-Match: el ETag que mandó el cliente ya no es el del elemento
+func conflict(ctx context.Context, err error) error {
+	if _, ok := conditional.IfMatch(ctx); ok && errors.Is(err, repository.ErrVersionConflict) {
+		return conditional.ErrPreconditionFailed
+	}
+	return err
+}
@@ -0,0 +1,113 @@
+package resource_test
+
+import (
+	"blog-api/app/conditional"
+	apperrors "blog-api/app/errors"
+	"blog-api/app/pagination"
+	"blog-api/app/repository"
+	resource "blog-api/app/v1/resource/service"
+	"blog-api/app/validation"
+	"blog-api/data"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositoryService(t *testing.T) {
+	ctx := context.Background()
+	db, err := repository.Open(ctx, repository.SQLite, ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	migrator, err := repository.NewMigrator(db, repository.SQLite)
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	service := resource.NewRepositoryService[data.Post](repository.NewPostRepository(db, repository.SQLite), "Post")
+
+	var validationErr *validation.Error
+	_, err = service.Create(ctx, data.Post{Title: "no user"})
+	require.ErrorAs(t, err, &validationErr)
+	require.Equal(t, "userId", validationErr.Fields[0].Field)
+
+	created, err := service.Create(ctx, data.Post{UserID: 1, Title: "a"})
+	require.NoError(t, err)
+	_, err = service.Patch(ctx, created.ID, func(current data.Post) (data.Post, error) {
+		current.Body = "b"
+		return current, nil
+	})
+	require.NoError(t, err)
+	// Update y Patch validan el elemento completo que resulta
+	_, err = service.Update(ctx, created.ID, data.Post{Title: "no user"})
+	require.ErrorAs(t, err, &validationErr)
+	_, err = service.Patch(ctx, created.ID, func(current data.Post) (data.Post, error) {
+		current.UserID = -1
+		return current, nil
+	})
+	require.ErrorAs(t, err, &validationErr)
+
+	posts, total, err := service.List(ctx, resource.Filter{"userId": "1"}, pagination.Pagination{})
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	require.Equal(t, []data.Post{{ID: 1, UserID: 1, Title: "a", Body: "b"}}, *posts)
+
+	require.NoError(t, service.Delete(ctx, created.ID))
+	_, err = service.Get(ctx, created.ID)
+	require.ErrorIs(t, err, repository.ErrNotFound)
+	require.EqualError(t, err, "Post can´t be found. not found")
+}
+
+func TestRepositoryService_Conditional(t *testing.T) {
+	ctx := context.Background()
+	db, err := repository.Open(ctx, repository.SQLite, ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	migrator, err := repository.NewMigrator(db, repository.SQLite)
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	service := resource.NewRepositoryService[data.Todo](repository.NewTodoRepository(db, repository.SQLite), "Todo")
+
+	created, err := service.Create(ctx, data.Todo{UserID: 1, Title: "a"})
+	require.NoError(t, err)
+	etag, err := conditional.ETag(*created)
+	require.NoError(t, err)
+
+	// Un If-Match viejo no escribe nada
+	_, err = service.Update(conditional.NewContext(ctx, `"stale"`), created.ID, data.Todo{UserID: 1, Title: "b"})
+	require.ErrorIs(t, err, conditional.ErrPreconditionFailed)
+	require.ErrorIs(t, service.Delete(conditional.NewContext(ctx, `"stale"`), created.ID), conditional.ErrPreconditionFailed)
+
+	updated, err := service.Update(conditional.NewContext(ctx, etag), created.ID, data.Todo{UserID: 1, Title: "b"})
+	require.NoError(t, err)
+	require.Equal(t, "b", updated.Title)
+	// El ETag anterior ya no sirve
+	_, err = service.Patch(conditional.NewContext(ctx, etag), created.ID, func(current data.Todo) (data.Todo, error) {
+		return current, nil
+	})
+	require.ErrorIs(t, err, conditional.ErrPreconditionFailed)
+
+	// Si otra escritura cambia el elemento entre la lectura y la escritura, el patch no la pisa
+	concurrent := func(current data.Todo) (data.Todo, error) {
+		_, err := service.Update(ctx, created.ID, data.Todo{UserID: 1, Title: "other"})
+		require.NoError(t, err)
+		current.Completed = true
+		return current, nil
+	}
+	_, err = service.Patch(ctx, created.ID, concurrent)
+	require.ErrorIs(t, err, repository.ErrVersionConflict)
+	require.ErrorIs(t, err, apperrors.ErrConflict)
+	current, err := service.Get(ctx, created.ID)
+	require.NoError(t, err)
+	etag, err = conditional.ETag(*current)
+	require.NoError(t, err)
+	_, err = service.Patch(conditional.NewContext(ctx, etag), created.ID, concurrent)
+	require.ErrorIs(t, err, conditional.ErrPreconditionFailed)
+
+	current, err = service.Get(ctx, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, data.Todo{ID: created.ID, UserID: 1, Title: "other"}, *current)
+	etag, err = conditional.ETag(*current)
+	require.NoError(t, err)
+	require.NoError(t, service.Delete(conditional.NewContext(ctx, etag), created.ID))
+}
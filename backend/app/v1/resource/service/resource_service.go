@@ -0,0 +1,366 @@
+package resource
+
+import (
+	"blog-api/app/cache"
+	"blog-api/app/clients/restclient"
+	"blog-api/app/coalesce"
+	"blog-api/app/conditional"
+	apperrors "blog-api/app/errors"
+	"blog-api/app/i18n"
+	"blog-api/app/pagination"
+	"blog-api/app/validation"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrUnprocessable indica que el elemento no se puede guardar porque referencia a otro
+// que no existe o no es compatible
+var ErrUnprocessable = apperrors.ErrUnprocessable
+
+// Filter son los criterios de búsqueda que se envían como query params al upstream
+type Filter map[string]string
+
+// NewFilter toma de la query solo los parámetros permitidos y no vacíos
+func NewFilter(query url.Values, keys ...string) Filter {
+	filter := Filter{}
+	for _, key := range keys {
+		if value := query.Get(key); value != "" {
+			filter[key] = value
+		}
+	}
+	return filter
+}
+
+// IResourceService define el CRUD genérico de un recurso
+type IResourceService[T any] interface {
+	List(ctx context.Context, filter Filter, page pagination.Pagination) (*[]T, int, error)
+	Get(ctx context.Context, id int) (*T, error)
+	Create(ctx context.Context, item T) (*T, error)
+	Update(ctx context.Context, id int, item T) (*T, error)
+	Patch(ctx context.Context, id int, apply func(current T) (T, error)) (*T, error)
+	Delete(ctx context.Context, id int) error
+}
+
+// patch lee el elemento, le aplica apply y lo reemplaza con el resultado, que se valida
+// completo como en Update. Así un patch puede dejar un campo en su valor cero. El
+// If-Match de ctx se compara con el elemento leído.
+func patch[T any](ctx context.Context, service IResourceService[T], name string, id int, apply func(current T) (T, error)) (*T, error) {
+	current, err := service.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := conditional.Check(ctx, *current); err != nil {
+		return nil, i18n.Errorf("%s can´t be updated. %w", name, err)
+	}
+	patched, err := apply(*current)
+	if err != nil {
+		return nil, i18n.Errorf("%s can´t be updated. %w", name, err)
+	}
+	return service.Update(conditional.Without(ctx), id, patched)
+}
+
+// precondition lee el elemento y lo compara con el If-Match de ctx; sin If-Match no lee
+// nada. failed es el formato del error si no coincide, con el nombre y el error.
+func precondition[T any](ctx context.Context, service IResourceService[T], id int, failed string, name string) error {
+	if _, ok := conditional.IfMatch(ctx); !ok {
+		return nil
+	}
+	current, err := service.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := conditional.Check(ctx, *current); err != nil {
+		return i18n.Errorf(failed, name, err)
+	}
+	return nil
+}
+
+// ResourceService implementa el CRUD genérico contra una colección de JSONPlaceholder
+type ResourceService[T any] struct {
+	restClient   restclient.IRestClient
+	name         string
+	baseURL      string
+	cache        cache.Cache
+	policy       cache.Policy
+	revalidating sync.Map
+	inflight     coalesce.Group[loaded]
+	// generation cuenta las invalidaciones; un GET que empezó antes de una no guarda su
+	// respuesta. mu hace atómicos el control con el guardado y el aumento con el borrado.
+	generation atomic.Uint64
+	mu         sync.Mutex
+}
+
+// loaded es la respuesta de un GET con la generación de la caché en que empezó
+type loaded struct {
+	entry      *cache.Entry
+	generation uint64
+}
+
+// Option configura un ResourceService
+type Option func(*options)
+
+type options struct {
+	cache  cache.Cache
+	policy cache.Policy
+}
+
+// WithCache guarda en c las respuestas de List y Get según policy. Las
+// escrituras invalidan todas las entradas de la colección.
+func WithCache(c cache.Cache, policy cache.Policy) Option {
+	return func(o *options) {
+		o.cache = c
+		o.policy = policy
+	}
+}
+
+// NewResourceService crea el servicio de la colección baseURL; name se usa en los mensajes de error
+func NewResourceService[T any](client restclient.IRestClient, name string, baseURL string, opts ...Option) *ResourceService[T] {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &ResourceService[T]{
+		restClient: client,
+		name:       name,
+		baseURL:    baseURL,
+		cache:      o.cache,
+		policy:     o.policy,
+	}
+}
+
+// List obtiene la colección filtrada y paginada junto con el total de elementos
+func (s *ResourceService[T]) List(ctx context.Context, filter Filter, page pagination.Pagination) (*[]T, int, error) {
+	u, err := url.Parse(s.baseURL)
+	if err != nil {
+		return nil, 0, err
+	}
+	q := u.Query()
+	for key, value := range filter {
+		q.Set(key, value)
+	}
+	page.Query(q)
+	// Encode ordena los parámetros, así la URL sirve como clave de la caché
+	u.RawQuery = q.Encode()
+	entry, err := s.fetch(ctx, u.String())
+	if err != nil {
+		return nil, 0, err
+	}
+	if entry.Status != http.StatusOK {
+		return nil, 0, apperrors.FromStatus(entry.Status, "%ss can´t be listed. Status Code: %d", s.name, entry.Status)
+	}
+	var items []T
+	err = json.Unmarshal(entry.Body, &items)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, ok := pagination.Total(entry.Header)
+	switch {
+	case ok:
+	case len(items) > page.Limit:
+		// El upstream ignoró _page y _limit y devolvió la colección completa
+		total = len(items)
+		items = pagination.Apply(items, page)
+	default:
+		// El upstream paginó sin informar el total: se sabe que hay al menos estos
+		total = page.Offset() + len(items)
+	}
+	return &items, total, nil
+}
+
+// Get obtiene un elemento por id
+func (s *ResourceService[T]) Get(ctx context.Context, id int) (*T, error) {
+	entry, err := s.fetch(ctx, s.itemURL(id))
+	if err != nil {
+		return nil, err
+	}
+	if entry.Status != http.StatusOK {
+		return nil, apperrors.FromStatus(entry.Status, "%s can´t be found. Status Code: %d", s.name, entry.Status)
+	}
+	var item T
+	err = json.Unmarshal(entry.Body, &item)
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Create valida y crea un elemento
+func (s *ResourceService[T]) Create(ctx context.Context, item T) (*T, error) {
+	if err := validation.Struct(item); err != nil {
+		return nil, i18n.Errorf("%s can´t be created. %w", s.name, err)
+	}
+	resp, err := s.send(ctx, http.MethodPost, s.baseURL, item)
+	if err != nil {
+		return nil, err
+	}
+	return s.decode(ctx, resp, http.StatusCreated, "%s can´t be created. Status Code: %d")
+}
+
+// Update valida y reemplaza un elemento
+func (s *ResourceService[T]) Update(ctx context.Context, id int, item T) (*T, error) {
+	if err := validation.Struct(item); err != nil {
+		return nil, i18n.Errorf("%s can´t be updated. %w", s.name, err)
+	}
+	// El upstream no tiene versiones: If-Match se compara con el elemento actual antes del PUT
+	if err := precondition[T](ctx, s, id, "%s can´t be updated. %w", s.name); err != nil {
+		return nil, err
+	}
+	resp, err := s.send(ctx, http.MethodPut, s.itemURL(id), item)
+	if err != nil {
+		return nil, err
+	}
+	return s.decode(ctx, resp, http.StatusOK, "%s can´t be updated. Status Code: %d")
+}
+
+// Patch aplica apply al elemento y lo reemplaza en el upstream con PUT
+func (s *ResourceService[T]) Patch(ctx context.Context, id int, apply func(current T) (T, error)) (*T, error) {
+	return patch[T](ctx, s, s.name, id, apply)
+}
+
+// Delete elimina un elemento
+func (s *ResourceService[T]) Delete(ctx context.Context, id int) error {
+	if err := precondition[T](ctx, s, id, "%s can´t be deleted. %w", s.name); err != nil {
+		return err
+	}
+	resp, err := s.restClient.NewRequest(ctx, http.MethodDelete, s.itemURL(id), bytes.NewBuffer(nil), nil)
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp)
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return apperrors.FromStatus(resp.StatusCode, "%s can´t be deleted. Status Code: %d", s.name, resp.StatusCode)
+	}
+	s.invalidate(ctx)
+	return nil
+}
+
+// fetch hace un GET pasando por la caché. Una entrada fresca se responde sin
+// llamar al upstream; una vencida se responde y se revalida en segundo plano.
+func (s *ResourceService[T]) fetch(ctx context.Context, url string) (*cache.Entry, error) {
+	if s.cache == nil {
+		result, err := s.load(ctx, url)
+		return result.entry, err
+	}
+	if entry, ok := s.cache.Get(ctx, url); ok {
+		if s.policy.Fresh(entry, time.Now()) {
+			cache.SetStatus(ctx, cache.Hit)
+		} else {
+			cache.SetStatus(ctx, cache.Stale)
+			go s.revalidate(context.WithoutCancel(ctx), url)
+		}
+		return &entry, nil
+	}
+	cache.SetStatus(ctx, cache.Miss)
+	result, err := s.load(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	s.store(ctx, url, result)
+	return result.entry, nil
+}
+
+// load hace el GET al upstream. Los GET concurrentes a la misma URL comparten una
+// sola llamada; la entrada devuelta es compartida y no se debe modificar.
+func (s *ResourceService[T]) load(ctx context.Context, url string) (loaded, error) {
+	result, err, _ := s.inflight.Do(ctx, url, func(ctx context.Context) (loaded, error) {
+		generation := s.generation.Load()
+		entry, err := s.request(ctx, url)
+		return loaded{entry: entry, generation: generation}, err
+	})
+	return result, err
+}
+
+func (s *ResourceService[T]) request(ctx context.Context, url string) (*cache.Entry, error) {
+	resp, err := s.restClient.NewRequest(ctx, http.MethodGet, url, bytes.NewBuffer(nil), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &cache.Entry{Status: resp.StatusCode, Header: resp.Header, Body: body, StoredAt: time.Now()}, nil
+}
+
+// store guarda solo las respuestas exitosas, y no si una escritura invalidó la caché
+// mientras se hacía el GET: la respuesta puede ser anterior a la escritura
+func (s *ResourceService[T]) store(ctx context.Context, url string, result loaded) {
+	if result.entry.Status != http.StatusOK {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if result.generation == s.generation.Load() {
+		s.cache.Set(ctx, url, *result.entry, s.policy.Lifetime())
+	}
+}
+
+// revalidate renueva una entrada vencida; solo hay una revalidación por URL a la vez
+func (s *ResourceService[T]) revalidate(ctx context.Context, url string) {
+	if _, running := s.revalidating.LoadOrStore(url, struct{}{}); running {
+		return
+	}
+	defer s.revalidating.Delete(url)
+	result, err := s.load(ctx, url)
+	if err != nil {
+		slog.Warn("cache revalidation failed", "url", url, "error", err)
+		return
+	}
+	s.store(ctx, url, result)
+}
+
+// invalidate descarta de la caché la colección y todos sus elementos
+func (s *ResourceService[T]) invalidate(ctx context.Context) {
+	if s.cache != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.generation.Add(1)
+		s.cache.DeletePrefix(ctx, s.baseURL)
+	}
+}
+
+func (s *ResourceService[T]) itemURL(id int) string {
+	return fmt.Sprintf("%s/%d", s.baseURL, id)
+}
+
+func (s *ResourceService[T]) send(ctx context.Context, method string, url string, item T) (*http.Response, error) {
+	body, err := json.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+	headers := map[string]string{"Content-Type": "application/json"}
+	return s.restClient.NewRequest(ctx, method, url, bytes.NewBuffer(body), headers)
+}
+
+// decode lee el elemento de la respuesta de una escritura e invalida la caché; failed es
+// el formato del error si el status no es el esperado, con el nombre y el status
+func (s *ResourceService[T]) decode(ctx context.Context, resp *http.Response, expected int, failed string) (*T, error) {
+	defer closeBody(resp)
+	if resp.StatusCode != expected {
+		return nil, apperrors.FromStatus(resp.StatusCode, failed, s.name, resp.StatusCode)
+	}
+	s.invalidate(ctx)
+	var item T
+	err := json.NewDecoder(resp.Body).Decode(&item)
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func closeBody(resp *http.Response) {
+	if resp.Body != nil {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+}
@@ -0,0 +1,282 @@
+package resource_test
+
+import (
+	"blog-api/app/cache"
+	"blog-api/app/conditional"
+	"blog-api/app/mocks"
+	"blog-api/app/pagination"
+	resource "blog-api/app/v1/resource/service"
+	"blog-api/app/validation"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const widgetsURL = "https://example.com/widgets"
+
+type widget struct {
+	ID   int    `json:"id"`
+	Name string `json:"name" validate:"required"`
+}
+
+func response(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{},
+		Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+	}
+}
+
+func TestNewFilter(t *testing.T) {
+	query := url.Values{"name": {"a"}, "id": {""}, "other": {"x"}}
+	require.Equal(t, resource.Filter{"name": "a"}, resource.NewFilter(query, "id", "name"))
+}
+
+func TestResourceService_List(t *testing.T) {
+	mockClient := mocks.NewIRestClient(t)
+	service := resource.NewResourceService[widget](mockClient, "Widget", widgetsURL)
+	t.Run("success", func(t *testing.T) {
+		mockClient.On("NewRequest", mock.Anything, "GET", widgetsURL+"?name=a", mock.Anything, mock.Anything).Return(response(http.StatusOK, `[{"id": 1, "name": "a"}]`), nil).Once()
+		widgets, total, err := service.List(context.Background(), resource.Filter{"name": "a"}, pagination.Pagination{})
+		require.NoError(t, err)
+		require.Equal(t, 1, total)
+		require.Equal(t, []widget{{ID: 1, Name: "a"}}, *widgets)
+	})
+	t.Run("error in response", func(t *testing.T) {
+		mockClient.On("NewRequest", mock.Anything, "GET", widgetsURL, mock.Anything, mock.Anything).Return(response(http.StatusBadGateway, ``), nil).Once()
+		_, _, err := service.List(context.Background(), resource.Filter{}, pagination.Pagination{})
+		require.Error(t, err)
+	})
+}
+
+func TestResourceService_Get(t *testing.T) {
+	mockClient := mocks.NewIRestClient(t)
+	service := resource.NewResourceService[widget](mockClient, "Widget", widgetsURL)
+	t.Run("success", func(t *testing.T) {
+		mockClient.On("NewRequest", mock.Anything, "GET", widgetsURL+"/1", mock.Anything, mock.Anything).Return(response(http.StatusOK, `{"id": 1, "name": "a"}`), nil).Once()
+		item, err := service.Get(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, widget{ID: 1, Name: "a"}, *item)
+	})
+	t.Run("not found", func(t *testing.T) {
+		mockClient.On("NewRequest", mock.Anything, "GET", widgetsURL+"/2", mock.Anything, mock.Anything).Return(response(http.StatusNotFound, `{}`), nil).Once()
+		_, err := service.Get(context.Background(), 2)
+		require.EqualError(t, err, "Widget can´t be found. Status Code: 404")
+	})
+}
+
+func TestResourceService_Create(t *testing.T) {
+	mockClient := mocks.NewIRestClient(t)
+	service := resource.NewResourceService[widget](mockClient, "Widget", widgetsURL)
+	headers := map[string]string{"Content-Type": "application/json"}
+	t.Run("success", func(t *testing.T) {
+		mockClient.On("NewRequest", mock.Anything, "POST", widgetsURL, mock.Anything, headers).Return(response(http.StatusCreated, `{"id": 11, "name": "a"}`), nil).Once()
+		item, err := service.Create(context.Background(), widget{Name: "a"})
+		require.NoError(t, err)
+		require.Equal(t, widget{ID: 11, Name: "a"}, *item)
+	})
+	t.Run("error in validation", func(t *testing.T) {
+		_, err := service.Create(context.Background(), widget{})
+		require.Error(t, err)
+	})
+	t.Run("error in request", func(t *testing.T) {
+		mockClient.On("NewRequest", mock.Anything, "POST", widgetsURL, mock.Anything, headers).Return(nil, errors.New("request error")).Once()
+		_, err := service.Create(context.Background(), widget{Name: "a"})
+		require.Error(t, err)
+	})
+}
+
+func TestResourceService_Patch(t *testing.T) {
+	mockClient := mocks.NewIRestClient(t)
+	service := resource.NewResourceService[widget](mockClient, "Widget", widgetsURL)
+	// Patch lee el elemento actual, le aplica el cambio y lo reemplaza con PUT
+	mockClient.On("NewRequest", mock.Anything, "GET", widgetsURL+"/1", mock.Anything, mock.Anything).Return(response(http.StatusOK, `{"id": 1, "name": "a"}`), nil).Once()
+	mockClient.On("NewRequest", mock.Anything, "PUT", widgetsURL+"/1", mock.Anything, mock.Anything).Return(response(http.StatusOK, `{"id": 1, "name": "b"}`), nil).Once()
+	var got widget
+	item, err := service.Patch(context.Background(), 1, func(current widget) (widget, error) {
+		got = current
+		current.Name = "b"
+		return current, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, widget{ID: 1, Name: "a"}, got)
+	require.Equal(t, widget{ID: 1, Name: "b"}, *item)
+
+	t.Run("error in apply", func(t *testing.T) {
+		mockClient.On("NewRequest", mock.Anything, "GET", widgetsURL+"/1", mock.Anything, mock.Anything).Return(response(http.StatusOK, `{"id": 1, "name": "a"}`), nil).Once()
+		_, err := service.Patch(context.Background(), 1, func(current widget) (widget, error) {
+			return current, errors.New("test failed")
+		})
+		require.EqualError(t, err, "Widget can´t be updated. test failed")
+	})
+}
+
+func TestResourceService_Conditional(t *testing.T) {
+	mockClient := mocks.NewIRestClient(t)
+	service := resource.NewResourceService[widget](mockClient, "Widget", widgetsURL)
+	etag, err := conditional.ETag(widget{ID: 1, Name: "a"})
+	require.NoError(t, err)
+
+	// Con If-Match se lee el elemento y, si cambió, no se envía la escritura
+	for i := 0; i < 3; i++ {
+		mockClient.On("NewRequest", mock.Anything, "GET", widgetsURL+"/1", mock.Anything, mock.Anything).Return(response(http.StatusOK, `{"id": 1, "name": "b"}`), nil).Once()
+	}
+	_, err = service.Update(conditional.NewContext(context.Background(), etag), 1, widget{Name: "c"})
+	require.ErrorIs(t, err, conditional.ErrPreconditionFailed)
+	_, err = service.Patch(conditional.NewContext(context.Background(), etag), 1, func(current widget) (widget, error) {
+		return current, nil
+	})
+	require.ErrorIs(t, err, conditional.ErrPreconditionFailed)
+	err = service.Delete(conditional.NewContext(context.Background(), etag), 1)
+	require.ErrorIs(t, err, conditional.ErrPreconditionFailed)
+
+	mockClient.On("NewRequest", mock.Anything, "GET", widgetsURL+"/1", mock.Anything, mock.Anything).Return(response(http.StatusOK, `{"id": 1, "name": "a"}`), nil).Once()
+	mockClient.On("NewRequest", mock.Anything, "PUT", widgetsURL+"/1", mock.Anything, mock.Anything).Return(response(http.StatusOK, `{"id": 1, "name": "c"}`), nil).Once()
+	item, err := service.Update(conditional.NewContext(context.Background(), etag), 1, widget{Name: "c"})
+	require.NoError(t, err)
+	require.Equal(t, widget{ID: 1, Name: "c"}, *item)
+}
+
+func TestResourceService_Validation(t *testing.T) {
+	// Los elementos inválidos no llegan al upstream
+	service := resource.NewResourceService[widget](mocks.NewIRestClient(t), "Widget", widgetsURL)
+	var validationErr *validation.Error
+	_, err := service.Update(context.Background(), 1, widget{})
+	require.ErrorAs(t, err, &validationErr)
+	require.EqualError(t, err, "Widget can´t be updated. validation failed: name is required")
+	_, err = service.Create(context.Background(), widget{})
+	require.ErrorAs(t, err, &validationErr)
+}
+
+func TestResourceService_Delete(t *testing.T) {
+	mockClient := mocks.NewIRestClient(t)
+	service := resource.NewResourceService[widget](mockClient, "Widget", widgetsURL)
+	t.Run("success", func(t *testing.T) {
+		mockClient.On("NewRequest", mock.Anything, "DELETE", widgetsURL+"/1", mock.Anything, mock.Anything).Return(&http.Response{StatusCode: http.StatusNoContent}, nil).Once()
+		assert.NoError(t, service.Delete(context.Background(), 1))
+	})
+	t.Run("error in response", func(t *testing.T) {
+		mockClient.On("NewRequest", mock.Anything, "DELETE", widgetsURL+"/1", mock.Anything, mock.Anything).Return(response(http.StatusInternalServerError, ``), nil).Once()
+		assert.Error(t, service.Delete(context.Background(), 1))
+	})
+}
+
+func TestResourceService_Cache(t *testing.T) {
+	mockClient := mocks.NewIRestClient(t)
+	responses := cache.NewLRU(10)
+	service := resource.NewResourceService[widget](mockClient, "Widget", widgetsURL, resource.WithCache(responses, cache.Policy{TTL: time.Minute}))
+
+	t.Run("miss then hit", func(t *testing.T) {
+		mockClient.On("NewRequest", mock.Anything, "GET", widgetsURL+"/1", mock.Anything, mock.Anything).Return(response(http.StatusOK, `{"id": 1, "name": "a"}`), nil).Once()
+		for i := 0; i < 2; i++ {
+			item, err := service.Get(context.Background(), 1)
+			require.NoError(t, err)
+			require.Equal(t, widget{ID: 1, Name: "a"}, *item)
+		}
+	})
+	t.Run("errors are not cached", func(t *testing.T) {
+		mockClient.On("NewRequest", mock.Anything, "GET", widgetsURL+"/2", mock.Anything, mock.Anything).Return(response(http.StatusNotFound, `{}`), nil).Twice()
+		for i := 0; i < 2; i++ {
+			_, err := service.Get(context.Background(), 2)
+			require.Error(t, err)
+		}
+	})
+	t.Run("writes invalidate the collection", func(t *testing.T) {
+		mockClient.On("NewRequest", mock.Anything, "GET", widgetsURL+"?name=a", mock.Anything, mock.Anything).Return(response(http.StatusOK, `[{"id": 1, "name": "a"}]`), nil).Once()
+		_, _, err := service.List(context.Background(), resource.Filter{"name": "a"}, pagination.Pagination{})
+		require.NoError(t, err)
+		require.Equal(t, 2, responses.Len())
+
+		mockClient.On("NewRequest", mock.Anything, "PUT", widgetsURL+"/1", mock.Anything, mock.Anything).Return(response(http.StatusOK, `{"id": 1, "name": "b"}`), nil).Once()
+		_, err = service.Update(context.Background(), 1, widget{Name: "b"})
+		require.NoError(t, err)
+		require.Equal(t, 0, responses.Len())
+	})
+}
+
+func TestResourceService_CacheWriteDuringLoad(t *testing.T) {
+	mockClient := mocks.NewIRestClient(t)
+	responses := cache.NewLRU(10)
+	service := resource.NewResourceService[widget](mockClient, "Widget", widgetsURL, resource.WithCache(responses, cache.Policy{TTL: time.Minute}))
+
+	// El GET lee el elemento antes del PUT pero termina después
+	loading, release := make(chan struct{}), make(chan struct{})
+	mockClient.On("NewRequest", mock.Anything, "GET", widgetsURL+"/1", mock.Anything, mock.Anything).Return(response(http.StatusOK, `{"id": 1, "name": "old"}`), nil).Once().Run(func(mock.Arguments) {
+		close(loading)
+		<-release
+	})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		item, err := service.Get(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "old", item.Name)
+	}()
+	<-loading
+	mockClient.On("NewRequest", mock.Anything, "PUT", widgetsURL+"/1", mock.Anything, mock.Anything).Return(response(http.StatusOK, `{"id": 1, "name": "new"}`), nil).Once()
+	_, err := service.Update(context.Background(), 1, widget{Name: "new"})
+	require.NoError(t, err)
+	close(release)
+	<-done
+	require.Zero(t, responses.Len(), "the response read before the write is not stored")
+
+	mockClient.On("NewRequest", mock.Anything, "GET", widgetsURL+"/1", mock.Anything, mock.Anything).Return(response(http.StatusOK, `{"id": 1, "name": "new"}`), nil).Once()
+	item, err := service.Get(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, "new", item.Name)
+	require.Equal(t, 1, responses.Len())
+}
+
+func TestResourceService_CacheStale(t *testing.T) {
+	mockClient := mocks.NewIRestClient(t)
+	responses := cache.NewLRU(10)
+	service := resource.NewResourceService[widget](mockClient, "Widget", widgetsURL, resource.WithCache(responses, cache.Policy{Stale: time.Hour}))
+	responses.Set(context.Background(), widgetsURL+"/1", cache.Entry{Status: http.StatusOK, Body: []byte(`{"id": 1, "name": "old"}`)}, time.Hour)
+
+	revalidated := make(chan struct{})
+	mockClient.On("NewRequest", mock.Anything, "GET", widgetsURL+"/1", mock.Anything, mock.Anything).Return(response(http.StatusOK, `{"id": 1, "name": "new"}`), nil).Once().Run(func(mock.Arguments) {
+		close(revalidated)
+	})
+	item, err := service.Get(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, "old", item.Name)
+
+	<-revalidated
+	require.Eventually(t, func() bool {
+		entry, _ := responses.Get(context.Background(), widgetsURL+"/1")
+		return string(entry.Body) == `{"id": 1, "name": "new"}`
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestResourceService_Coalesces(t *testing.T) {
+	mockClient := mocks.NewIRestClient(t)
+	service := resource.NewResourceService[widget](mockClient, "Widget", widgetsURL)
+	release := make(chan struct{})
+	mockClient.On("NewRequest", mock.Anything, "GET", widgetsURL+"/1", mock.Anything, mock.Anything).Return(response(http.StatusOK, `{"id": 1, "name": "a"}`), nil).Once().Run(func(mock.Arguments) {
+		<-release
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			item, err := service.Get(context.Background(), 1)
+			assert.NoError(t, err)
+			assert.Equal(t, widget{ID: 1, Name: "a"}, *item)
+		}()
+	}
+	// Los que lleguen mientras la llamada está bloqueada se unen a ella
+	time.AfterFunc(100*time.Millisecond, func() { close(release) })
+	wg.Wait()
+}
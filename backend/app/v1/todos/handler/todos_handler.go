@@ -1,23 +1,42 @@
 package todos
 
 import (
+	resource "blog-api/app/v1/resource/handler"
 	todos "blog-api/app/v1/todos/service"
 	"blog-api/data"
-	"encoding/json"
-	"github.com/go-chi/chi/v5"
 	"net/http"
-	"strconv"
 )
 
 // TodoHandler maneja las solicitudes relacionadas con todos
 type TodoHandler struct {
-	postService todos.ITodoService
+	*resource.ResourceHandler[data.Todo]
 }
 
 // NewTodoHandler crea una nueva instancia del manejador de todos
-func NewTodoHandler(postService todos.ITodoService) *TodoHandler {
+func NewTodoHandler(todoService todos.ITodoService) *TodoHandler {
 	return &TodoHandler{
-		postService: postService,
+		ResourceHandler: resource.NewResourceHandler(resource.Operations[data.Todo]{
+			List:   todoService.GetTodos,
+			Get:    todoService.GetTodo,
+			Create: todoService.CreateTodo,
+			Update: todoService.UpdateTodo,
+			Patch:  todoService.PatchTodo,
+			Delete: todoService.DeleteTodo,
+		}, "todoID", todos.Filters...),
+	}
+}
+
+// Routes devuelve las rutas de todos para MountResource
+func (ph *TodoHandler) Routes() resource.Routes {
+	return resource.Routes{
+		IDParam: ph.IDParam(),
+		List:    ph.GetTodos,
+		Create:  ph.CreateTodo,
+		Get:     ph.GetTodo,
+		Update:  ph.UpdateTodo,
+		Patch:   ph.PatchTodo,
+		Delete:  ph.DeleteTodo,
+		Bulk:    ph.BulkTodos,
 	}
 }
 
@@ -29,18 +48,12 @@ func NewTodoHandler(postService todos.ITodoService) *TodoHandler {
 // @Produce      json
 // @Success      200
 // @Failure      500
+// @Param        page   query int    false "Page number"
+// @Param        limit  query int    false "Page size"
+// @Param        cursor query string false "Opaque cursor from X-Next-Cursor"
 // @Router       ///v1/todos [get] .
 func (ph *TodoHandler) GetTodos(w http.ResponseWriter, r *http.Request) {
-	title := r.URL.Query().Get("title")
-	userID, _ := strconv.Atoi(r.URL.Query().Get("userId"))
-	id, _ := strconv.Atoi(r.URL.Query().Get("id"))
-	todos, err := ph.postService.GetTodos(title, userID, id)
-	if err != nil {
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(todos)
+	ph.List(w, r)
 }
 
 // GetTodo godoc
@@ -50,25 +63,12 @@ func (ph *TodoHandler) GetTodos(w http.ResponseWriter, r *http.Request) {
 // @Accept		 json
 // @Produce      json
 // @Success      200
+// @Success      304
 // @Failure      400
 // @Failure      404
-// @Router       ///v1/post/{postId} [get] .
+// @Router       ///v1/post/{todoID} [get] .
 func (ph *TodoHandler) GetTodo(w http.ResponseWriter, r *http.Request) {
-	postID := chi.URLParam(r, "postID")
-	id, err := strconv.Atoi(postID)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	post, err := ph.postService.GetTodo(id)
-	if err != nil {
-		http.Error(w, "Not Found", http.StatusNotFound)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(post)
+	ph.Get(w, r)
 }
 
 // CreateTodo GetTodo godoc
@@ -82,26 +82,11 @@ func (ph *TodoHandler) GetTodo(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Success      201
 // @Failure      400
+// @Failure      422
 // @Failure      500
-// @Router       ///v1/post/{postId} [post] .
+// @Router       ///v1/post/{todoID} [post] .
 func (ph *TodoHandler) CreateTodo(w http.ResponseWriter, r *http.Request) {
-	var post data.Todo
-	err := json.NewDecoder(r.Body).Decode(&post)
-	if err != nil {
-
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	createdTodo, err := ph.postService.CreateTodo(post)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(createdTodo)
+	ph.Create(w, r)
 }
 
 // UpdateTodo godoc
@@ -116,38 +101,19 @@ func (ph *TodoHandler) CreateTodo(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Success      200
 // @Failure      400
+// @Failure      412
+// @Failure      422
 // @Failure      500
-// @Router       ///v1/post/{postId} [post] .
+// @Router       ///v1/post/{todoID} [post] .
 func (ph *TodoHandler) UpdateTodo(w http.ResponseWriter, r *http.Request) {
-	postID := chi.URLParam(r, "postID")
-	id, err := strconv.Atoi(postID)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	var post data.Todo
-	err = json.NewDecoder(r.Body).Decode(&post)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	updatedTodo, err := ph.postService.UpdateTodo(id, post)
-	if err != nil {
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(updatedTodo)
+	ph.Update(w, r)
 }
 
 // PatchTodo godoc
 // @Description Handler to patch post
 // @Tags Todos
 // @Description.markdown patch post
-// @Accept		 json
+// @Accept		 json,application/merge-patch+json,application/json-patch+json
 // @UrlParam  		TodoID path string true "TodoID"
 // @Param  		UserID path string true "UserID"
 // @Param  		Title path string true "Title"
@@ -155,31 +121,13 @@ func (ph *TodoHandler) UpdateTodo(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Success      200
 // @Failure      400
+// @Failure      412
+// @Failure      415
+// @Failure      422
 // @Failure      500
-// @Router       ///v1/post/{postId} [patch] .
+// @Router       ///v1/post/{todoID} [patch] .
 func (ph *TodoHandler) PatchTodo(w http.ResponseWriter, r *http.Request) {
-	postID := chi.URLParam(r, "postID")
-	id, err := strconv.Atoi(postID)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	var post data.Todo
-	err = json.NewDecoder(r.Body).Decode(&post)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	patchedTodo, err := ph.postService.UpdateTodo(id, post)
-	if err != nil {
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(patchedTodo)
+	ph.Patch(w, r)
 }
 
 // DeleteTodo godoc
@@ -191,21 +139,27 @@ func (ph *TodoHandler) PatchTodo(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Success      204
 // @Failure      400
+// @Failure      412
+// @Failure      422
 // @Failure      500
-// @Router       ///v1/post/{postId} [patch] .
+// @Router       ///v1/post/{todoID} [patch] .
 func (ph *TodoHandler) DeleteTodo(w http.ResponseWriter, r *http.Request) {
-	postID := chi.URLParam(r, "postID")
-	id, err := strconv.Atoi(postID)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	err = ph.postService.DeleteTodo(id)
-	if err != nil {
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
+	ph.Delete(w, r)
+}
 
-	w.WriteHeader(http.StatusNoContent)
+// BulkTodos godoc
+// @Description Handler to create, update and delete todos in one request
+// @Tags Todos
+// @Description.markdown bulk todos
+// @Accept		 json,application/x-ndjson
+// @Produce      json
+// @Param        atomic query bool false "Apply all operations or none"
+// @Success      207
+// @Failure      400
+// @Failure      413
+// @Failure      415
+// @Failure      500
+// @Router       ///v1/todos/bulk [post] .
+func (ph *TodoHandler) BulkTodos(w http.ResponseWriter, r *http.Request) {
+	ph.Bulk(w, r)
 }
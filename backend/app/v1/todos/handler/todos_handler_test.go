@@ -2,11 +2,14 @@ package todos
 
 import (
 	"blog-api/app/mocks"
+	"blog-api/app/pagination"
+	resource "blog-api/app/v1/resource/service"
 	"blog-api/data"
 	"bytes"
 	"context"
 	"encoding/json"
 	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"net/http"
 	"net/http/httptest"
@@ -20,9 +23,9 @@ func TestGetTodos_Success(t *testing.T) {
 	// Mock expected todos
 	mockTodos := []data.Todo{{ID: 1, Title: "My Todo", UserID: 1}}
 	// Set mock expectations
-	mockTodoService.On("GetTodos", "", 0, 0).Return(&mockTodos, nil)
+	mockTodoService.On("GetTodos", mock.Anything, resource.Filter{}, pagination.Pagination{}).Return(&mockTodos, len(mockTodos), nil)
 	// Create handler and request
-	handler := TodoHandler{postService: mockTodoService}
+	handler := NewTodoHandler(mockTodoService)
 	req, _ := http.NewRequest("GET", "/todos", nil)
 	// Create mock response recorder
 	mockRecorder := httptest.NewRecorder()
@@ -43,11 +46,11 @@ func TestGetTodo_Success(t *testing.T) {
 	// Mock expected post
 	mockTodo := data.Todo{ID: 1, Title: "My Todo", UserID: 1}
 	// Set mock expectations
-	mockTodoService.On("GetTodo", 1).Return(&mockTodo, nil)
+	mockTodoService.On("GetTodo", mock.Anything, 1).Return(&mockTodo, nil)
 	// Create handler and request
-	handler := &TodoHandler{postService: mockTodoService}
+	handler := NewTodoHandler(mockTodoService)
 	req, _ := http.NewRequest("GET", "/todos/1", nil)
-	ctx.URLParams.Add("postID", "1")
+	ctx.URLParams.Add("todoID", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
 	// Mock response recorder
 	mockRecorder := httptest.NewRecorder()
@@ -68,9 +71,9 @@ func TestCreateTodo_Success(t *testing.T) {
 	mockTodo := data.Todo{Title: "My Todo", UserID: 1}
 	mockCreatedTodo := data.Todo{ID: 1, Title: "My Todo", UserID: 1}
 	// Set mock expectations
-	mockTodoService.On("CreateTodo", mockTodo).Return(&mockCreatedTodo, nil)
+	mockTodoService.On("CreateTodo", mock.Anything, mockTodo).Return(&mockCreatedTodo, nil)
 	// Create handler and request
-	handler := &TodoHandler{postService: mockTodoService}
+	handler := NewTodoHandler(mockTodoService)
 	reqBody, err := json.Marshal(mockTodo)
 	require.NoError(t, err)
 	req, _ := http.NewRequest(http.MethodPost, "/todos", bytes.NewReader(reqBody))
@@ -95,15 +98,15 @@ func TestUpdateTodo_Success(t *testing.T) {
 	mockUpdatedTodo := data.Todo{ID: 1, Title: "Updated Todo", UserID: 1}
 
 	// Set mock expectations
-	mockTodoService.On("UpdateTodo", 1, mockTodo).Return(&mockUpdatedTodo, nil)
+	mockTodoService.On("UpdateTodo", mock.Anything, 1, mockTodo).Return(&mockUpdatedTodo, nil)
 
 	// Create handler and request
-	handler := &TodoHandler{postService: mockTodoService}
+	handler := NewTodoHandler(mockTodoService)
 	reqBody, err := json.Marshal(mockTodo)
 	require.NoError(t, err)
 	req, _ := http.NewRequest(http.MethodPut, "/todos/1", bytes.NewReader(reqBody))
 	ctx := chi.NewRouteContext()
-	ctx.URLParams.Add("postID", "1")
+	ctx.URLParams.Add("todoID", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
 
 	// Mock response recorder
@@ -124,20 +127,24 @@ func TestPatchTodo_Success(t *testing.T) {
 	mockTodoService := mocks.NewITodoService(t)
 	defer mockTodoService.AssertExpectations(t)
 
-	// Mock expected post with only updated title
-	mockTodo := data.Todo{Title: "Updated Title"}
+	// Mock current todo and the expected one after applying the patch
+	mockCurrentTodo := data.Todo{ID: 1, Title: "Old Title", UserID: 1}
 	mockPatchedTodo := data.Todo{ID: 1, Title: "Updated Title", UserID: 1}
 
-	// Set mock expectations
-	mockTodoService.On("UpdateTodo", 1, mockTodo).Return(&mockPatchedTodo, nil)
+	// Set mock expectations: the service applies the patch to the current todo
+	mockTodoService.On("PatchTodo", mock.Anything, 1, mock.Anything).Return(
+		func(ctx context.Context, id int, apply func(data.Todo) (data.Todo, error)) (*data.Todo, error) {
+			patched, err := apply(mockCurrentTodo)
+			return &patched, err
+		})
 
-	// Create handler and request with only title in the body
-	handler := &TodoHandler{postService: mockTodoService}
-	reqBody, err := json.Marshal(mockTodo)
-	require.NoError(t, err)
+	// Create handler and request with a merge patch of the title
+	handler := NewTodoHandler(mockTodoService)
+	reqBody := []byte(`{"title": "Updated Title"}`)
 	req, _ := http.NewRequest(http.MethodPatch, "/todos/1", bytes.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
 	ctx := chi.NewRouteContext()
-	ctx.URLParams.Add("postID", "1")
+	ctx.URLParams.Add("todoID", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
 
 	// Mock response recorder
@@ -159,13 +166,13 @@ func TestDeleteTodo_Success(t *testing.T) {
 	defer mockTodoService.AssertExpectations(t)
 
 	// Set mock expectations
-	mockTodoService.On("DeleteTodo", 1).Return(nil)
+	mockTodoService.On("DeleteTodo", mock.Anything, 1).Return(nil)
 
 	// Create handler and request
-	handler := &TodoHandler{postService: mockTodoService}
+	handler := NewTodoHandler(mockTodoService)
 	req, _ := http.NewRequest(http.MethodDelete, "/todos/1", nil)
 	ctx := chi.NewRouteContext()
-	ctx.URLParams.Add("postID", "1")
+	ctx.URLParams.Add("todoID", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
 
 	// Mock response recorder
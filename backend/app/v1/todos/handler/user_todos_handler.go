@@ -0,0 +1,65 @@
+package todos
+
+import (
+	resource "blog-api/app/v1/resource/handler"
+	todos "blog-api/app/v1/todos/service"
+	"blog-api/data"
+	"net/http"
+)
+
+// UserTodosHandler maneja los todos de un user: /users/{userID}/todos
+type UserTodosHandler struct {
+	*resource.NestedHandler[data.Todo]
+}
+
+// NewUserTodosHandler crea el manejador; user comprueba que exista el user
+func NewUserTodosHandler(todoService todos.ITodoService, user resource.Parent) *UserTodosHandler {
+	handler := resource.NewResourceHandler(resource.Operations[data.Todo]{
+		List:   todoService.GetTodos,
+		Create: todoService.CreateTodo,
+	}, "todoID", todos.Filters...)
+	return &UserTodosHandler{
+		NestedHandler: resource.NewNestedHandler(handler, user, "userID", "userId", func(item *data.Todo, id int) {
+			item.UserID = id
+		}),
+	}
+}
+
+// Mount devuelve la opción de MountResource que registra las rutas bajo /users/{userID}
+func (h *UserTodosHandler) Mount() resource.MountOption {
+	return resource.MountNested("/todos", h.GetUserTodos, h.CreateUserTodo)
+}
+
+// GetUserTodos godoc
+// @Description  Handler to get the todos of a user
+// @Tags Todos
+// @Accept		 json
+// @Produce      json
+// @Param        userID path  int    true  "User ID"
+// @Param        page   query int    false "Page number"
+// @Param        limit  query int    false "Page size"
+// @Param        cursor query string false "Opaque cursor from X-Next-Cursor"
+// @Success      200
+// @Failure      400
+// @Failure      404
+// @Failure      500
+// @Router       ///v1/users/{userID}/todos [get] .
+func (h *UserTodosHandler) GetUserTodos(w http.ResponseWriter, r *http.Request) {
+	h.List(w, r)
+}
+
+// CreateUserTodo godoc
+// @Description  Handler to create a todo of a user; userId is taken from the path
+// @Tags Todos
+// @Accept		 json
+// @Produce      json
+// @Param        userID path  int    true  "User ID"
+// @Success      201
+// @Failure      400
+// @Failure      422
+// @Failure      404
+// @Failure      500
+// @Router       ///v1/users/{userID}/todos [post] .
+func (h *UserTodosHandler) CreateUserTodo(w http.ResponseWriter, r *http.Request) {
+	h.Create(w, r)
+}
@@ -1,9 +1,13 @@
 package todos
 
 import (
+	"blog-api/app/config"
 	"blog-api/app/mocks"
+	"blog-api/app/pagination"
+	resource "blog-api/app/v1/resource/service"
 	data "blog-api/data"
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -16,6 +20,10 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+var upstream = config.Upstream{Root: "https://jsonplaceholder.typicode.com", Path: "todos"}
+
+var baseUrl = upstream.URL()
+
 type MockReader struct {
 	*bytes.Reader
 }
@@ -31,10 +39,10 @@ func TestTodoService_GetTodos_Success(t *testing.T) {
 	}
 	//mockResp.Body.Read([]byte(`[{"id": 1, "title": "Test Title", "userId": 10}]`))
 	mockClient := mocks.NewIRestClient(t)
-	mockClient.On("NewRequest", "GET", "https://jsonplaceholder.typicode.com/todos?id=1&title=Test+Title&userId=10", mock.Anything, mock.Anything).Return(mockResp, nil)
+	mockClient.On("NewRequest", mock.Anything, "GET", "https://jsonplaceholder.typicode.com/todos?id=1&title=Test+Title&userId=10", mock.Anything, mock.Anything).Return(mockResp, nil)
 	//
-	service := &TodoService{restClient: mockClient}
-	todos, err := service.GetTodos("Test Title", 10, 1)
+	service := NewTodoService(mockClient, upstream)
+	todos, _, err := service.GetTodos(context.Background(), resource.Filter{"title": "Test Title", "userId": "10", "id": "1"}, pagination.Pagination{})
 	require.NoError(t, err)
 	require.NotNil(t, todos)
 	require.Equal(t, 1, len(*todos))
@@ -42,10 +50,10 @@ func TestTodoService_GetTodos_Success(t *testing.T) {
 
 func TestTodoService_GetTodos_ErrorInRequest(t *testing.T) {
 	mockClient := mocks.NewIRestClient(t)
-	mockClient.On("NewRequest", "GET", "https://jsonplaceholder.typicode.com/todos?id=1&title=Test+Title&userId=10", mock.Anything, mock.Anything).Return(nil, errors.New("request error"))
+	mockClient.On("NewRequest", mock.Anything, "GET", "https://jsonplaceholder.typicode.com/todos?id=1&title=Test+Title&userId=10", mock.Anything, mock.Anything).Return(nil, errors.New("request error"))
 
-	service := &TodoService{restClient: mockClient}
-	_, err := service.GetTodos("Test Title", 10, 1)
+	service := NewTodoService(mockClient, upstream)
+	_, _, err := service.GetTodos(context.Background(), resource.Filter{"title": "Test Title", "userId": "10", "id": "1"}, pagination.Pagination{})
 	require.Error(t, err)
 }
 
@@ -62,10 +70,10 @@ func TestTodoService_GetTodo_Success(t *testing.T) {
 		Body:       io.NopCloser(bytes.NewReader([]byte(`{"id": 1, "title": "Test Title", "userId": 10}`))),
 	}
 	mockClient := mocks.NewIRestClient(t)
-	mockClient.On("NewRequest", "GET", fmt.Sprintf("%s/%d", baseUrl, mockTodo.ID), mock.Anything, mock.Anything).Return(mockResp, nil)
+	mockClient.On("NewRequest", mock.Anything, "GET", fmt.Sprintf("%s/%d", baseUrl, mockTodo.ID), mock.Anything, mock.Anything).Return(mockResp, nil)
 	// Create service and call GetTodo
-	service := &TodoService{restClient: mockClient}
-	album, err := service.GetTodo(mockTodo.ID)
+	service := NewTodoService(mockClient, upstream)
+	album, err := service.GetTodo(context.Background(), mockTodo.ID)
 	require.NoError(t, err)
 	require.NotNil(t, album)
 	require.Equal(t, *mockTodo, *album)
@@ -73,19 +81,19 @@ func TestTodoService_GetTodo_Success(t *testing.T) {
 
 func TestCreateTodo(t *testing.T) {
 	mockClient := mocks.NewIRestClient(t)
-	service := &TodoService{restClient: mockClient}
+	service := NewTodoService(mockClient, upstream)
 	album := data.Todo{
 		ID:     1,
 		Title:  "Test Title",
 		UserID: 10,
 	}
 	t.Run("success", func(t *testing.T) {
-		mockClient.On("NewRequest", "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: http.StatusCreated,
 			Body:       io.NopCloser(bytes.NewReader([]byte(`{"id": 1, "title": "Test Title", "userId": 10}`))),
-		}, nil)
+		}, nil).Once()
 
-		createdTodo, err := service.CreateTodo(album)
+		createdTodo, err := service.CreateTodo(context.Background(), album)
 		assert.NoError(t, err)
 		assert.Equal(t, album, *createdTodo)
 	})
@@ -95,37 +103,37 @@ func TestCreateTodo(t *testing.T) {
 			Title:  "", // Title is required
 			UserID: 10,
 		}
-		_, err := service.CreateTodo(invalidTodo)
+		_, err := service.CreateTodo(context.Background(), invalidTodo)
 		assert.Error(t, err)
 	})
 	t.Run("error in request", func(t *testing.T) {
-		mockClient.On("NewRequest", "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(nil, errors.New("request error"))
+		mockClient.On("NewRequest", mock.Anything, "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(nil, errors.New("request error")).Once()
 
-		_, err := service.CreateTodo(album)
+		_, err := service.CreateTodo(context.Background(), album)
 		assert.Error(t, err)
 	})
 	t.Run("error in response", func(t *testing.T) {
-		mockClient.On("NewRequest", "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: 500,
 			Body:       ioutil.NopCloser(bytes.NewReader([]byte(``))),
-		}, nil)
+		}, nil).Once()
 
-		_, err := service.CreateTodo(album)
+		_, err := service.CreateTodo(context.Background(), album)
 		assert.Error(t, err)
 	})
 	t.Run("error in decoding", func(t *testing.T) {
-		mockClient.On("NewRequest", "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: http.StatusCreated,
 			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`not a valid json`))),
-		}, nil)
-		_, err := service.CreateTodo(album)
+		}, nil).Once()
+		_, err := service.CreateTodo(context.Background(), album)
 		assert.Error(t, err)
 	})
 }
 
 func TestUpdateTodo(t *testing.T) {
 	mockClient := mocks.NewIRestClient(t)
-	service := &TodoService{restClient: mockClient}
+	service := NewTodoService(mockClient, upstream)
 
 	album := data.Todo{
 		ID:     1,
@@ -134,53 +142,53 @@ func TestUpdateTodo(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mockClient.On("NewRequest", "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: http.StatusOK,
 			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"id": 1, "title": "Updated Title", "userId": 10}`))),
-		}, nil)
+		}, nil).Once()
 
-		updatedTodo, err := service.UpdateTodo(album.ID, album)
+		updatedTodo, err := service.UpdateTodo(context.Background(), album.ID, album)
 		assert.NoError(t, err)
 		assert.Equal(t, album, *updatedTodo)
 	})
 
 	t.Run("error in request", func(t *testing.T) {
-		mockClient.On("NewRequest", "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(nil, errors.New("request error"))
+		mockClient.On("NewRequest", mock.Anything, "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(nil, errors.New("request error")).Once()
 
-		_, err := service.UpdateTodo(album.ID, album)
+		_, err := service.UpdateTodo(context.Background(), album.ID, album)
 		assert.Error(t, err)
 	})
 
 	t.Run("error in response", func(t *testing.T) {
-		mockClient.On("NewRequest", "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: 500,
 			Body:       ioutil.NopCloser(bytes.NewReader([]byte(``))),
-		}, nil)
+		}, nil).Once()
 
-		_, err := service.UpdateTodo(album.ID, album)
+		_, err := service.UpdateTodo(context.Background(), album.ID, album)
 		assert.Error(t, err)
 	})
 
 	t.Run("error in decoding", func(t *testing.T) {
-		mockClient.On("NewRequest", "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: http.StatusOK,
 			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`not a valid json`))),
-		}, nil)
+		}, nil).Once()
 
-		_, err := service.UpdateTodo(album.ID, album)
+		_, err := service.UpdateTodo(context.Background(), album.ID, album)
 		assert.Error(t, err)
 	})
 }
 
 func TestDeleteTodo(t *testing.T) {
 	mockClient := mocks.NewIRestClient(t)
-	service := &TodoService{restClient: mockClient}
+	service := NewTodoService(mockClient, upstream)
 	albumID := 1
 	t.Run("success", func(t *testing.T) {
-		mockClient.On("NewRequest", "DELETE", fmt.Sprintf("%s/%d", baseUrl, albumID), mock.Anything, mock.Anything).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "DELETE", fmt.Sprintf("%s/%d", baseUrl, albumID), mock.Anything, mock.Anything).Return(&http.Response{
 			StatusCode: http.StatusOK,
-		}, nil)
-		err := service.DeleteTodo(albumID)
+		}, nil).Once()
+		err := service.DeleteTodo(context.Background(), albumID)
 		assert.NoError(t, err)
 	})
 }
@@ -1,23 +1,42 @@
 package users
 
 import (
+	resource "blog-api/app/v1/resource/handler"
 	users "blog-api/app/v1/users/service"
 	"blog-api/data"
-	"encoding/json"
-	"github.com/go-chi/chi/v5"
 	"net/http"
-	"strconv"
 )
 
 // UserHandler maneja las solicitudes relacionadas con users
 type UserHandler struct {
-	postService users.IUserService
+	*resource.ResourceHandler[data.User]
 }
 
 // NewUserHandler crea una nueva instancia del manejador de users
-func NewUserHandler(postService users.IUserService) *UserHandler {
+func NewUserHandler(userService users.IUserService) *UserHandler {
 	return &UserHandler{
-		postService: postService,
+		ResourceHandler: resource.NewResourceHandler(resource.Operations[data.User]{
+			List:   userService.GetUsers,
+			Get:    userService.GetUser,
+			Create: userService.CreateUser,
+			Update: userService.UpdateUser,
+			Patch:  userService.PatchUser,
+			Delete: userService.DeleteUser,
+		}, "userID", users.Filters...),
+	}
+}
+
+// Routes devuelve las rutas de users para MountResource
+func (ph *UserHandler) Routes() resource.Routes {
+	return resource.Routes{
+		IDParam: ph.IDParam(),
+		List:    ph.GetUsers,
+		Create:  ph.CreateUser,
+		Get:     ph.GetUser,
+		Update:  ph.UpdateUser,
+		Patch:   ph.PatchUser,
+		Delete:  ph.DeleteUser,
+		Bulk:    ph.BulkUsers,
 	}
 }
 
@@ -29,18 +48,12 @@ func NewUserHandler(postService users.IUserService) *UserHandler {
 // @Produce      json
 // @Success      200
 // @Failure      500
+// @Param        page   query int    false "Page number"
+// @Param        limit  query int    false "Page size"
+// @Param        cursor query string false "Opaque cursor from X-Next-Cursor"
 // @Router       ///v1/users [get] .
 func (ph *UserHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
-	title := r.URL.Query().Get("title")
-	userID, _ := strconv.Atoi(r.URL.Query().Get("userId"))
-	id, _ := strconv.Atoi(r.URL.Query().Get("id"))
-	users, err := ph.postService.GetUsers(title, userID, id)
-	if err != nil {
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(users)
+	ph.List(w, r)
 }
 
 // GetUser godoc
@@ -50,25 +63,12 @@ func (ph *UserHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
 // @Accept		 json
 // @Produce      json
 // @Success      200
+// @Success      304
 // @Failure      400
 // @Failure      404
-// @Router       ///v1/post/{postId} [get] .
+// @Router       ///v1/post/{userID} [get] .
 func (ph *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
-	postID := chi.URLParam(r, "postID")
-	id, err := strconv.Atoi(postID)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	post, err := ph.postService.GetUser(id)
-	if err != nil {
-		http.Error(w, "Not Found", http.StatusNotFound)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(post)
+	ph.Get(w, r)
 }
 
 // CreateUser GetUser godoc
@@ -82,26 +82,11 @@ func (ph *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Success      201
 // @Failure      400
+// @Failure      422
 // @Failure      500
-// @Router       ///v1/post/{postId} [post] .
+// @Router       ///v1/post/{userID} [post] .
 func (ph *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
-	var post data.User
-	err := json.NewDecoder(r.Body).Decode(&post)
-	if err != nil {
-
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	createdUser, err := ph.postService.CreateUser(post)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(createdUser)
+	ph.Create(w, r)
 }
 
 // UpdateUser godoc
@@ -116,38 +101,19 @@ func (ph *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Success      200
 // @Failure      400
+// @Failure      412
+// @Failure      422
 // @Failure      500
-// @Router       ///v1/post/{postId} [post] .
+// @Router       ///v1/post/{userID} [post] .
 func (ph *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
-	postID := chi.URLParam(r, "postID")
-	id, err := strconv.Atoi(postID)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	var post data.User
-	err = json.NewDecoder(r.Body).Decode(&post)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	updatedUser, err := ph.postService.UpdateUser(id, post)
-	if err != nil {
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(updatedUser)
+	ph.Update(w, r)
 }
 
 // PatchUser godoc
 // @Description Handler to patch post
 // @Tags Users
 // @Description.markdown patch post
-// @Accept		 json
+// @Accept		 json,application/merge-patch+json,application/json-patch+json
 // @UrlParam  		UserID path string true "UserID"
 // @Param  		UserID path string true "UserID"
 // @Param  		Title path string true "Title"
@@ -155,31 +121,13 @@ func (ph *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Success      200
 // @Failure      400
+// @Failure      412
+// @Failure      415
+// @Failure      422
 // @Failure      500
-// @Router       ///v1/post/{postId} [patch] .
+// @Router       ///v1/post/{userID} [patch] .
 func (ph *UserHandler) PatchUser(w http.ResponseWriter, r *http.Request) {
-	postID := chi.URLParam(r, "postID")
-	id, err := strconv.Atoi(postID)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	var post data.User
-	err = json.NewDecoder(r.Body).Decode(&post)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	patchedUser, err := ph.postService.UpdateUser(id, post)
-	if err != nil {
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(patchedUser)
+	ph.Patch(w, r)
 }
 
 // DeleteUser godoc
@@ -191,21 +139,27 @@ func (ph *UserHandler) PatchUser(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Success      204
 // @Failure      400
+// @Failure      412
+// @Failure      422
 // @Failure      500
-// @Router       ///v1/post/{postId} [patch] .
+// @Router       ///v1/post/{userID} [patch] .
 func (ph *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
-	postID := chi.URLParam(r, "postID")
-	id, err := strconv.Atoi(postID)
-	if err != nil {
-		http.Error(w, "Bad Request", http.StatusBadRequest)
-		return
-	}
-
-	err = ph.postService.DeleteUser(id)
-	if err != nil {
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
+	ph.Delete(w, r)
+}
 
-	w.WriteHeader(http.StatusNoContent)
+// BulkUsers godoc
+// @Description Handler to create, update and delete users in one request
+// @Tags Users
+// @Description.markdown bulk users
+// @Accept		 json,application/x-ndjson
+// @Produce      json
+// @Param        atomic query bool false "Apply all operations or none"
+// @Success      207
+// @Failure      400
+// @Failure      413
+// @Failure      415
+// @Failure      500
+// @Router       ///v1/users/bulk [post] .
+func (ph *UserHandler) BulkUsers(w http.ResponseWriter, r *http.Request) {
+	ph.Bulk(w, r)
 }
@@ -2,11 +2,14 @@ package users
 
 import (
 	"blog-api/app/mocks"
+	"blog-api/app/pagination"
+	resource "blog-api/app/v1/resource/service"
 	"blog-api/data"
 	"bytes"
 	"context"
 	"encoding/json"
 	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"net/http"
 	"net/http/httptest"
@@ -20,9 +23,9 @@ func TestGetUsers_Success(t *testing.T) {
 	// Mock expected users
 	mockUsers := []data.User{{ID: 1, Name: "My User", Username: "myuser", Email: "asd@gmail.com", Address: data.Address{}, Phone: "", Website: "", Company: data.Company{}}}
 	// Set mock expectations
-	mockUserService.On("GetUsers", "", 0, 0).Return(&mockUsers, nil)
+	mockUserService.On("GetUsers", mock.Anything, resource.Filter{}, pagination.Pagination{}).Return(&mockUsers, len(mockUsers), nil)
 	// Create handler and request
-	handler := UserHandler{postService: mockUserService}
+	handler := NewUserHandler(mockUserService)
 	req, _ := http.NewRequest("GET", "/users", nil)
 	// Create mock response recorder
 	mockRecorder := httptest.NewRecorder()
@@ -43,11 +46,11 @@ func TestGetUser_Success(t *testing.T) {
 	// Mock expected post
 	mockUser := data.User{ID: 1, Name: "My User", Username: "myuser", Email: "asd@gmail.com", Address: data.Address{}, Phone: "", Website: "", Company: data.Company{}}
 	// Set mock expectations
-	mockUserService.On("GetUser", 1).Return(&mockUser, nil)
+	mockUserService.On("GetUser", mock.Anything, 1).Return(&mockUser, nil)
 	// Create handler and request
-	handler := &UserHandler{postService: mockUserService}
+	handler := NewUserHandler(mockUserService)
 	req, _ := http.NewRequest("GET", "/users/1", nil)
-	ctx.URLParams.Add("postID", "1")
+	ctx.URLParams.Add("userID", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
 	// Mock response recorder
 	mockRecorder := httptest.NewRecorder()
@@ -69,9 +72,9 @@ func TestCreateUser_Success(t *testing.T) {
 	mockCreatedUser := data.User{ID: 1, Name: "My User", Username: "myuser", Email: "asd@gmail.com", Address: data.Address{}, Phone: "", Website: "", Company: data.Company{}}
 
 	// Set mock expectations
-	mockUserService.On("CreateUser", mockUser).Return(&mockCreatedUser, nil)
+	mockUserService.On("CreateUser", mock.Anything, mockUser).Return(&mockCreatedUser, nil)
 	// Create handler and request
-	handler := &UserHandler{postService: mockUserService}
+	handler := NewUserHandler(mockUserService)
 	reqBody, err := json.Marshal(mockUser)
 	require.NoError(t, err)
 	req, _ := http.NewRequest(http.MethodPost, "/users", bytes.NewReader(reqBody))
@@ -96,15 +99,15 @@ func TestUpdateUser_Success(t *testing.T) {
 	mockUpdatedUser := data.User{ID: 1, Name: "My User", Username: "myuser", Email: "asd@gmail.com", Address: data.Address{}, Phone: "", Website: "", Company: data.Company{}}
 
 	// Set mock expectations
-	mockUserService.On("UpdateUser", 1, mockUser).Return(&mockUpdatedUser, nil)
+	mockUserService.On("UpdateUser", mock.Anything, 1, mockUser).Return(&mockUpdatedUser, nil)
 
 	// Create handler and request
-	handler := &UserHandler{postService: mockUserService}
+	handler := NewUserHandler(mockUserService)
 	reqBody, err := json.Marshal(mockUser)
 	require.NoError(t, err)
 	req, _ := http.NewRequest(http.MethodPut, "/users/1", bytes.NewReader(reqBody))
 	ctx := chi.NewRouteContext()
-	ctx.URLParams.Add("postID", "1")
+	ctx.URLParams.Add("userID", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
 
 	// Mock response recorder
@@ -125,20 +128,24 @@ func TestPatchUser_Success(t *testing.T) {
 	mockUserService := mocks.NewIUserService(t)
 	defer mockUserService.AssertExpectations(t)
 
-	// Mock expected post with only updated title
-	mockUser := data.User{ID: 1, Name: "My User", Username: "myuser", Email: "asd@gmail.com", Address: data.Address{}, Phone: "", Website: "", Company: data.Company{}}
+	// Mock current user and the expected one after applying the patch
+	mockCurrentUser := data.User{ID: 1, Name: "Old Name", Username: "myuser", Email: "asd@gmail.com", Address: data.Address{}, Phone: "", Website: "", Company: data.Company{}}
 	mockPatchedUser := data.User{ID: 1, Name: "My User", Username: "myuser", Email: "asd@gmail.com", Address: data.Address{}, Phone: "", Website: "", Company: data.Company{}}
 
-	// Set mock expectations
-	mockUserService.On("UpdateUser", 1, mockUser).Return(&mockPatchedUser, nil)
+	// Set mock expectations: the service applies the patch to the current user
+	mockUserService.On("PatchUser", mock.Anything, 1, mock.Anything).Return(
+		func(ctx context.Context, id int, apply func(data.User) (data.User, error)) (*data.User, error) {
+			patched, err := apply(mockCurrentUser)
+			return &patched, err
+		})
 
-	// Create handler and request with only title in the body
-	handler := &UserHandler{postService: mockUserService}
-	reqBody, err := json.Marshal(mockUser)
-	require.NoError(t, err)
+	// Create handler and request with a merge patch of the name
+	handler := NewUserHandler(mockUserService)
+	reqBody := []byte(`{"name": "My User"}`)
 	req, _ := http.NewRequest(http.MethodPatch, "/users/1", bytes.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
 	ctx := chi.NewRouteContext()
-	ctx.URLParams.Add("postID", "1")
+	ctx.URLParams.Add("userID", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
 
 	// Mock response recorder
@@ -160,13 +167,13 @@ func TestDeleteUser_Success(t *testing.T) {
 	defer mockUserService.AssertExpectations(t)
 
 	// Set mock expectations
-	mockUserService.On("DeleteUser", 1).Return(nil)
+	mockUserService.On("DeleteUser", mock.Anything, 1).Return(nil)
 
 	// Create handler and request
-	handler := &UserHandler{postService: mockUserService}
+	handler := NewUserHandler(mockUserService)
 	req, _ := http.NewRequest(http.MethodDelete, "/users/1", nil)
 	ctx := chi.NewRouteContext()
-	ctx.URLParams.Add("postID", "1")
+	ctx.URLParams.Add("userID", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
 
 	// Mock response recorder
@@ -2,171 +2,78 @@ package users
 
 import (
 	"blog-api/app/clients/restclient"
+	"blog-api/app/config"
+	"blog-api/app/pagination"
+	"blog-api/app/repository"
+	resource "blog-api/app/v1/resource/service"
 	data "blog-api/data"
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"github.com/go-playground/validator/v10"
-	"net/http"
-	"net/url"
-	"os"
-	"strconv"
+	"context"
 )
 
-var baseUrl = os.Getenv("baseURL")
+// Filters son los query params que acepta GetUsers
+var Filters = []string{"id", "username", "email"}
 
 // IUserService define un servicio para obtener users
 type IUserService interface {
-	GetUsers(title string, userID int, id int) (*[]data.User, error)
-	GetUser(id int) (*data.User, error)
-	CreateUser(album data.User) (*data.User, error)
-	UpdateUser(id int, album data.User) (*data.User, error)
-	DeleteUser(id int) error
+	GetUsers(ctx context.Context, filter resource.Filter, page pagination.Pagination) (*[]data.User, int, error)
+	GetUser(ctx context.Context, id int) (*data.User, error)
+	CreateUser(ctx context.Context, user data.User) (*data.User, error)
+	UpdateUser(ctx context.Context, id int, user data.User) (*data.User, error)
+	PatchUser(ctx context.Context, id int, apply func(current data.User) (data.User, error)) (*data.User, error)
+	DeleteUser(ctx context.Context, id int) error
 }
 
-// UserService implementa el servicio utilizando JSONPlaceholder
+// UserService implementa el servicio utilizando JSONPlaceholder, el almacenamiento local o ambos
 type UserService struct {
-	restClient restclient.IRestClient
+	resource resource.IResourceService[data.User]
 }
 
 // GetUsers obtiene users desde JSONPlaceholder
-func (s *UserService) GetUsers(title string, userID int, id int) (*[]data.User, error) {
-	u, err := url.Parse(baseUrl)
-	if err != nil {
-		return nil, err
-	}
-	q := u.Query()
-	if title != "" {
-		q.Set("title", title)
-	}
-	if userID != 0 {
-		q.Set("userId", strconv.Itoa(userID))
-	}
-	if id != 0 {
-		q.Set("id", strconv.Itoa(id))
-	}
-	u.RawQuery = q.Encode()
-	url := u.String()
-	payload := bytes.NewBuffer(nil)
-	method := "GET"
-	resp, err := s.restClient.NewRequest(method, url, payload, nil)
-	var users []data.User
-	err = json.NewDecoder(resp.Body).Decode(&users)
-	if err != nil {
-		return nil, err
-	}
-	return &users, nil
+func (s *UserService) GetUsers(ctx context.Context, filter resource.Filter, page pagination.Pagination) (*[]data.User, int, error) {
+	return s.resource.List(ctx, filter, page)
 }
 
-func (s *UserService) GetUser(id int) (*data.User, error) {
-	url := fmt.Sprintf("%s/%d", baseUrl, id)
-	method := "GET"
-	resp, err := s.restClient.NewRequest(method, url, bytes.NewBuffer(nil), nil)
-	if err != nil {
-		return nil, err
-	}
-	var album data.User
-	err = json.NewDecoder(resp.Body).Decode(&album)
-	if err != nil {
-		return nil, err
-	}
-	return &album, nil
+func (s *UserService) GetUser(ctx context.Context, id int) (*data.User, error) {
+	return s.resource.Get(ctx, id)
 }
 
-func (s *UserService) CreateUser(album data.User) (*data.User, error) {
-	validate := validator.New()
-	if err := validate.Struct(album); err != nil {
-		return nil, fmt.Errorf("User can´t be created. Status Code: %s", err)
-	}
+func (s *UserService) CreateUser(ctx context.Context, user data.User) (*data.User, error) {
+	return s.resource.Create(ctx, user)
+}
 
-	url := baseUrl
-	method := "POST"
-	body, err := json.Marshal(album)
-	if err != nil {
-		return nil, err
-	}
-	payload := bytes.NewBuffer(body)
-	headers := map[string]string{"Content-Type": " application/json"}
-	resp, err := s.restClient.NewRequest(method, url, payload, headers)
-	if err != nil {
-		return nil, fmt.Errorf("User can´t be created. Status Code: %d", resp.StatusCode)
-	}
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("User can´t be created. Status Code: %d", resp.StatusCode)
-	}
-	var createdUser data.User
-	err = json.NewDecoder(resp.Body).Decode(&createdUser)
-	if err != nil {
-		return nil, err
-	}
-	return &createdUser, nil
+func (s *UserService) UpdateUser(ctx context.Context, id int, user data.User) (*data.User, error) {
+	return s.resource.Update(ctx, id, user)
 }
 
-func (s *UserService) UpdateUser(id int, album data.User) (*data.User, error) {
-	url := fmt.Sprintf("%s/%d", baseUrl, id)
-	method := "PUT"
-	body, err := json.Marshal(album)
-	if err != nil {
-		return nil, err
-	}
-	payload := bytes.NewBuffer(body)
-	headers := map[string]string{"Content-Type": " application/json"}
-	resp, err := s.restClient.NewRequest(method, url, payload, headers)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("User can´t be updated. Status Code: %d", resp.StatusCode)
-	}
-	var updatedUser data.User
-	err = json.NewDecoder(resp.Body).Decode(&updatedUser)
-	if err != nil {
-		return nil, err
-	}
-	return &updatedUser, nil
+func (s *UserService) PatchUser(ctx context.Context, id int, apply func(current data.User) (data.User, error)) (*data.User, error) {
+	return s.resource.Patch(ctx, id, apply)
 }
 
-func (s *UserService) PatchUser(id int, album data.User) (*data.User, error) {
-	url := fmt.Sprintf("%s/%d", baseUrl, id)
-	method := "PATCH"
-	body, err := json.Marshal(album)
-	if err != nil {
-		return nil, err
-	}
-	headers := map[string]string{"Content-Type": " application/json"}
-	payload := bytes.NewBuffer(body)
-	resp, err := s.restClient.NewRequest(method, url, payload, headers)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("User can't be updated. Status Code: %d", resp.StatusCode)
-	}
-	var updatedUser data.User
-	err = json.NewDecoder(resp.Body).Decode(&updatedUser)
-	if err != nil {
-		return nil, err
-	}
-	return &updatedUser, nil
+func (s *UserService) DeleteUser(ctx context.Context, id int) error {
+	return s.resource.Delete(ctx, id)
 }
 
-func (s *UserService) DeleteUser(id int) error {
-	url := fmt.Sprintf("%s/%d", baseUrl, id)
-	method := "DELETE"
-	payload := bytes.NewBuffer(nil)
-	resp, err := s.restClient.NewRequest(method, url, payload, nil)
-	if err != nil {
-		return err
+// NewUserService crea una nueva instancia del servicio de users contra la colección upstream dada
+func NewUserService(client restclient.IRestClient, upstream config.Upstream, opts ...resource.Option) IUserService {
+	return &UserService{
+		resource: resource.NewResourceService[data.User](client, "User", upstream.URL(), opts...),
 	}
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("User cannnt be created. Status Code: %d", resp.StatusCode)
+}
+
+// NewUserLocalService crea el servicio de users sobre el almacenamiento local
+func NewUserLocalService(repo repository.UserRepository) IUserService {
+	return &UserService{
+		resource: resource.NewRepositoryService[data.User](repo, "User"),
 	}
-	return nil
 }
 
-// NewUserUserService crea una nueva instancia del servicio de users
-func NewUserService(client restclient.IRestClient) IUserService {
+// NewUserOverlayService crea el servicio de users que lee del upstream y guarda las escrituras localmente
+func NewUserOverlayService(client restclient.IRestClient, upstream config.Upstream, store *repository.OverlayStore, opts ...resource.Option) IUserService {
 	return &UserService{
-		restClient: client,
+		resource: resource.NewOverlayService[data.User](
+			resource.NewResourceService[data.User](client, "User", upstream.URL(), opts...),
+			repository.NewOverlay[data.User](store, "users"),
+			"User",
+		),
 	}
 }
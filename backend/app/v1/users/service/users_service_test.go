@@ -1,9 +1,13 @@
 package users
 
 import (
+	"blog-api/app/config"
 	"blog-api/app/mocks"
+	"blog-api/app/pagination"
+	resource "blog-api/app/v1/resource/service"
 	data "blog-api/data"
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -16,6 +20,10 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+var upstream = config.Upstream{Root: "https://jsonplaceholder.typicode.com", Path: "users"}
+
+var baseUrl = upstream.URL()
+
 type MockReader struct {
 	*bytes.Reader
 }
@@ -27,14 +35,14 @@ func (r *MockReader) Close() error {
 func TestUserService_GetUsers_Success(t *testing.T) {
 	mockResp := &http.Response{
 		StatusCode: http.StatusOK,
-		Body:       io.NopCloser(&MockReader{bytes.NewReader([]byte(`[{"id": 1, "title": "Test Title", "userId": 10}]`))}),
+		Body:       io.NopCloser(&MockReader{bytes.NewReader([]byte(`[{"id": 1, "name": "Test Name", "username": "tester"}]`))}),
 	}
-	//mockResp.Body.Read([]byte(`[{"id": 1, "title": "Test Title", "userId": 10}]`))
+	//mockResp.Body.Read([]byte(`[{"id": 1, "name": "Test Name", "username": "tester"}]`))
 	mockClient := mocks.NewIRestClient(t)
-	mockClient.On("NewRequest", "GET", "https://jsonplaceholder.typicode.com/users?id=1&title=Test+Title&userId=10", mock.Anything, mock.Anything).Return(mockResp, nil)
+	mockClient.On("NewRequest", mock.Anything, "GET", "https://jsonplaceholder.typicode.com/users?id=1&title=Test+Title&userId=10", mock.Anything, mock.Anything).Return(mockResp, nil)
 	//
-	service := &UserService{restClient: mockClient}
-	users, err := service.GetUsers("Test Title", 10, 1)
+	service := NewUserService(mockClient, upstream)
+	users, _, err := service.GetUsers(context.Background(), resource.Filter{"title": "Test Title", "userId": "10", "id": "1"}, pagination.Pagination{})
 	require.NoError(t, err)
 	require.NotNil(t, users)
 	require.Equal(t, 1, len(*users))
@@ -42,30 +50,30 @@ func TestUserService_GetUsers_Success(t *testing.T) {
 
 func TestUserService_GetUsers_ErrorInRequest(t *testing.T) {
 	mockClient := mocks.NewIRestClient(t)
-	mockClient.On("NewRequest", "GET", "https://jsonplaceholder.typicode.com/users?id=1&title=Test+Title&userId=10", mock.Anything, mock.Anything).Return(nil, errors.New("request error"))
+	mockClient.On("NewRequest", mock.Anything, "GET", "https://jsonplaceholder.typicode.com/users?id=1&title=Test+Title&userId=10", mock.Anything, mock.Anything).Return(nil, errors.New("request error"))
 
-	service := &UserService{restClient: mockClient}
-	_, err := service.GetUsers("Test Title", 10, 1)
+	service := NewUserService(mockClient, upstream)
+	_, _, err := service.GetUsers(context.Background(), resource.Filter{"title": "Test Title", "userId": "10", "id": "1"}, pagination.Pagination{})
 	require.Error(t, err)
 }
 
 func TestUserService_GetUser_Success(t *testing.T) {
 	// Mock expected album data
 	mockUser := &data.User{
-		ID:     1,
-		Title:  "Test Title",
-		UserID: 10,
+		ID:       1,
+		Name:     "Test Name",
+		Username: "tester",
 	}
 	// Mock client response
 	mockResp := &http.Response{
 		StatusCode: http.StatusOK,
-		Body:       io.NopCloser(bytes.NewReader([]byte(`{"id": 1, "title": "Test Title", "userId": 10}`))),
+		Body:       io.NopCloser(bytes.NewReader([]byte(`{"id": 1, "name": "Test Name", "username": "tester"}`))),
 	}
 	mockClient := mocks.NewIRestClient(t)
-	mockClient.On("NewRequest", "GET", fmt.Sprintf("%s/%d", baseUrl, mockUser.ID), mock.Anything, mock.Anything).Return(mockResp, nil)
+	mockClient.On("NewRequest", mock.Anything, "GET", fmt.Sprintf("%s/%d", baseUrl, mockUser.ID), mock.Anything, mock.Anything).Return(mockResp, nil)
 	// Create service and call GetUser
-	service := &UserService{restClient: mockClient}
-	album, err := service.GetUser(mockUser.ID)
+	service := NewUserService(mockClient, upstream)
+	album, err := service.GetUser(context.Background(), mockUser.ID)
 	require.NoError(t, err)
 	require.NotNil(t, album)
 	require.Equal(t, *mockUser, *album)
@@ -73,114 +81,114 @@ func TestUserService_GetUser_Success(t *testing.T) {
 
 func TestCreateUser(t *testing.T) {
 	mockClient := mocks.NewIRestClient(t)
-	service := &UserService{restClient: mockClient}
+	service := NewUserService(mockClient, upstream)
 	album := data.User{
-		ID:     1,
-		Title:  "Test Title",
-		UserID: 10,
+		ID:       1,
+		Name:     "Test Name",
+		Username: "tester",
 	}
 	t.Run("success", func(t *testing.T) {
-		mockClient.On("NewRequest", "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: http.StatusCreated,
-			Body:       io.NopCloser(bytes.NewReader([]byte(`{"id": 1, "title": "Test Title", "userId": 10}`))),
-		}, nil)
+			Body:       io.NopCloser(bytes.NewReader([]byte(`{"id": 1, "name": "Test Name", "username": "tester"}`))),
+		}, nil).Once()
 
-		createdUser, err := service.CreateUser(album)
+		createdUser, err := service.CreateUser(context.Background(), album)
 		assert.NoError(t, err)
 		assert.Equal(t, album, *createdUser)
 	})
 	t.Run("error in validation", func(t *testing.T) {
 		invalidUser := data.User{
-			ID:     1,
-			Title:  "", // Title is required
-			UserID: 10,
+			ID:       1,
+			Name:     "", // Name is required
+			Username: "tester",
 		}
-		_, err := service.CreateUser(invalidUser)
+		_, err := service.CreateUser(context.Background(), invalidUser)
 		assert.Error(t, err)
 	})
 	t.Run("error in request", func(t *testing.T) {
-		mockClient.On("NewRequest", "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(nil, errors.New("request error"))
+		mockClient.On("NewRequest", mock.Anything, "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(nil, errors.New("request error")).Once()
 
-		_, err := service.CreateUser(album)
+		_, err := service.CreateUser(context.Background(), album)
 		assert.Error(t, err)
 	})
 	t.Run("error in response", func(t *testing.T) {
-		mockClient.On("NewRequest", "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: 500,
 			Body:       ioutil.NopCloser(bytes.NewReader([]byte(``))),
-		}, nil)
+		}, nil).Once()
 
-		_, err := service.CreateUser(album)
+		_, err := service.CreateUser(context.Background(), album)
 		assert.Error(t, err)
 	})
 	t.Run("error in decoding", func(t *testing.T) {
-		mockClient.On("NewRequest", "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "POST", baseUrl, mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: http.StatusCreated,
 			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`not a valid json`))),
-		}, nil)
-		_, err := service.CreateUser(album)
+		}, nil).Once()
+		_, err := service.CreateUser(context.Background(), album)
 		assert.Error(t, err)
 	})
 }
 
 func TestUpdateUser(t *testing.T) {
 	mockClient := mocks.NewIRestClient(t)
-	service := &UserService{restClient: mockClient}
+	service := NewUserService(mockClient, upstream)
 
 	album := data.User{
-		ID:     1,
-		Title:  "Updated Title",
-		UserID: 10,
+		ID:       1,
+		Name:     "Updated Name",
+		Username: "tester",
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mockClient.On("NewRequest", "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: http.StatusOK,
-			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"id": 1, "title": "Updated Title", "userId": 10}`))),
-		}, nil)
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"id": 1, "name": "Updated Name", "username": "tester"}`))),
+		}, nil).Once()
 
-		updatedUser, err := service.UpdateUser(album.ID, album)
+		updatedUser, err := service.UpdateUser(context.Background(), album.ID, album)
 		assert.NoError(t, err)
 		assert.Equal(t, album, *updatedUser)
 	})
 
 	t.Run("error in request", func(t *testing.T) {
-		mockClient.On("NewRequest", "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(nil, errors.New("request error"))
+		mockClient.On("NewRequest", mock.Anything, "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(nil, errors.New("request error")).Once()
 
-		_, err := service.UpdateUser(album.ID, album)
+		_, err := service.UpdateUser(context.Background(), album.ID, album)
 		assert.Error(t, err)
 	})
 
 	t.Run("error in response", func(t *testing.T) {
-		mockClient.On("NewRequest", "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: 500,
 			Body:       ioutil.NopCloser(bytes.NewReader([]byte(``))),
-		}, nil)
+		}, nil).Once()
 
-		_, err := service.UpdateUser(album.ID, album)
+		_, err := service.UpdateUser(context.Background(), album.ID, album)
 		assert.Error(t, err)
 	})
 
 	t.Run("error in decoding", func(t *testing.T) {
-		mockClient.On("NewRequest", "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": " application/json"}).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "PUT", fmt.Sprintf("%s/%d", baseUrl, album.ID), mock.Anything, map[string]string{"Content-Type": "application/json"}).Return(&http.Response{
 			StatusCode: http.StatusOK,
 			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`not a valid json`))),
-		}, nil)
+		}, nil).Once()
 
-		_, err := service.UpdateUser(album.ID, album)
+		_, err := service.UpdateUser(context.Background(), album.ID, album)
 		assert.Error(t, err)
 	})
 }
 
 func TestDeleteUser(t *testing.T) {
 	mockClient := mocks.NewIRestClient(t)
-	service := &UserService{restClient: mockClient}
+	service := NewUserService(mockClient, upstream)
 	albumID := 1
 	t.Run("success", func(t *testing.T) {
-		mockClient.On("NewRequest", "DELETE", fmt.Sprintf("%s/%d", baseUrl, albumID), mock.Anything, mock.Anything).Return(&http.Response{
+		mockClient.On("NewRequest", mock.Anything, "DELETE", fmt.Sprintf("%s/%d", baseUrl, albumID), mock.Anything, mock.Anything).Return(&http.Response{
 			StatusCode: http.StatusOK,
-		}, nil)
-		err := service.DeleteUser(albumID)
+		}, nil).Once()
+		err := service.DeleteUser(context.Background(), albumID)
 		assert.NoError(t, err)
 	})
 }
@@ -0,0 +1,39 @@
+package validation
+
+import (
+	apperrors "blog-api/app/errors"
+	"encoding/json"
+	"errors"
+	"reflect"
+)
+
+// TypeError convierte el error de json.Unmarshal por un valor de otro tipo en un error
+// de tipo kind con el detalle del campo, p. ej. age must be a number. Devuelve nil si
+// err no es de ese tipo o no indica el campo.
+func TypeError(kind *apperrors.Error, err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) || typeErr.Field == "" {
+		return nil
+	}
+	expected := jsonType(typeErr.Type.Kind())
+	field := apperrors.NewFieldError(typeErr.Field, "type", expected, "%s must be a %s", typeErr.Field, expected)
+	return apperrors.WithFields(apperrors.Newf(kind, "%s must be a %s", typeErr.Field, expected), field)
+}
+
+// jsonType devuelve el nombre en JSON de un tipo de Go
+func jsonType(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	default:
+		return kind.String()
+	}
+}
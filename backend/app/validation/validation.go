@@ -0,0 +1,193 @@
+package validation
+
+import (
+	apperrors "blog-api/app/errors"
+	"blog-api/app/i18n"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	entranslations "github.com/go-playground/validator/v10/translations/en"
+	estranslations "github.com/go-playground/validator/v10/translations/es"
+)
+
+// Error indica que un elemento no cumple las reglas de validación de sus campos.
+// Fields tiene los mensajes en inglés; FieldErrors los traduce al idioma de la respuesta.
+type Error struct {
+	Fields []apperrors.FieldError
+	errs   []validator.FieldError
+}
+
+func (e *Error) Error() string {
+	return "validation failed: " + join(e.Fields)
+}
+
+// Localize escribe el error en lang
+func (e *Error) Localize(lang string) string {
+	return i18n.Sprintf(lang, "validation failed: %s", join(e.FieldErrors(lang)))
+}
+
+// Is hace que los errores de validación sean del tipo apperrors.ErrValidation
+func (e *Error) Is(target error) bool {
+	return target == apperrors.ErrValidation
+}
+
+// FieldErrors devuelve los campos inválidos para la respuesta, con los mensajes en lang
+func (e *Error) FieldErrors(lang string) []apperrors.FieldError {
+	trans := translator(lang)
+	fields := make([]apperrors.FieldError, len(e.errs))
+	for i, fe := range e.errs {
+		fields[i] = fieldError(fe, trans)
+	}
+	return fields
+}
+
+func join(fields []apperrors.FieldError) string {
+	messages := make([]string, len(fields))
+	for i, field := range fields {
+		messages[i] = field.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// messages son los textos de las reglas que usan las entidades; {0} es el campo y {1}
+// el parámetro de la regla. Las demás reglas usan las traducciones del validador.
+var messages = map[string]map[string]string{
+	i18n.English: {
+		"required":   "{0} is required",
+		"email":      "{0} must be a valid email address",
+		"uri":        "{0} must be a valid URL",
+		"url":        "{0} must be a valid URL",
+		"min":        "{0} must be {1} or greater",
+		"min-string": "{0} must be at least {1} characters long",
+		"max":        "{0} must be {1} or less",
+		"max-string": "{0} must be at most {1} characters long",
+		"default":    "{0} does not satisfy {1}",
+	},
+	i18n.Spanish: {
+		"required":   "{0} es obligatorio",
+		"email":      "{0} debe ser un email válido",
+		"uri":        "{0} debe ser una URL válida",
+		"url":        "{0} debe ser una URL válida",
+		"min":        "{0} debe ser {1} o mayor",
+		"min-string": "{0} debe tener al menos {1} caracteres",
+		"max":        "{0} debe ser {1} o menor",
+		"max-string": "{0} debe tener como máximo {1} caracteres",
+		"default":    "{0} no cumple {1}",
+	},
+}
+
+// rules son las reglas con texto propio
+var rules = []string{"required", "email", "uri", "url", "min", "max"}
+
+// validate es compartido: guarda la información de cada tipo la primera vez que lo valida
+var validate, translators = newValidator()
+
+func newValidator() (*validator.Validate, *ut.UniversalTranslator) {
+	v := validator.New()
+	// Los campos se informan con su nombre en el JSON, p. ej. userId en lugar de UserID
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	translators := ut.New(en.New(), en.New(), es.New())
+	defaults := map[string]func(*validator.Validate, ut.Translator) error{
+		i18n.English: entranslations.RegisterDefaultTranslations,
+		i18n.Spanish: estranslations.RegisterDefaultTranslations,
+	}
+	for lang, register := range defaults {
+		trans, _ := translators.GetTranslator(lang)
+		if err := register(v, trans); err != nil {
+			panic(err)
+		}
+		for key, text := range messages[lang] {
+			if err := trans.Add("blog."+key, text, true); err != nil {
+				panic(err)
+			}
+		}
+		for _, rule := range rules {
+			if err := v.RegisterTranslation(rule, trans, noop, translate); err != nil {
+				panic(err)
+			}
+		}
+	}
+	return v, translators
+}
+
+// noop no registra textos: los de las reglas propias se agregan en newValidator
+func noop(ut.Translator) error {
+	return nil
+}
+
+// translate escribe el mensaje de una regla propia con el path del campo
+func translate(trans ut.Translator, fe validator.FieldError) string {
+	key := fe.Tag()
+	if (key == "min" || key == "max") && fe.Kind() == reflect.String {
+		key += "-string"
+	}
+	message, err := trans.T("blog."+key, path(fe), fe.Param())
+	if err != nil {
+		return fe.Error()
+	}
+	return message
+}
+
+// translator devuelve el traductor de lang o del primer idioma de su cadena que exista
+func translator(lang string) ut.Translator {
+	for _, candidate := range i18n.Fallbacks(lang) {
+		if trans, ok := translators.GetTranslator(candidate); ok {
+			return trans
+		}
+	}
+	return translators.GetFallback()
+}
+
+// Struct valida todos los campos de item según sus tags validate. Devuelve un *Error
+// con cada campo inválido, o nil.
+func Struct(item any) error {
+	err := validate.Struct(item)
+	if err == nil {
+		return nil
+	}
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return err
+	}
+	invalid := &Error{errs: errs}
+	invalid.Fields = invalid.FieldErrors(i18n.Default)
+	return invalid
+}
+
+// path es el campo en el JSON sin el tipo, p. ej. address.city
+func path(fe validator.FieldError) string {
+	field := fe.Namespace()
+	if _, rest, ok := strings.Cut(field, "."); ok {
+		field = rest
+	}
+	return field
+}
+
+// fieldError traduce el error del validador con trans. Las reglas sin traducción se
+// informan como que el campo no la cumple.
+func fieldError(fe validator.FieldError, trans ut.Translator) apperrors.FieldError {
+	message := fe.Translate(trans)
+	if message == fe.Error() {
+		message, _ = trans.T("blog.default", path(fe), fe.Tag())
+	}
+	return apperrors.FieldError{
+		Field:   path(fe),
+		Rule:    fe.Tag(),
+		Param:   fe.Param(),
+		Message: message,
+	}
+}
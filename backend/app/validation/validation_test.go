@@ -0,0 +1,63 @@
+package validation
+
+import (
+	apperrors "blog-api/app/errors"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type address struct {
+	City string `json:"city" validate:"required"`
+}
+
+type person struct {
+	ID      int     `json:"id"`
+	Name    string  `json:"name" validate:"required,min=2,max=10"`
+	Email   string  `json:"email" validate:"omitempty,email"`
+	Age     int     `json:"age" validate:"max=150"`
+	Site    string  `json:"site,omitempty" validate:"omitempty,uri"`
+	Address address `json:"address"`
+}
+
+func fields(t *testing.T, err error) []apperrors.FieldError {
+	var validationErr *Error
+	require.True(t, errors.As(err, &validationErr), err)
+	return validationErr.Fields
+}
+
+func TestStruct(t *testing.T) {
+	require.NoError(t, Struct(person{Name: "Ana", Address: address{City: "Lima"}}))
+
+	err := Struct(person{Name: "A", Email: "ana", Age: 200, Site: "not a url"})
+	require.Equal(t, []apperrors.FieldError{
+		{Field: "name", Rule: "min", Param: "2", Message: "name must be at least 2 characters long"},
+		{Field: "email", Rule: "email", Message: "email must be a valid email address"},
+		{Field: "age", Rule: "max", Param: "150", Message: "age must be 150 or less"},
+		{Field: "site", Rule: "uri", Message: "site must be a valid URL"},
+		{Field: "address.city", Rule: "required", Message: "address.city is required"},
+	}, fields(t, err))
+	require.EqualError(t, Struct(person{Address: address{City: "Lima"}}), "validation failed: name is required")
+}
+
+func TestStruct_NotAStruct(t *testing.T) {
+	err := Struct("ana")
+	require.Error(t, err)
+	var validationErr *Error
+	require.False(t, errors.As(err, &validationErr))
+}
+
+func TestError_Localize(t *testing.T) {
+	err := Struct(person{Name: "Anastasia Maria", Age: 200})
+	var validationErr *Error
+	require.True(t, errors.As(err, &validationErr))
+	require.Equal(t, []apperrors.FieldError{
+		{Field: "name", Rule: "max", Param: "10", Message: "name debe tener como máximo 10 caracteres"},
+		{Field: "age", Rule: "max", Param: "150", Message: "age debe ser 150 o menor"},
+		{Field: "address.city", Rule: "required", Message: "address.city es obligatorio"},
+	}, validationErr.FieldErrors("es-AR"))
+	require.Equal(t, "validación fallida: name debe tener como máximo 10 caracteres; age debe ser 150 o menor; address.city es obligatorio", validationErr.Localize("es"))
+	// Un idioma sin traductor usa el inglés
+	require.Equal(t, validationErr.Fields, validationErr.FieldErrors("fr"))
+}
@@ -13,7 +13,7 @@ func (r *Album) Marshal() ([]byte, error) {
 }
 
 type Album struct {
-	UserID int    `json:"userId"`
+	UserID int    `json:"userId" validate:"required,min=1"`
 	ID     int    `json:"id"`
-	Title  string `json:"title"`
+	Title  string `json:"title" validate:"required,max=200"`
 }
@@ -13,8 +13,19 @@ func (r *Comment) Marshal() ([]byte, error) {
 }
 
 type Comment struct {
-	UserID int64  `json:"userId"`
-	ID     int64  `json:"id"`
-	Title  string `json:"title"`
-	Body   string `json:"body"`
+	PostID int    `json:"postId" validate:"required,min=1"`
+	ID     int    `json:"id"`
+	Name   string `json:"name" validate:"required,max=200"`
+	Email  string `json:"email" validate:"required,email,max=254"`
+	Body   string `json:"body" validate:"max=5000"`
+	// ParentID es el comment al que responde; 0 si no es una respuesta
+	ParentID int `json:"parentId,omitempty" validate:"omitempty,min=1"`
+}
+
+// CommentThread es un comment con sus respuestas
+type CommentThread struct {
+	Comment
+	// ReplyCount es la cantidad de respuestas directas, aunque no se incluyan por la profundidad
+	ReplyCount int              `json:"replyCount"`
+	Replies    []*CommentThread `json:"replies,omitempty"`
 }
@@ -0,0 +1,21 @@
+package data
+
+import "encoding/json"
+
+func UnmarshalPhoto(data []byte) (Photo, error) {
+	var r Photo
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *Photo) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type Photo struct {
+	AlbumID      int    `json:"albumId" validate:"required,min=1"`
+	ID           int    `json:"id"`
+	Title        string `json:"title" validate:"required,max=200"`
+	URL          string `json:"url" validate:"omitempty,uri"`
+	ThumbnailURL string `json:"thumbnailUrl" validate:"omitempty,uri"`
+}
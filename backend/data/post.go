@@ -13,8 +13,8 @@ func (r *Post) Marshal() ([]byte, error) {
 }
 
 type Post struct {
-	UserID int    `json:"userId"`
+	UserID int    `json:"userId" validate:"required,min=1"`
 	ID     int    `json:"id"`
-	Title  string `json:"title"`
-	Body   string `json:"body"`
+	Title  string `json:"title" validate:"required,max=200"`
+	Body   string `json:"body" validate:"max=5000"`
 }
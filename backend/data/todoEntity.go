@@ -13,8 +13,8 @@ func (r *Todo) Marshal() ([]byte, error) {
 }
 
 type Todo struct {
-	UserID    int64  `json:"userId"`
-	ID        int64  `json:"id"`
-	Title     string `json:"title"`
+	UserID    int    `json:"userId" validate:"required,min=1"`
+	ID        int    `json:"id"`
+	Title     string `json:"title" validate:"required,max=200"`
 	Completed bool   `json:"completed"`
 }
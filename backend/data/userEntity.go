@@ -14,9 +14,9 @@ func (r *User) Marshal() ([]byte, error) {
 
 type User struct {
 	ID       int     `json:"id"`
-	Name     string  `json:"name"`
-	Username string  `json:"username"`
-	Email    string  `json:"email"`
+	Name     string  `json:"name" validate:"required,max=100"`
+	Username string  `json:"username" validate:"required,max=50"`
+	Email    string  `json:"email" validate:"omitempty,email"`
 	Address  Address `json:"address"`
 	Phone    string  `json:"phone"`
 	Website  string  `json:"website"`
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"blog-api/app/clients/restclient"
+	"blog-api/app/config"
+	"blog-api/app/fixtures"
+	"blog-api/app/repository"
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+const fixturesUsage = `usage:
+  backend seed [-out file] [-format json|ndjson]
+      load all resources from the configured upstream into the local database,
+      or write them to file
+  backend export [-format json|ndjson] [file]
+      write a snapshot of the local database, overlay changes included, to file
+      (stdout by default)
+  backend import [-format json|ndjson] [file]
+      replace the local database and the overlay changes with the snapshot in file
+      (stdin by default)
+
+the format defaults to ndjson for .ndjson and .jsonl files and to json otherwise`
+
+// runFixtures ejecuta los subcomandos seed, export e import y devuelve el código de salida del proceso
+func runFixtures(cfg *config.Config, command string, args []string) int {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprintln(os.Stderr, fixturesUsage) }
+	format := flags.String("format", "", "json or ndjson")
+	out := ""
+	if command == "seed" {
+		flags.StringVar(&out, "out", "", "write the fixtures to this file instead of the database")
+	}
+	if err := flags.Parse(args); err != nil || flags.NArg() > 1 || (command == "seed" && flags.NArg() > 0) {
+		flags.Usage()
+		return 2
+	}
+	path := out
+	if command != "seed" {
+		path = flags.Arg(0)
+	}
+	bundleFormat, err := fixtures.FormatFor(*format, path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	ctx := context.Background()
+	switch command {
+	case "seed":
+		snapshot, err := fixtures.FromUpstream(ctx, restclient.NewRestClient(clientOptions(cfg)), cfg)
+		if err != nil {
+			return exitCode(err)
+		}
+		if out != "" {
+			return exitCode(writeFixtures(out, snapshot, bundleFormat))
+		}
+		return exitCode(restore(ctx, cfg.Database, snapshot))
+	case "export":
+		db, dialect, err := openSchema(cfg.Database)
+		if err != nil {
+			return exitCode(err)
+		}
+		defer db.Close()
+		snapshot, err := repository.Export(ctx, db, dialect)
+		if err != nil {
+			return exitCode(err)
+		}
+		return exitCode(writeFixtures(path, snapshot, bundleFormat))
+	default:
+		var in io.Reader = os.Stdin
+		if path != "" {
+			file, err := os.Open(path)
+			if err != nil {
+				return exitCode(err)
+			}
+			defer file.Close()
+			in = file
+		}
+		snapshot, err := fixtures.Read(in, bundleFormat)
+		if err != nil {
+			return exitCode(err)
+		}
+		return exitCode(restore(ctx, cfg.Database, snapshot))
+	}
+}
+
+// restore reemplaza el contenido de la base de datos por snapshot
+func restore(ctx context.Context, database config.Database, snapshot *repository.Snapshot) error {
+	db, dialect, err := openSchema(database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if err := repository.Import(ctx, db, dialect, snapshot); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "imported %d users, %d posts, %d albums, %d photos, %d comments, %d todos",
+		len(snapshot.Users), len(snapshot.Posts), len(snapshot.Albums), len(snapshot.Photos), len(snapshot.Comments), len(snapshot.Todos))
+	if snapshot.Overlay != nil {
+		fmt.Fprintf(os.Stderr, ", %d overlay changes", len(snapshot.Overlay))
+	}
+	fmt.Fprintln(os.Stderr)
+	return nil
+}
+
+// writeFixtures escribe snapshot en path, o en stdout si path está vacío
+func writeFixtures(path string, snapshot *repository.Snapshot, format fixtures.Format) error {
+	if path == "" {
+		return fixtures.Write(os.Stdout, snapshot, format)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := fixtures.Write(file, snapshot, format); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// openSchema abre la base de datos y falla si tiene migraciones sin aplicar
+func openSchema(database config.Database) (*sql.DB, repository.Dialect, error) {
+	db, dialect, err := openDatabase(database)
+	if err != nil {
+		return nil, dialect, fmt.Errorf("database unavailable: %w", err)
+	}
+	if err := checkSchema(db, dialect); err != nil {
+		db.Close()
+		return nil, dialect, err
+	}
+	return db, dialect, nil
+}
@@ -3,20 +3,40 @@
 package main
 
 import (
+	"blog-api/app/blob"
+	"blog-api/app/cache"
 	"blog-api/app/clients/restclient"
+	"blog-api/app/config"
+	apperrors "blog-api/app/errors"
+	"blog-api/app/expand"
+	"blog-api/app/health"
+	"blog-api/app/i18n"
+	"blog-api/app/idempotency"
+	"blog-api/app/patch"
+	"blog-api/app/repository"
 	ah "blog-api/app/v1/albums/handler"
 	as "blog-api/app/v1/albums/service"
+	bh "blog-api/app/v1/batch/handler"
 	ch "blog-api/app/v1/comments/handler"
 	cs "blog-api/app/v1/comments/service"
+	oh "blog-api/app/v1/overlay/handler"
+	phh "blog-api/app/v1/photos/handler"
+	phs "blog-api/app/v1/photos/service"
 	ph "blog-api/app/v1/posts/handler"
 	ps "blog-api/app/v1/posts/service"
+	rh "blog-api/app/v1/resource/handler"
+	rs "blog-api/app/v1/resource/service"
 	th "blog-api/app/v1/todos/handler"
 	ts "blog-api/app/v1/todos/service"
 	uh "blog-api/app/v1/users/handler"
 	us "blog-api/app/v1/users/service"
+	"blog-api/data"
 	"context"
+	"database/sql"
+	"expvar"
 	"log/slog"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -31,17 +51,88 @@ import (
 // @BasePath /
 func main() {
 	r := chi.NewRouter()
-	restClient := restclient.NewRestClient()
-	postService := ps.NewPostService(restClient)
-	postHandler := ph.NewPostHandler(postService)
-	albumsService := as.NewAlbumService(restClient)
-	albumHandler := ah.NewAlbumHandler(albumsService)
-	commentsService := cs.NewCommentService(restClient)
-	commentHandler := ch.NewCommentHandler(commentsService)
-	todoService := ts.NewTodoService(restClient)
-	todoHandler := th.NewTodoHandler(todoService)
-	userService := us.NewUserService(restClient)
-	userHandler := uh.NewUserHandler(userService)
+	cfg, err := config.Load()
+	if err != nil {
+		slog.Error("invalid configuration", "error", err)
+		os.Exit(1)
+	}
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			os.Exit(runMigrate(cfg.Database, os.Args[2:]))
+		case "seed", "export", "import":
+			os.Exit(runFixtures(cfg, os.Args[1], os.Args[2:]))
+		}
+	}
+	restClient := restclient.NewRestClient(clientOptions(cfg))
+	var svc services
+	var overlayStore *repository.OverlayStore
+	var atomic rh.Atomic
+	if cfg.Mode == config.ModeProxy {
+		svc = proxyServices(restClient, cfg)
+	} else {
+		db, dialect, err := openDatabase(cfg.Database)
+		if err != nil {
+			slog.Error("database unavailable", "driver", cfg.Database.Driver, "error", err)
+			os.Exit(1)
+		}
+		defer db.Close()
+		if err := checkSchema(db, dialect); err != nil {
+			slog.Error("refusing to start", "error", err)
+			os.Exit(1)
+		}
+		if cfg.Mode == config.ModeLocal {
+			svc = localServices(db, dialect)
+		} else {
+			overlayStore = repository.NewOverlayStore(db, dialect)
+			svc = overlayServices(restClient, cfg, overlayStore)
+		}
+		// Los bulk atómicos se aplican en una transacción de la base de datos local
+		atomic = func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return repository.Transaction(ctx, db, fn)
+		}
+	}
+	store, err := blob.NewDisk(cfg.Uploads.Dir)
+	if err != nil {
+		slog.Error("upload directory unavailable", "error", err)
+		os.Exit(1)
+	}
+	related := newRelations(svc)
+	bulk := rh.BulkOptions{MaxItems: cfg.Bulk.MaxItems, Concurrency: cfg.Bulk.Concurrency, Atomic: atomic}
+	postHandler := ph.NewPostHandler(svc.posts)
+	postHandler.WithExpander(related.posts)
+	postHandler.WithBulk(bulk)
+	albumHandler := ah.NewAlbumHandler(svc.albums)
+	albumHandler.WithExpander(related.albums)
+	albumHandler.WithBulk(bulk)
+	photoHandler := phh.NewPhotoHandler(svc.photos)
+	photoHandler.WithExpander(related.photos)
+	photoHandler.WithBulk(bulk)
+	commentHandler := ch.NewCommentHandler(svc.comments)
+	commentHandler.WithExpander(related.comments)
+	commentHandler.WithBulk(bulk)
+	todoHandler := th.NewTodoHandler(svc.todos)
+	todoHandler.WithExpander(related.todos)
+	todoHandler.WithBulk(bulk)
+	userHandler := uh.NewUserHandler(svc.users)
+	userHandler.WithBulk(bulk)
+	userExists := rh.Exists(svc.users.GetUser)
+	userPostsHandler := ph.NewUserPostsHandler(svc.posts, userExists)
+	userPostsHandler.WithExpander(related.posts)
+	userAlbumsHandler := ah.NewUserAlbumsHandler(svc.albums, userExists)
+	userAlbumsHandler.WithExpander(related.albums)
+	userTodosHandler := th.NewUserTodosHandler(svc.todos, userExists)
+	userTodosHandler.WithExpander(related.todos)
+	// En modo proxy el upstream no guarda las photos creadas: las imágenes quedarían huérfanas
+	var uploader *phs.Uploader
+	if cfg.Mode != config.ModeProxy {
+		uploader = phs.NewUploader(svc.photos, store, "/files", cfg.Uploads.ThumbnailSize)
+	}
+	albumPhotosHandler := phh.NewAlbumPhotosHandler(svc.photos, rh.Exists(svc.albums.GetAlbum), uploader, cfg.Uploads.MaxSize)
+	albumPhotosHandler.WithExpander(related.photos)
+	postCommentsHandler := ch.NewPostCommentsHandler(svc.comments, rh.Exists(svc.posts.GetPost))
+	postCommentsHandler.WithExpander(related.comments)
+	healthHandler := health.NewHealthHandler(restClient)
 	// Logger
 	logger := httplog.NewLogger("blog-api", httplog.Options{
 		LogLevel: slog.LevelDebug,
@@ -67,11 +158,12 @@ func main() {
 	r.Use(middleware.Heartbeat("/ping"))
 
 	r.Use(middleware.RequestID)
+	// Idioma de los mensajes según Accept-Language
+	r.Use(i18n.Middleware)
 	r.Use(middleware.RealIP)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.NoCache)
-	r.Use(middleware.AllowContentType("application/json"))
+	r.Use(middleware.AllowContentType("application/json", "multipart/form-data", patch.MergePatch, patch.JSONPatch, rh.NDJSON))
 	r.Use(middleware.CleanPath)
 	r.Use(middleware.Recoverer)
 	r.Use(cors.Handler(cors.Options{
@@ -79,103 +171,205 @@ func main() {
 		AllowedOrigins: []string{"https://*", "http://*"},
 		// AllowOriginFunc:  func(r *http.Request, origin string) bool { return true },
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "If-Match", "If-None-Match", idempotency.Header},
+		ExposedHeaders:   []string{"Link", "X-Total-Count", "X-Next-Cursor", "Retry-After", "X-Cache", "Accept-Patch", "ETag", idempotency.ReplayedHeader},
 		AllowCredentials: false,
 		MaxAge:           300, // Maximum value not ignored by any of major browsers
 	}))
 
+	// Los errores se responden como application/problem+json; se registran antes de
+	// montar las rutas para que los subrouters hereden NotFound y MethodNotAllowed
+	r.NotFound(apperrors.NotFound)
+	r.MethodNotAllowed(apperrors.MethodNotAllowed)
+	r.Get("/problems", apperrors.CatalogHandler)
+	r.Get("/problems/{code}", apperrors.CatalogHandler)
+
+	// Métricas y estado de los circuitos del cliente upstream
+	r.With(middleware.NoCache).Handle("/debug/vars", expvar.Handler())
+	r.With(middleware.NoCache).Get("/health", healthHandler.GetHealth)
+	// Imágenes subidas y sus miniaturas
+	r.Get("/files/*", blob.Handler(store))
+
+	// Las subsolicitudes de /v1/batch se atienden con el router completo, con sus middlewares
+	batchHandler := bh.NewBatchHandler(r, bh.Options{
+		MaxRequests: cfg.Batch.MaxRequests,
+		MaxBody:     cfg.Batch.MaxSize,
+		Concurrency: cfg.Batch.Concurrency,
+	})
+
 	// API version 1.
 	r.Route("/v1", func(r chi.Router) {
 		r.Use(apiVersionCtx("v1"))
-		r.Mount("/albums", albumRouter(albumHandler))
-		r.Mount("/comments", commentRouter(commentHandler))
-		r.Mount("/posts", postRouter(postHandler))
-		r.Mount("/todos", todoRouter(todoHandler))
-		r.Mount("/users", userRouter(userHandler))
+		// Los POST con Idempotency-Key se atienden una sola vez; las repeticiones reciben
+		// la respuesta guardada. El cuerpo puede ser una foto, así que se admite su tamaño.
+		r.Use(idempotency.Middleware(idempotency.NewMemory(), idempotency.Options{
+			TTL:     cfg.Idempotency.TTL,
+			Wait:    cfg.Idempotency.Wait,
+			MaxBody: cfg.Uploads.MaxSize + 1<<20,
+		}))
+		rh.MountResource(r, "/albums", albumHandler.Routes(), albumPhotosHandler.Mount())
+		rh.MountResource(r, "/comments", commentHandler.Routes(), commentHandler.Replies())
+		rh.MountResource(r, "/photos", photoHandler.Routes())
+		rh.MountResource(r, "/posts", postHandler.Routes(), postCommentsHandler.Mount())
+		rh.MountResource(r, "/todos", todoHandler.Routes())
+		rh.MountResource(r, "/users", userHandler.Routes(), userPostsHandler.Mount(), userAlbumsHandler.Mount(), userTodosHandler.Mount())
+		r.With(rh.CacheControl(rh.NoStore)).Post("/batch", batchHandler.Batch)
+		if overlayStore != nil {
+			r.Delete("/overlay", oh.NewOverlayHandler(overlayStore, config.Resources).ResetOverlay)
+		}
 	})
 	http.ListenAndServe(":8000", r)
 }
 
-func apiVersionCtx(version string) func(next http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			r = r.WithContext(context.WithValue(r.Context(), "api.version", version))
-			next.ServeHTTP(w, r)
-		})
+// services son los servicios de cada recurso
+type services struct {
+	posts    ps.IPostService
+	albums   as.IAlbumService
+	comments cs.ICommentService
+	photos   phs.IPhotoService
+	todos    ts.ITodoService
+	users    us.IUserService
+}
+
+// relations son las relaciones que cada recurso puede incrustar con ?expand=
+type relations struct {
+	posts    *expand.Resolver[data.Post]
+	albums   *expand.Resolver[data.Album]
+	comments *expand.Resolver[data.Comment]
+	photos   *expand.Resolver[data.Photo]
+	todos    *expand.Resolver[data.Todo]
+}
+
+// newRelations arma las relaciones sobre los servicios, así se cargan con su caché y coalescencia
+func newRelations(svc services) relations {
+	posts := expand.NewResolver[data.Post]()
+	comments := expand.NewResolver[data.Comment]()
+	albums := expand.NewResolver[data.Album]()
+	photos := expand.NewResolver[data.Photo]()
+	posts.
+		Add("user", expand.BelongsTo(func(p data.Post) int { return p.UserID }, svc.users.GetUser, nil)).
+		Add("comments", expand.HasMany(func(p data.Post) int { return p.ID }, "postId", svc.comments.GetComments, comments))
+	comments.
+		Add("post", expand.BelongsTo(func(c data.Comment) int { return c.PostID }, svc.posts.GetPost, posts)).
+		Add("parent", expand.BelongsTo(func(c data.Comment) int { return c.ParentID }, svc.comments.GetComment, comments)).
+		Add("replies", expand.HasMany(func(c data.Comment) int { return c.ID }, "parentId", svc.comments.GetComments, comments))
+	albums.
+		Add("user", expand.BelongsTo(func(a data.Album) int { return a.UserID }, svc.users.GetUser, nil)).
+		Add("photos", expand.HasMany(func(a data.Album) int { return a.ID }, "albumId", svc.photos.GetPhotos, nil))
+	photos.Add("album", expand.BelongsTo(func(p data.Photo) int { return p.AlbumID }, svc.albums.GetAlbum, albums))
+	return relations{
+		posts:    posts,
+		albums:   albums,
+		comments: comments,
+		photos:   photos,
+		todos:    expand.NewResolver[data.Todo]().Add("user", expand.BelongsTo(func(t data.Todo) int { return t.UserID }, svc.users.GetUser, nil)),
 	}
 }
 
-func albumRouter(albumHandler *ah.AlbumHandler) http.Handler {
-	r := chi.NewRouter()
-	r.With(paginate).Get("/", albumHandler.GetAlbums)
-	r.Post("/", albumHandler.CreateAlbum)
-	r.Route("/{postID}", func(r chi.Router) {
-		r.Get("/", albumHandler.GetAlbum)
-		r.Put("/", albumHandler.UpdateAlbum)
-		r.Delete("/", albumHandler.DeleteAlbum)
-		r.Patch("/", albumHandler.PatchAlbum)
-	})
-	return r
+// cacheOptions devuelve la opción de caché de respuestas de cada recurso
+func cacheOptions(cfg *config.Config) func(resource string) rs.Option {
+	responseCache := cache.NewLRU(cfg.Cache.Size)
+	return func(resource string) rs.Option {
+		return rs.WithCache(responseCache, cache.Policy{TTL: cfg.CacheTTL(resource), Stale: cfg.Cache.Stale})
+	}
 }
 
-func postRouter(postHandler *ph.PostHandler) http.Handler {
-	r := chi.NewRouter()
-	r.With(paginate).Get("/", postHandler.GetPosts)
-	r.Post("/", postHandler.CreatePost)
-	r.Route("/{postID}", func(r chi.Router) {
-		r.Get("/", postHandler.GetPost)
-		r.Put("/", postHandler.UpdatePost)
-		r.Delete("/", postHandler.DeletePost)
-		r.Patch("/", postHandler.PatchPost)
-	})
-	return r
+// proxyServices crea los servicios que reenvían al upstream, con caché de respuestas
+func proxyServices(restClient restclient.IRestClient, cfg *config.Config) services {
+	cached := cacheOptions(cfg)
+	return services{
+		posts:    ps.NewPostService(restClient, cfg.Upstream("posts"), cached("posts")),
+		albums:   as.NewAlbumService(restClient, cfg.Upstream("albums"), cached("albums")),
+		comments: cs.NewCommentService(restClient, cfg.Upstream("comments"), cached("comments")),
+		photos:   phs.NewPhotoService(restClient, cfg.Upstream("photos"), cached("photos")),
+		todos:    ts.NewTodoService(restClient, cfg.Upstream("todos"), cached("todos")),
+		users:    us.NewUserService(restClient, cfg.Upstream("users"), cached("users")),
+	}
 }
 
-func commentRouter(commentHandler *ch.CommentHandler) http.Handler {
-	r := chi.NewRouter()
-	r.With(paginate).Get("/", commentHandler.GetComments)
-	r.Post("/", commentHandler.CreateComment)
-	r.Route("/{postID}", func(r chi.Router) {
-		r.Get("/", commentHandler.GetComment)
-		r.Put("/", commentHandler.UpdateComment)
-		r.Delete("/", commentHandler.DeleteComment)
-		r.Patch("/", commentHandler.PatchComment)
-	})
-	return r
+// localServices crea los servicios que guardan en la base de datos local
+func localServices(db *sql.DB, dialect repository.Dialect) services {
+	return services{
+		posts:    ps.NewPostLocalService(repository.NewPostRepository(db, dialect)),
+		albums:   as.NewAlbumLocalService(repository.NewAlbumRepository(db, dialect)),
+		comments: cs.NewCommentLocalService(repository.NewCommentRepository(db, dialect)),
+		photos:   phs.NewPhotoLocalService(repository.NewPhotoRepository(db, dialect)),
+		todos:    ts.NewTodoLocalService(repository.NewTodoRepository(db, dialect)),
+		users:    us.NewUserLocalService(repository.NewUserRepository(db, dialect)),
+	}
 }
 
-func todoRouter(todoHandler *th.TodoHandler) http.Handler {
-	r := chi.NewRouter()
-	r.With(paginate).Get("/", todoHandler.GetTodos)
-	r.Post("/", todoHandler.CreateTodo)
-	r.Route("/{postID}", func(r chi.Router) {
-		r.Get("/", todoHandler.GetTodo)
-		r.Put("/", todoHandler.UpdateTodo)
-		r.Delete("/", todoHandler.DeleteTodo)
-		r.Patch("/", todoHandler.PatchTodo)
-	})
-	return r
+// overlayServices crea los servicios que leen del upstream y guardan las escrituras en store
+func overlayServices(restClient restclient.IRestClient, cfg *config.Config, store *repository.OverlayStore) services {
+	cached := cacheOptions(cfg)
+	return services{
+		posts:    ps.NewPostOverlayService(restClient, cfg.Upstream("posts"), store, cached("posts")),
+		albums:   as.NewAlbumOverlayService(restClient, cfg.Upstream("albums"), store, cached("albums")),
+		comments: cs.NewCommentOverlayService(restClient, cfg.Upstream("comments"), store, cached("comments")),
+		photos:   phs.NewPhotoOverlayService(restClient, cfg.Upstream("photos"), store, cached("photos")),
+		todos:    ts.NewTodoOverlayService(restClient, cfg.Upstream("todos"), store, cached("todos")),
+		users:    us.NewUserOverlayService(restClient, cfg.Upstream("users"), store, cached("users")),
+	}
 }
-func userRouter(userHandler *uh.UserHandler) http.Handler {
-	r := chi.NewRouter()
-	r.With(paginate).Get("/", userHandler.GetUsers)
-	r.Post("/", userHandler.CreateUser)
-	r.Route("/{postID}", func(r chi.Router) {
-		r.Get("/", userHandler.GetUser)
-		r.Put("/", userHandler.UpdateUser)
-		r.Delete("/", userHandler.DeleteUser)
-		r.Patch("/", userHandler.PatchUser)
-	})
-	return r
+
+// openDatabase abre la base de datos de los modos local y overlay
+func openDatabase(database config.Database) (*sql.DB, repository.Dialect, error) {
+	dialect, err := repository.DialectFor(database.Driver)
+	if err != nil {
+		return nil, dialect, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	db, err := repository.Open(ctx, dialect, database.DSN)
+	return db, dialect, err
 }
 
-// paginate is a stub, but very possible to implement middleware logic
-// to handle the request params for handling a paginated request.
-func paginate(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// just a stub.. some ideas are to look at URL query params for something like
-		// the page number, or the limit, and send a query cursor down the chain
-		next.ServeHTTP(w, r)
-	})
+// checkSchema falla si la base de datos tiene migraciones sin aplicar
+func checkSchema(db *sql.DB, dialect repository.Dialect) error {
+	migrator, err := repository.NewMigrator(db, dialect)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return migrator.Check(ctx)
+}
+
+// clientOptions aplica los tiempos de espera, reintentos y el circuit breaker configurados sobre los valores por defecto del cliente
+func clientOptions(cfg *config.Config) restclient.Options {
+	options := restclient.DefaultOptions()
+	if cfg.UpstreamTimeouts.Connect > 0 {
+		options.ConnectTimeout = cfg.UpstreamTimeouts.Connect
+	}
+	if cfg.UpstreamTimeouts.Read > 0 {
+		options.ReadTimeout = cfg.UpstreamTimeouts.Read
+	}
+	if cfg.UpstreamTimeouts.Total > 0 {
+		options.Timeout = cfg.UpstreamTimeouts.Total
+	}
+	if cfg.UpstreamRetry.Attempts > 0 {
+		options.Retry.MaxAttempts = cfg.UpstreamRetry.Attempts
+	}
+	if cfg.UpstreamRetry.BaseDelay > 0 {
+		options.Retry.BaseDelay = cfg.UpstreamRetry.BaseDelay
+	}
+	if cfg.UpstreamRetry.MaxDelay > 0 {
+		options.Retry.MaxDelay = cfg.UpstreamRetry.MaxDelay
+	}
+	if cfg.UpstreamBreaker.Threshold > 0 {
+		options.Breaker.FailureThreshold = cfg.UpstreamBreaker.Threshold
+	}
+	if cfg.UpstreamBreaker.CoolDown > 0 {
+		options.Breaker.CoolDown = cfg.UpstreamBreaker.CoolDown
+	}
+	return options
+}
+
+func apiVersionCtx(version string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = r.WithContext(context.WithValue(r.Context(), "api.version", version))
+			next.ServeHTTP(w, r)
+		})
+	}
 }
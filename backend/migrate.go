@@ -0,0 +1,82 @@
+package main
+
+import (
+	"blog-api/app/config"
+	"blog-api/app/repository"
+	"context"
+	"fmt"
+	"os"
+)
+
+const migrateUsage = `usage: backend migrate <command>
+
+commands:
+  up      apply all pending migrations
+  down    revert the last applied migration
+  redo    revert and re-apply the last applied migration
+  status  list migrations and when they were applied`
+
+// runMigrate ejecuta el subcomando migrate sobre la base de datos configurada
+// y devuelve el código de salida del proceso
+func runMigrate(database config.Database, args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+	db, dialect, err := openDatabase(database)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "database unavailable:", err)
+		return 1
+	}
+	defer db.Close()
+	migrator, err := repository.NewMigrator(db, dialect)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("applied  %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("schema is up to date")
+		}
+		return exitCode(err)
+	case "down", "redo":
+		run, verb := migrator.Down, "reverted"
+		if args[0] == "redo" {
+			run, verb = migrator.Redo, "redone  "
+		}
+		migration, err := run(ctx)
+		if migration != nil {
+			fmt.Printf("%s %04d_%s\n", verb, migration.Version, migration.Name)
+		} else if err == nil {
+			fmt.Println("no migrations applied")
+		}
+		return exitCode(err)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-30s %s\n", status.Version, status.Name, applied)
+		}
+		return exitCode(err)
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+}
+
+func exitCode(err error) int {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}